@@ -0,0 +1,99 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// Admitter is protocol definition for admission
+// policies guarding the cache against insertions
+// that would displace more valuable enteries. They
+// see hashes of accessed keys, never values, and
+// decide whether a new key (`candidate`) is worth
+// evicting the eviction victim (`victim`) for.
+type Admitter interface {
+	Record(hash uint64)
+	Admit(candidate, victim uint64) bool
+}
+
+// tinyLFU is an `Admitter` admitting candidates
+// estimated to be accessed more often than the
+// victim.
+type tinyLFU struct {
+	sketch *countMin
+}
+
+// - MARK: Alloc/Init section.
+
+// NewTinyLFU returns a TinyLFU admission policy
+// estimating access frequencies of about `size`
+// distinct keys. Frequencies age over time so
+// that formerly popular keys are displaced once
+// they cool down.
+func NewTinyLFU(size int) Admitter {
+	return &tinyLFU{newCountMin(size)}
+}
+
+// WithAdmitter attaches admission policy `a`. It
+// records lookups and writes of new keys, and once
+// the cache is full, writes of new keys that `a`
+// deems less valuable than the eviction victim
+// fail with `ELRUNOTADMITTED` ( loaded values are
+// served but not cached ). Updates of cached and
+// evictions enforcing the cost limit aren't
+// subject to admission.
+func WithAdmitter(a Admitter) Option {
+	return func(lru *LRU) {
+		lru.opts.admitter = a
+	}
+}
+
+// - MARK: tinyLFU section.
+
+// Record conforms to `Admitter`.
+func (t *tinyLFU) Record(hash uint64) {
+	t.sketch.add(hash)
+}
+
+// Admit conforms to `Admitter`.
+func (t *tinyLFU) Admit(candidate, victim uint64) bool {
+	return t.sketch.estimate(candidate) > t.sketch.estimate(victim)
+}
+
+// - MARK: LRU section.
+
+// admitted records a write of new `key` and
+// returns whether it may displace `victim`.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) admitted(key interface{}, victim *list.Element) bool {
+	var (
+		h uint64 = hashKey(key)
+	)
+	lru.opts.admitter.Record(h)
+	if victim == nil || lru.opts.admitter.Admit(h, hashKey(victim.Value.(*LRUItem).Key)) {
+		return true
+	}
+	lru.opts.stats.Denied++
+	return false
+}
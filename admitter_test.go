@@ -0,0 +1,57 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestLRUAdmitter(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(2, WithAdmitter(NewTinyLFU(64)))
+	)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	for i := 0; i < 3; i++ {
+		lru.Get("a")
+		lru.Get("b")
+	}
+	// one-hit wonders don't displace popular enteries
+	if _, err := lru.Set("c", 3); err != ELRUNOTADMITTED {
+		t.Fatal("assertion failed, expected insertion to be denied.", err)
+	}
+	if _, err := lru.Set("a", 10); err != nil || lru.Len() != 2 || lru.Read("a") != 10 {
+		t.Fatal("assertion failed, expected updates to be admitted.", err)
+	}
+	for i := 0; i < 3; i++ {
+		lru.Get("c")
+	}
+	// "c" is now looked up more often than victim "b"
+	if isNew, err := lru.Set("c", 3); !isNew || err != nil {
+		t.Fatal("assertion failed, expected insertion to be admitted.", err)
+	}
+	if lru.Read("a") != 10 || lru.Read("b") != nil || lru.Read("c") != 3 {
+		t.Fatal("assertion failed, inconsistent state. expected victim to be evicted.")
+	}
+	if stats := lru.Stats(); stats.Denied != 1 {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 1, stats.Denied)
+	}
+}
@@ -0,0 +1,82 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// - MARK: LRU section.
+
+// Alias makes `aliasKey` resolve to the entery
+// stored under `canonicalKey`; both keys share a
+// single copy of the value and its recency. Writes
+// through the alias update the shared entery and
+// the alias is dropped when the entery leaves the
+// cache. An existing entery stored under `aliasKey`
+// is replaced. It returns `false` when no entery
+// with `canonicalKey` exists.
+func (lru *LRU) Alias(aliasKey interface{}, canonicalKey interface{}) (ok bool) {
+	var (
+		elem    *list.Element
+		prev    *list.Element
+		item    *LRUItem
+		aliases map[interface{}]struct{}
+	)
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if elem = lru.readEntery(canonicalKey); elem == nil {
+		return false
+	}
+	// resolve aliases of aliases to their canonical key
+	item = elem.Value.(*LRUItem)
+	if item.Key == aliasKey {
+		return true
+	}
+	if prev = lru.readEntery(aliasKey); prev != nil {
+		lru.remove(aliasKey)
+	}
+	if aliases, ok = lru.opts.aliases[item.Key]; !ok {
+		aliases = make(map[interface{}]struct{})
+		lru.opts.aliases[item.Key] = aliases
+	}
+	aliases[aliasKey] = struct{}{}
+	lru.lookup[aliasKey] = elem
+	return true
+}
+
+// unalias removes `alias` of `canonical`. Note,
+// this routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) unalias(alias interface{}, canonical interface{}) {
+	var (
+		aliases map[interface{}]struct{}
+		ok      bool
+	)
+	delete(lru.lookup, alias)
+	if aliases, ok = lru.opts.aliases[canonical]; !ok {
+		return
+	}
+	delete(aliases, alias)
+	if len(aliases) == 0 {
+		delete(lru.opts.aliases, canonical)
+	}
+}
@@ -0,0 +1,60 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestLRUAlias(t *testing.T) {
+	var (
+		lru   *LRU = NewLRU(8)
+		value interface{}
+	)
+	if lru.Alias("slug:alice", "id:1") {
+		t.Fatal("assertion failed, expected false for missing canonical key.")
+	}
+	lru.Set("id:1", "alice")
+	lru.Set("id:2", "bob")
+	if !lru.Alias("slug:alice", "id:1") || !lru.Alias("url:/alice", "slug:alice") {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if value, _ = lru.Get("url:/alice"); value != "alice" || lru.Len() != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	if lru.items.Front().Value.(*LRUItem).Key != "id:1" {
+		t.Fatal("assertion failed, expected shared recency.")
+	}
+	// writes through an alias update the shared entery
+	lru.Set("slug:alice", "alice2")
+	if lru.Read("id:1") != "alice2" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	// removing an alias keeps the entery
+	if !lru.Remove("slug:alice") || lru.Read("slug:alice") != nil || lru.Read("id:1") != "alice2" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	// removing the entery drops remaining aliases
+	lru.Remove("id:1")
+	if lru.Read("url:/alice") != nil || len(lru.lookup) != 1 || len(lru.opts.aliases) != 0 {
+		t.Fatal("assertion failed, expected aliases to be released.")
+	}
+}
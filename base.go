@@ -23,12 +23,33 @@
 // Package cache provides common caching facilities.
 package cache
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // Error messages
 var (
-	ELRUINVALTYPE error = errors.New("cache(lru): invalid item type.")
-	ELRUFATAL     error = errors.New("cache(lru): fatal state.")
+	ELRUINVALTYPE   error = errors.New("cache(lru): invalid item type.")
+	ELRUFATAL       error = errors.New("cache(lru): fatal state.")
+	ELRUNOCLOCK     error = errors.New("cache(lru): no hybrid logical clock configured.")
+	ELRUCORRUPT     error = errors.New("cache(lru): inconsistent internal state.")
+	ELRULOADLIMIT   error = errors.New("cache(lru): too many concurrent loads.")
+	ELRUWARMING     error = errors.New("cache(lru): cache is warming up.")
+	ELRUMISS        error = errors.New("cache(lru): key not found.")
+	ELRUSHORT       error = errors.New("cache(lru): stream is shorter than its size.")
+	ELRURELEASED    error = errors.New("cache(lru): value was released.")
+	ELRUSNAPSHOT    error = errors.New("cache(lru): unsupported snapshot version.")
+	ELRULEASED      error = errors.New("cache(lru): key is leased.")
+	ELRUNILVALUE    error = errors.New("cache(lru): nil value.")
+	ELRUNOTADMITTED error = errors.New("cache(lru): insertion not admitted.")
+	ELRUPANICKED    error = errors.New("cache(lru): load panicked.")
+	// ErrCachedError is matched by errors served
+	// from the loader error cache.
+	ErrCachedError error = errors.New("cache(lru): cached loader error.")
+	// ErrInvalidValueType is matched by errors of
+	// writes violating `WithValueType`.
+	ErrInvalidValueType error = errors.New("cache(lru): invalid value type.")
 )
 
 // CacheInterface is protocol definition that
@@ -42,6 +63,12 @@ type CacheInterface interface {
 	Len() int
 }
 
+// Remover is protocol definition for caches
+// that support removal of individual enteries.
+type Remover interface {
+	Remove(interface{}) bool
+}
+
 // CacheItemInterface is protocol definition
 // for indiviudal items in cache lines that
 // must be conformed.
@@ -50,3 +77,30 @@ type CacheItemInterface interface {
 	V() interface{}
 	C() int
 }
+
+// Loader is protocol definition for data
+// sources that back a read-through cache.
+type Loader interface {
+	Load(interface{}) (interface{}, error)
+}
+
+// LoaderWithTTL is protocol definition for
+// data sources that dictate freshness of
+// each loaded item.
+type LoaderWithTTL interface {
+	LoadWithTTL(interface{}) (interface{}, time.Duration, error)
+}
+
+// Releasable is protocol definition for values
+// holding resources ( e.g. pooled buffers ) that
+// must be released once no longer referenced; see
+// `WithRefCounting`.
+type Releasable interface {
+	Release()
+}
+
+// Clock is protocol definition for time
+// sources used to compute expiry and ages.
+type Clock interface {
+	Now() time.Time
+}
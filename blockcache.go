@@ -0,0 +1,152 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"errors"
+	"io"
+)
+
+// Error messages
+var (
+	ELRUOFFSET error = errors.New("cache(blockcache): negative offset.")
+)
+
+// Defaults
+const (
+	defaultBLOCKSIZE = 64 << 10
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ io.ReaderAt = (*BlockCache)(nil)
+)
+
+// BlockCache is an `io.ReaderAt` caching fixed-size
+// blocks of another one in an `LRU`, for random
+// reads over remote object storage or large files.
+// Blocks are immutable once cached; the underlying
+// data is assumed not to change.
+type BlockCache struct {
+	r         io.ReaderAt
+	blockSize int64
+	readAhead int64
+	lru       *LRU
+}
+
+// - MARK: Alloc/Init section.
+
+// NewBlockCache allocates and initializes a new
+// `BlockCache` struct reading from `r` in blocks of
+// `blockSize` bytes, keeping up to `blocks` blocks
+// and returns a pointer to it. Misses fetch the
+// missing block along with the `readAhead` blocks
+// following it in a single read. Note, when
+// `blockSize <= 0` holds true, it's set to
+// `defaultBLOCKSIZE` ( by default 64KiB ).
+func NewBlockCache(r io.ReaderAt, blockSize int, blocks int, readAhead int) *BlockCache {
+	if blockSize <= 0 {
+		blockSize = defaultBLOCKSIZE
+	}
+	if readAhead < 0 {
+		readAhead = 0
+	}
+	return &BlockCache{r: r, blockSize: int64(blockSize), readAhead: int64(readAhead), lru: NewLRU(blocks)}
+}
+
+// - MARK: BlockCache section.
+
+// ReadAt conforms to `io.ReaderAt`.
+func (bc *BlockCache) ReadAt(p []byte, off int64) (n int, err error) {
+	var (
+		block []byte
+		start int64
+	)
+	if off < 0 {
+		return 0, ELRUOFFSET
+	}
+	for n < len(p) {
+		if block, err = bc.block(off / bc.blockSize); err != nil {
+			return n, err
+		}
+		start = off % bc.blockSize
+		if start >= int64(len(block)) {
+			return n, io.EOF
+		}
+		c := copy(p[n:], block[start:])
+		n += c
+		off += int64(c)
+		if int64(len(block)) < bc.blockSize && n < len(p) {
+			// short block marks end of data
+			return n, io.EOF
+		}
+	}
+	return n, nil
+}
+
+// Len returns number of cached blocks.
+func (bc *BlockCache) Len() int {
+	return bc.lru.Len()
+}
+
+// block returns block `idx`, fetching it along with
+// read-ahead blocks on a miss.
+func (bc *BlockCache) block(idx int64) (block []byte, err error) {
+	var (
+		buf   []byte
+		n     int
+		count int64
+	)
+	if v, _ := bc.lru.Get(idx); v != nil {
+		return v.([]byte), nil
+	}
+	for count = 1; count <= bc.readAhead; count++ {
+		if bc.lru.Read(idx+count) != nil {
+			break
+		}
+	}
+	buf = make([]byte, count*bc.blockSize)
+	if n, err = bc.r.ReadAt(buf, idx*bc.blockSize); err != nil && err != io.EOF {
+		return nil, err
+	}
+	buf = buf[:n]
+	for i := int64(0); i < count; i++ {
+		var (
+			lo int64 = i * bc.blockSize
+			hi int64 = lo + bc.blockSize
+		)
+		if lo > int64(n) || (lo == int64(n) && i > 0) {
+			break
+		}
+		if hi > int64(n) {
+			hi = int64(n)
+		}
+		// full-capacity slices would let appends of
+		// one block overwrite the next
+		bc.lru.Set(idx+i, buf[lo:hi:hi])
+		if i == 0 {
+			block = buf[lo:hi:hi]
+		}
+	}
+	return block, nil
+}
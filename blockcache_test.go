@@ -0,0 +1,89 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// countingReaderAt counts `ReadAt` calls.
+type countingReaderAt struct {
+	r     io.ReaderAt
+	reads int
+}
+
+// ReadAt conforms to `io.ReaderAt`.
+func (cr *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	cr.reads++
+	return cr.r.ReadAt(p, off)
+}
+
+func TestBlockCache(t *testing.T) {
+	var (
+		data []byte            = []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+		cr   *countingReaderAt = &countingReaderAt{r: bytes.NewReader(data)}
+		bc   *BlockCache       = NewBlockCache(cr, 8, 4, 1)
+		buf  []byte            = make([]byte, 10)
+	)
+	if n, err := bc.ReadAt(buf, 3); n != 10 || err != nil || string(buf) != "3456789abc" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n, err, string(buf))
+	}
+	if cr.reads != 1 || bc.Len() != 2 {
+		t.Fatal("assertion failed, expected read-ahead.", cr.reads, bc.Len())
+	}
+	bc.ReadAt(buf[:4], 12)
+	if cr.reads != 1 {
+		t.Fatal("assertion failed, expected cached block.", cr.reads)
+	}
+	if n, err := bc.ReadAt(buf, 30); n != 6 || err != io.EOF || string(buf[:n]) != "uvwxyz" {
+		t.Fatal("assertion failed, expected short read at end.", n, err, string(buf[:n]))
+	}
+	if n, err := bc.ReadAt(buf, 40); n != 0 || err != io.EOF {
+		t.Fatal("assertion failed, expected EOF past end.", n, err)
+	}
+	// compare against the source over every offset
+	for off := int64(0); off < int64(len(data)); off++ {
+		expected := make([]byte, 7)
+		en, eerr := bytes.NewReader(data).ReadAt(expected, off)
+		got := make([]byte, 7)
+		gn, gerr := bc.ReadAt(got, off)
+		if en != gn || eerr != gerr || !bytes.Equal(expected[:en], got[:gn]) {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", off, gn, gerr)
+		}
+	}
+}
+
+func TestBlockCacheDefaults(t *testing.T) {
+	var (
+		bc  *BlockCache = NewBlockCache(bytes.NewReader([]byte("data")), 0, 4, 0)
+		buf []byte      = make([]byte, 4)
+	)
+	if n, err := bc.ReadAt(buf, 0); n != 4 || string(buf) != "data" || (err != nil && err != io.EOF) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n, err)
+	}
+	if _, err := bc.ReadAt(buf, -1); err != ELRUOFFSET {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+}
@@ -0,0 +1,83 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+// - MARK: LRU section.
+
+// RemoveFunc removes every entery for which `pred`
+// returns true in a single pass under the lock and
+// returns the number of removed enteries. Aliases
+// of removed enteries are removed with them. Note,
+// `pred` must not call back into the cache.
+func (lru *LRU) RemoveFunc(pred func(key, value interface{}) bool) (n int) {
+	var (
+		next *list.Element
+		item *LRUItem
+	)
+	lru.mu.Lock()
+	for elem := lru.items.Front(); elem != nil; elem = next {
+		next = elem.Next()
+		if item = elem.Value.(*LRUItem); pred(item.Key, item.Value) {
+			lru.remove(item.Key)
+			n++
+		}
+	}
+	if lru.opts.readmit != nil {
+		lru.opts.readmit.dropFunc(pred)
+	}
+	lru.debugValidate()
+	lru.mu.Unlock()
+	return n
+}
+
+// ExpireFunc rewrites the expiry of every entery
+// for which `pred` returns true to `ttl` from now
+// and returns the number of matching enteries. A
+// non-positive `ttl` makes matching enteries never
+// expire. Matching enteries leave their tiers (
+// see `WithTiers` ) and stop sliding ( see
+// `SetWithIdleTTL` ). Note, `pred` must not call
+// back into the cache.
+func (lru *LRU) ExpireFunc(pred func(key, value interface{}) bool, ttl time.Duration) (n int) {
+	var (
+		item    *LRUItem
+		expires int64
+	)
+	lru.mu.Lock()
+	expires = lru.deadline(ttl)
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		if item = elem.Value.(*LRUItem); pred(item.Key, item.Value) {
+			item.expires = expires
+			lru.scheduleExpiry(item)
+			lru.fixExpiry(item.Key)
+			n++
+		}
+	}
+	lru.mu.Unlock()
+	return n
+}
@@ -0,0 +1,79 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRURemoveFunc(t *testing.T) {
+	var (
+		lru     *LRU = NewLRU(16)
+		removed []interface{}
+	)
+	lru.opts.onEvict = func(key, value interface{}, reason EvictReason) {
+		if reason != EvictREMOVED {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", reason)
+		}
+		removed = append(removed, key)
+	}
+	for i := 0; i < 10; i++ {
+		lru.Set(i, i)
+	}
+	lru.Alias("even", 4)
+	if n := lru.RemoveFunc(func(key, value interface{}) bool { return value.(int)%2 == 0 }); n != 5 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n)
+	}
+	if lru.Len() != 5 || len(removed) != 5 || lru.Read(2) != nil || lru.Read("even") != nil || lru.Read(3) != 3 {
+		t.Fatal("assertion failed, inconsistent state.", lru.Len(), removed)
+	}
+	if err := lru.Validate(); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+}
+
+func TestLRUExpireFunc(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(16, WithClock(clock))
+	)
+	for i := 0; i < 4; i++ {
+		lru.Set2("users", i, i)
+	}
+	lru.SetWithTTL("other", 1, time.Hour)
+	isUser := func(key, value interface{}) bool {
+		ck, ok := key.(compositeKey)
+		return ok && ck.ns == "users"
+	}
+	if n := lru.ExpireFunc(isUser, time.Second); n != 4 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n)
+	}
+	clock.now = clock.now.Add(2 * time.Second)
+	if v, _ := lru.Get2("users", 1); v != nil {
+		t.Fatal("assertion failed, expected expired entery.", v)
+	}
+	if v, _ := lru.Get("other"); v != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v)
+	}
+}
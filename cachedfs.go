@@ -0,0 +1,158 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"io/fs"
+	"time"
+)
+
+// dirEnteryCOST is the estimated cost in bytes of
+// a cached directory entery.
+const dirEnteryCOST = 64
+
+// Ensure interface (protocol) conformance
+var (
+	_ fs.ReadFileFS = (*cachedFS)(nil)
+	_ fs.ReadDirFS  = (*cachedFS)(nil)
+	_ fs.StatFS     = (*cachedFS)(nil)
+)
+
+// cachedFS is a `fs.FS` caching file reads,
+// directory listings and stats of another one.
+type cachedFS struct {
+	inner fs.FS
+	c     CacheInterface
+	ttl   time.Duration
+}
+
+// fsKey is the cache key of a filesystem call of
+// `cfs`, so that filesystems sharing a cache never
+// serve each other's files.
+type fsKey struct {
+	cfs  *cachedFS
+	op   byte
+	name string
+}
+
+// Filesystem calls
+const (
+	fsREADFILE byte = iota
+	fsREADDIR
+	fsSTAT
+)
+
+// - MARK: Alloc/Init section.
+
+// CachedFS returns a `fs.FS` caching results of
+// `ReadFile`, `ReadDir` and `Stat` calls on `inner`
+// in `c` for `ttl` ( when `c` supports `SetWithTTL` )
+// to accelerate embedded or network filesystems.
+// `Open` is passed through. Errors are not cached.
+// See `FSCost` for size-aware cost accounting.
+func CachedFS(inner fs.FS, c CacheInterface, ttl time.Duration) fs.FS {
+	return &cachedFS{inner: inner, c: c, ttl: ttl}
+}
+
+// FSCost is a cost function for `WithCost` that
+// charges cached file contents by size in bytes
+// and directory listings by number of enteries.
+func FSCost(key, value interface{}) int64 {
+	switch v := value.(type) {
+	case []byte:
+		return int64(len(v))
+	case []fs.DirEntry:
+		return int64(len(v)) * dirEnteryCOST
+	}
+	return dirEnteryCOST
+}
+
+// - MARK: cachedFS section.
+
+// Open conforms to `fs.FS`.
+func (cfs *cachedFS) Open(name string) (fs.File, error) {
+	return cfs.inner.Open(name)
+}
+
+// ReadFile conforms to `fs.ReadFileFS`. The returned
+// slice is a copy owned by the caller.
+func (cfs *cachedFS) ReadFile(name string) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+		ok   bool
+	)
+	if data, ok = cfs.cached(fsKey{cfs, fsREADFILE, name}).([]byte); !ok {
+		if data, err = fs.ReadFile(cfs.inner, name); err != nil {
+			return nil, err
+		}
+		cfs.store(fsKey{cfs, fsREADFILE, name}, data)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// ReadDir conforms to `fs.ReadDirFS`.
+func (cfs *cachedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var (
+		entries []fs.DirEntry
+		err     error
+		ok      bool
+	)
+	if entries, ok = cfs.cached(fsKey{cfs, fsREADDIR, name}).([]fs.DirEntry); !ok {
+		if entries, err = fs.ReadDir(cfs.inner, name); err != nil {
+			return nil, err
+		}
+		cfs.store(fsKey{cfs, fsREADDIR, name}, entries)
+	}
+	return append([]fs.DirEntry(nil), entries...), nil
+}
+
+// Stat conforms to `fs.StatFS`.
+func (cfs *cachedFS) Stat(name string) (info fs.FileInfo, err error) {
+	if info, ok := cfs.cached(fsKey{cfs, fsSTAT, name}).(fs.FileInfo); ok {
+		return info, nil
+	}
+	if info, err = fs.Stat(cfs.inner, name); err != nil {
+		return nil, err
+	}
+	cfs.store(fsKey{cfs, fsSTAT, name}, info)
+	return info, nil
+}
+
+// cached returns the cached result of `key`;
+// callers treat values of other types as misses.
+func (cfs *cachedFS) cached(key fsKey) interface{} {
+	if v, err := cfs.c.Get(key); err == nil {
+		return v
+	}
+	return nil
+}
+
+// store caches `value` as result of `key`.
+func (cfs *cachedFS) store(key fsKey, value interface{}) {
+	if ts, ok := cfs.c.(ttlSetter); ok && cfs.ttl > 0 {
+		ts.SetWithTTL(key, value, cfs.ttl)
+		return
+	}
+	cfs.c.Set(key, value)
+}
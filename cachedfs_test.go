@@ -0,0 +1,90 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// countingFS counts `Open` calls of its wrapped
+// filesystem.
+type countingFS struct {
+	fs.FS
+	opens int
+}
+
+// Open conforms to `fs.FS`.
+func (cfs *countingFS) Open(name string) (fs.File, error) {
+	cfs.opens++
+	return cfs.FS.Open(name)
+}
+
+func TestCachedFS(t *testing.T) {
+	var (
+		inner *countingFS = &countingFS{FS: fstest.MapFS{
+			"static/app.js":  {Data: []byte("console.log(1)")},
+			"static/app.css": {Data: []byte("body{}")},
+		}}
+		lru  *LRU  = NewLRU(16, WithCost(FSCost, 1<<20))
+		cfs  fs.FS = CachedFS(inner, lru, time.Minute)
+		data []byte
+		err  error
+	)
+	for i := 0; i < 3; i++ {
+		if data, err = fs.ReadFile(cfs, "static/app.js"); string(data) != "console.log(1)" || err != nil {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", string(data), err)
+		}
+		if entries, err := fs.ReadDir(cfs, "static"); len(entries) != 2 || err != nil {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", entries, err)
+		}
+	}
+	opens := inner.opens
+	data[0] = 'X'
+	if data, _ = fs.ReadFile(cfs, "static/app.js"); data[0] != 'c' || inner.opens != opens {
+		t.Fatal("assertion failed, expected cached copy.", string(data), inner.opens)
+	}
+	if lru.Cost() != int64(len("console.log(1)"))+2*dirEnteryCOST {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", lru.Cost())
+	}
+	if _, err = fs.ReadFile(cfs, "missing"); err == nil || lru.Len() != 2 {
+		t.Fatal("assertion failed, expected uncached error.", err, lru.Len())
+	}
+	if err = fstest.TestFS(cfs, "static/app.js", "static/app.css"); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+}
+
+func TestCachedFSSharedCache(t *testing.T) {
+	var (
+		lru *LRU  = NewLRU(16)
+		a   fs.FS = CachedFS(fstest.MapFS{"f": {Data: []byte("a")}}, lru, 0)
+		b   fs.FS = CachedFS(fstest.MapFS{"f": {Data: []byte("b")}}, lru, 0)
+	)
+	fs.ReadFile(a, "f")
+	if data, err := fs.ReadFile(b, "f"); string(data) != "b" || err != nil {
+		t.Fatal("assertion failed, expected files of filesystems sharing a cache to be isolated.", string(data), err)
+	}
+}
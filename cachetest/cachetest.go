@@ -0,0 +1,189 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+// Package cachetest provides facilities for testing
+// caching logic under failure conditions.
+package cachetest
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mitghi/cache"
+)
+
+// Error messages
+var (
+	EINJECTED error = errors.New("cachetest: injected failure.")
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ cache.Clock  = (*FakeClock)(nil)
+	_ cache.Loader = (*CountingLoader)(nil)
+)
+
+// CountingLoader wraps a `cache.Loader` and counts
+// calls per key.
+type CountingLoader struct {
+	mu    sync.Mutex
+	inner cache.Loader
+	calls map[interface{}]int
+	total int64
+}
+
+// FakeClock is a manually driven `cache.Clock`.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// - MARK: Alloc/Init section.
+
+// NewCountingLoader allocates and initializes a new
+// `CountingLoader` wrapping `inner` and returns a
+// pointer to it.
+func NewCountingLoader(inner cache.Loader) *CountingLoader {
+	return &CountingLoader{inner: inner, calls: make(map[interface{}]int)}
+}
+
+// NewFakeClock allocates and initializes a new
+// `FakeClock` set to `now` and returns a pointer
+// to it.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// SlowLoader wraps `inner` in a loader that sleeps
+// for `latency` before each load.
+func SlowLoader(inner cache.Loader, latency time.Duration) cache.Loader {
+	return cache.LoaderFunc(func(key interface{}) (interface{}, error) {
+		time.Sleep(latency)
+		return inner.Load(key)
+	})
+}
+
+// FlakyLoader wraps `inner` in a loader that fails
+// with `EINJECTED` with probability `rate`. Failures
+// are reproducible for a given `seed`.
+func FlakyLoader(inner cache.Loader, rate float64, seed int64) cache.Loader {
+	var (
+		mu  sync.Mutex
+		rnd *rand.Rand = rand.New(rand.NewSource(seed))
+	)
+	return cache.LoaderFunc(func(key interface{}) (interface{}, error) {
+		mu.Lock()
+		fail := rnd.Float64() < rate
+		mu.Unlock()
+		if fail {
+			return nil, EINJECTED
+		}
+		return inner.Load(key)
+	})
+}
+
+// - MARK: CountingLoader section.
+
+// Load conforms to `cache.Loader`.
+func (cl *CountingLoader) Load(key interface{}) (interface{}, error) {
+	cl.mu.Lock()
+	cl.calls[key]++
+	cl.mu.Unlock()
+	atomic.AddInt64(&cl.total, 1)
+	return cl.inner.Load(key)
+}
+
+// Calls returns number of loads of `key`.
+func (cl *CountingLoader) Calls(key interface{}) (n int) {
+	cl.mu.Lock()
+	n = cl.calls[key]
+	cl.mu.Unlock()
+	return n
+}
+
+// Total returns number of loads of all keys.
+func (cl *CountingLoader) Total() int {
+	return int(atomic.LoadInt64(&cl.total))
+}
+
+// AssertCalls fails `t` unless `key` was loaded
+// exactly `want` times.
+func (cl *CountingLoader) AssertCalls(t testing.TB, key interface{}, want int) {
+	t.Helper()
+	if n := cl.Calls(key); n != want {
+		t.Fatalf("assertion failed, expected equal with value(%d) loads of key(%v) - got value(%d).", want, key, n)
+	}
+}
+
+// AssertOnce fails `t` unless `key` was loaded
+// exactly once.
+func (cl *CountingLoader) AssertOnce(t testing.TB, key interface{}) {
+	t.Helper()
+	cl.AssertCalls(t, key, 1)
+}
+
+// - MARK: FakeClock section.
+
+// Now conforms to `cache.Clock`.
+func (fc *FakeClock) Now() (now time.Time) {
+	fc.mu.Lock()
+	now = fc.now
+	fc.mu.Unlock()
+	return now
+}
+
+// Advance moves the clock forward by `d`; a
+// negative `d` simulates a backward clock skew.
+func (fc *FakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	fc.now = fc.now.Add(d)
+	fc.mu.Unlock()
+}
+
+// Set sets the clock to `now`.
+func (fc *FakeClock) Set(now time.Time) {
+	fc.mu.Lock()
+	fc.now = now
+	fc.mu.Unlock()
+}
+
+// SkewedClock returns a `cache.Clock` reading
+// `base` shifted by `skew`.
+func SkewedClock(base cache.Clock, skew time.Duration) cache.Clock {
+	return skewedClock{base, skew}
+}
+
+// skewedClock is a `cache.Clock` shifted by a
+// constant skew.
+type skewedClock struct {
+	base cache.Clock
+	skew time.Duration
+}
+
+// Now conforms to `cache.Clock`.
+func (sc skewedClock) Now() time.Time {
+	return sc.base.Now().Add(sc.skew)
+}
@@ -0,0 +1,90 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cachetest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mitghi/cache"
+)
+
+func TestStampedeLoadsOnce(t *testing.T) {
+	const workers int = 32
+	var (
+		loader *CountingLoader = NewCountingLoader(cache.LoaderFunc(func(key interface{}) (interface{}, error) {
+			return key, nil
+		}))
+		lru *cache.LRU = cache.NewLRU(8, cache.WithLoader(SlowLoader(loader, 10*time.Millisecond)))
+		wg  sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lru.Get("hot")
+		}()
+	}
+	wg.Wait()
+	loader.AssertOnce(t, "hot")
+	if loader.Total() != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", loader.Total())
+	}
+}
+
+func TestFlakyLoader(t *testing.T) {
+	var (
+		loader cache.Loader = FlakyLoader(cache.LoaderFunc(func(key interface{}) (interface{}, error) {
+			return key, nil
+		}), 0.5, 1)
+		failures int
+	)
+	for i := 0; i < 100; i++ {
+		if _, err := loader.Load(i); err == EINJECTED {
+			failures++
+		}
+	}
+	if failures == 0 || failures == 100 {
+		t.Fatal("assertion failed, expected partial failures.", failures)
+	}
+}
+
+func TestFakeClockExpiry(t *testing.T) {
+	var (
+		clock *FakeClock = NewFakeClock(time.Unix(0, 0))
+		lru   *cache.LRU = cache.NewLRU(8, cache.WithClock(clock))
+	)
+	lru.SetWithTTL("key", "value", time.Minute)
+	clock.Advance(59 * time.Second)
+	if lru.Read("key") != "value" {
+		t.Fatal("assertion failed, expected live entery.")
+	}
+	clock.Advance(time.Second)
+	if lru.Read("key") != nil {
+		t.Fatal("assertion failed, expected expired entery.")
+	}
+	if skewed := SkewedClock(clock, -time.Second); !skewed.Now().Equal(clock.Now().Add(-time.Second)) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+}
@@ -0,0 +1,221 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cachetest
+
+import (
+	"container/list"
+	"math/rand"
+	"testing"
+
+	"github.com/mitghi/cache"
+)
+
+// Operations replayed by `CheckOps`
+const (
+	opSET byte = iota
+	opGET
+	opREAD
+	opREMOVE
+	opPURGE
+	opLEN
+	opCOUNT
+)
+
+// Model is protocol definition for reference
+// models ( i.e. policy oracles ) that cache
+// implementations are checked against. Lookups
+// report presence explicitly instead of using
+// `nil` values.
+type Model interface {
+	Set(key, value interface{}) (isNew bool)
+	Get(key interface{}) (value interface{}, ok bool)
+	Read(key interface{}) (value interface{}, ok bool)
+	Remove(key interface{}) (ok bool)
+	Purge()
+	Len() int
+}
+
+// ModelConfig configures `CheckAgainstModel`.
+type ModelConfig struct {
+	Ops  int   // number of operations ( default 10000 )
+	Keys int   // size of the key space ( default 64 )
+	Seed int64 // seed of the operation sequence
+}
+
+// lruModel is the reference model of the
+// Least Recently Used policy; a plain map
+// plus a recency list.
+type lruModel struct {
+	capacity int
+	order    *list.List
+	values   map[interface{}]*list.Element
+}
+
+// lruModelEntry is an entery of `lruModel`.
+type lruModelEntry struct {
+	key, value interface{}
+}
+
+// - MARK: Alloc/Init section.
+
+// NewLRUModel returns the reference model of an
+// LRU cache holding at most `capacity` enteries.
+func NewLRUModel(capacity int) Model {
+	return &lruModel{
+		capacity: capacity,
+		order:    list.New(),
+		values:   make(map[interface{}]*list.Element),
+	}
+}
+
+// - MARK: Checker section.
+
+// CheckAgainstModel replays a random sequence of
+// operations, as configured by `cfg`, against both
+// `c` and `m` and fails `t` on the first diverging
+// result.
+func CheckAgainstModel(t testing.TB, c cache.CacheInterface, m Model, cfg ModelConfig) {
+	t.Helper()
+	if cfg.Ops <= 0 {
+		cfg.Ops = 10000
+	}
+	var (
+		rnd *rand.Rand = rand.New(rand.NewSource(cfg.Seed))
+		ops []byte     = make([]byte, cfg.Ops*2)
+	)
+	rnd.Read(ops)
+	CheckOps(t, c, m, ops, cfg.Keys)
+}
+
+// CheckOps is same as `CheckAgainstModel` except
+// that operations are decoded from `ops`, two bytes
+// per operation, which makes it suitable as body of
+// fuzz targets. Keys are drawn from `[0, keys)`.
+func CheckOps(t testing.TB, c cache.CacheInterface, m Model, ops []byte, keys int) {
+	t.Helper()
+	if keys <= 0 {
+		keys = 64
+	}
+	for i := 0; i+1 < len(ops); i += 2 {
+		var (
+			op  byte = ops[i] % opCOUNT
+			key int  = int(ops[i+1]) % keys
+			val int  = i
+		)
+		switch op {
+		case opSET:
+			isNew, err := c.Set(key, val)
+			if err != nil || isNew != m.Set(key, val) {
+				t.Fatalf("assertion failed, diverged at op(%d) Set(%d): got isNew(%v) err(%v).", i/2, key, isNew, err)
+			}
+		case opGET:
+			got, err := c.Get(key)
+			want, _ := m.Get(key)
+			if err != nil || got != want {
+				t.Fatalf("assertion failed, diverged at op(%d) Get(%d): got value(%v) - expected value(%v).", i/2, key, got, want)
+			}
+		case opREAD:
+			got := c.Read(key)
+			want, _ := m.Read(key)
+			if got != want {
+				t.Fatalf("assertion failed, diverged at op(%d) Read(%d): got value(%v) - expected value(%v).", i/2, key, got, want)
+			}
+		case opREMOVE:
+			r, ok := c.(cache.Remover)
+			if !ok {
+				continue
+			}
+			if got, want := r.Remove(key), m.Remove(key); got != want {
+				t.Fatalf("assertion failed, diverged at op(%d) Remove(%d): got value(%v) - expected value(%v).", i/2, key, got, want)
+			}
+		case opPURGE:
+			c.Purge()
+			m.Purge()
+		case opLEN:
+			if got, want := c.Len(), m.Len(); got != want {
+				t.Fatalf("assertion failed, diverged at op(%d) Len(): got value(%d) - expected value(%d).", i/2, got, want)
+			}
+		}
+	}
+	if got, want := c.Len(), m.Len(); got != want {
+		t.Fatalf("assertion failed, diverged at end Len(): got value(%d) - expected value(%d).", got, want)
+	}
+}
+
+// - MARK: lruModel section.
+
+// Set conforms to `Model`.
+func (m *lruModel) Set(key, value interface{}) bool {
+	if elem, ok := m.values[key]; ok {
+		elem.Value.(*lruModelEntry).value = value
+		m.order.MoveToFront(elem)
+		return false
+	}
+	if m.order.Len() >= m.capacity {
+		back := m.order.Remove(m.order.Back()).(*lruModelEntry)
+		delete(m.values, back.key)
+	}
+	m.values[key] = m.order.PushFront(&lruModelEntry{key, value})
+	return true
+}
+
+// Get conforms to `Model`.
+func (m *lruModel) Get(key interface{}) (interface{}, bool) {
+	elem, ok := m.values[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(elem)
+	return elem.Value.(*lruModelEntry).value, true
+}
+
+// Read conforms to `Model`.
+func (m *lruModel) Read(key interface{}) (interface{}, bool) {
+	elem, ok := m.values[key]
+	if !ok {
+		return nil, false
+	}
+	return elem.Value.(*lruModelEntry).value, true
+}
+
+// Remove conforms to `Model`.
+func (m *lruModel) Remove(key interface{}) bool {
+	elem, ok := m.values[key]
+	if !ok {
+		return false
+	}
+	m.order.Remove(elem)
+	delete(m.values, key)
+	return true
+}
+
+// Purge conforms to `Model`.
+func (m *lruModel) Purge() {
+	m.order.Init()
+	m.values = make(map[interface{}]*list.Element)
+}
+
+// Len conforms to `Model`.
+func (m *lruModel) Len() int {
+	return m.order.Len()
+}
@@ -0,0 +1,44 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cachetest
+
+import (
+	"testing"
+
+	"github.com/mitghi/cache"
+)
+
+func TestLRUAgainstModel(t *testing.T) {
+	for _, capacity := range []int{2, 8, 32} {
+		for seed := int64(0); seed < 4; seed++ {
+			CheckAgainstModel(t, cache.NewLRU(capacity), NewLRUModel(capacity), ModelConfig{Seed: seed, Keys: capacity * 2})
+		}
+	}
+}
+
+func FuzzLRUAgainstModel(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 1, 1, 0, 4, 5, 0})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		CheckOps(t, cache.NewLRU(4), NewLRUModel(4), ops, 8)
+	})
+}
@@ -0,0 +1,160 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cachetest
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/mitghi/cache"
+)
+
+// StressConfig configures `StressTest`. Operation
+// weights default to 60% Get, 30% Set, 9% Remove
+// and 1% Purge when all of them are zero.
+type StressConfig struct {
+	Workers  int   // concurrent goroutines ( default 8 )
+	Ops      int   // operations per goroutine ( default 10000 )
+	Keys     int   // size of the key space ( default 1024 )
+	Capacity int   // upper bound of `Len` to assert; disabled when zero
+	Seed     int64 // seed of the operation sequences
+
+	Get, Set, Remove, Purge int // operation weights
+}
+
+// validator is implemented by caches that can
+// check their own internal consistency.
+type validator interface {
+	Validate() error
+}
+
+// - MARK: Stress section.
+
+// StressTest runs the operation mix configured by
+// `cfg` against `c` from concurrent goroutines while
+// asserting invariants: `Len` never exceeds capacity
+// and, for caches with a `Validate() error` method,
+// internal structures stay consistent. It's meant to
+// be run with the race detector enabled.
+func StressTest(t testing.TB, c cache.CacheInterface, cfg StressConfig) {
+	t.Helper()
+	cfg.defaults()
+	var (
+		wg    sync.WaitGroup
+		errs  chan string = make(chan string, cfg.Workers+1)
+		done  chan struct{}
+		check func() string
+	)
+	check = func() string {
+		if l := c.Len(); cfg.Capacity > 0 && l > cfg.Capacity {
+			return "Len exceeds capacity"
+		}
+		if v, ok := c.(validator); ok {
+			if err := v.Validate(); err != nil {
+				return err.Error()
+			}
+		}
+		return ""
+	}
+	done = make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if msg := check(); msg != "" {
+				errs <- msg
+				return
+			}
+			// yield to workers on single threaded
+			// platforms ( e.g. js/wasm ) lacking
+			// preemption
+			runtime.Gosched()
+		}
+	}()
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			cfg.run(c, rand.New(rand.NewSource(seed)))
+		}(cfg.Seed + int64(w))
+	}
+	wg.Wait()
+	close(done)
+	if msg := check(); msg != "" {
+		errs <- msg
+	}
+	select {
+	case msg := <-errs:
+		t.Fatalf("assertion failed, invariant violated: %s.", msg)
+	default:
+	}
+}
+
+// defaults fills zero fields of `cfg`.
+func (cfg *StressConfig) defaults() {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 8
+	}
+	if cfg.Ops <= 0 {
+		cfg.Ops = 10000
+	}
+	if cfg.Keys <= 0 {
+		cfg.Keys = 1024
+	}
+	if cfg.Get+cfg.Set+cfg.Remove+cfg.Purge <= 0 {
+		cfg.Get, cfg.Set, cfg.Remove, cfg.Purge = 60, 30, 9, 1
+	}
+}
+
+// run performs one goroutine's share of the
+// operation mix.
+func (cfg *StressConfig) run(c cache.CacheInterface, rnd *rand.Rand) {
+	var (
+		total   int = cfg.Get + cfg.Set + cfg.Remove + cfg.Purge
+		remover cache.Remover
+	)
+	remover, _ = c.(cache.Remover)
+	for i := 0; i < cfg.Ops; i++ {
+		var (
+			pick int = rnd.Intn(total)
+			key  int = rnd.Intn(cfg.Keys)
+		)
+		switch {
+		case pick < cfg.Get:
+			c.Get(key)
+		case pick < cfg.Get+cfg.Set:
+			c.Set(key, i)
+		case pick < cfg.Get+cfg.Set+cfg.Remove:
+			if remover != nil {
+				remover.Remove(key)
+			}
+		default:
+			c.Purge()
+		}
+	}
+}
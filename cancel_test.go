@@ -0,0 +1,100 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func slowCtxLoader(release chan struct{}) LoaderCtx {
+	return LoaderCtxFunc(func(ctx context.Context, key interface{}) (interface{}, time.Duration, error) {
+		select {
+		case <-release:
+			return key, 0, nil
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		}
+	})
+}
+
+func TestLRULoadCancelAll(t *testing.T) {
+	var (
+		release chan struct{} = make(chan struct{})
+		lru     *LRU          = NewLRU(8, WithLoaderCtx(slowCtxLoader(release)))
+		errs    chan error    = make(chan error, 1)
+		ctx     context.Context
+		cancel  context.CancelFunc
+	)
+	defer close(release)
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		_, err := lru.GetCtx(ctx, "k")
+		errs <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := lru.Get("k")
+		waiter <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Fatal("assertion failed, expected cancelled load.", err)
+	}
+	if err := <-waiter; err != context.Canceled {
+		t.Fatal("assertion failed, expected waiters to share the cancellation.", err)
+	}
+}
+
+func TestLRULoadDetach(t *testing.T) {
+	var (
+		release chan struct{}    = make(chan struct{})
+		lru     *LRU             = NewLRU(8, WithLoaderCtx(slowCtxLoader(release)), WithLoadCancellation(LoadDETACH, time.Second))
+		waiter  chan interface{} = make(chan interface{}, 1)
+		ctx     context.Context
+		cancel  context.CancelFunc
+	)
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		value, _ := lru.Get("k")
+		waiter <- value
+	}()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := lru.GetCtx(ctx, "k"); err != context.Canceled {
+		t.Fatal("assertion failed, expected caller to give up.", err)
+	}
+	close(release)
+	if value := <-waiter; value != "k" {
+		t.Fatal("assertion failed, expected load delivered to remaining waiter.", value)
+	}
+	if lru.Read("k") != "k" {
+		t.Fatal("assertion failed, expected result of detached load to be cached.")
+	}
+}
@@ -0,0 +1,163 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// Capacity controller defaults
+const (
+	defaultCONTROLSTEP     = 0.1
+	defaultCONTROLINTERVAL = time.Minute
+)
+
+// CapacityController adjusts capacity within
+// `[Min, Max]` to meet a hit ratio of `Target`; see
+// `WithCapacityController`. `Max` defaults to the
+// initial capacity.
+type CapacityController struct {
+	Min, Max int
+	Target   float64       // desired hit ratio in (0, 1]
+	Step     float64       // relative change per adjustment, 0.1 by default
+	Interval time.Duration // time between adjustments, one minute by default
+}
+
+// controlState is the state of an attached
+// `CapacityController`.
+type controlState struct {
+	CapacityController
+	last      int64 // time of last adjustment
+	hits      uint64
+	misses    uint64
+	ghostHits uint64
+}
+
+// - MARK: Alloc/Init section.
+
+// WithCapacityController attaches `cc`. Once per
+// interval, on access, it compares the hit ratio
+// observed since the last adjustment to the target.
+// Below target, capacity grows when misses hit the
+// ghost list of recently evicted keys, i.e. when a
+// larger cache would have served them. Above target,
+// capacity shrinks while the surplus exceeds the
+// contribution of such accesses. It enables a
+// ghost list of `Max` keys unless `WithGhost` is
+// used.
+func WithCapacityController(cc CapacityController) Option {
+	return func(lru *LRU) {
+		if cc.Max <= 0 {
+			cc.Max = lru.capacity + 1
+		}
+		if cc.Step <= 0 {
+			cc.Step = defaultCONTROLSTEP
+		}
+		if cc.Interval <= 0 {
+			cc.Interval = defaultCONTROLINTERVAL
+		}
+		lru.opts.control = &controlState{CapacityController: cc}
+		if lru.opts.ghost == nil {
+			lru.opts.ghost = newGhost(cc.Max)
+		}
+	}
+}
+
+// - MARK: LRU section.
+
+// Capacity returns maximum number of enteries.
+func (lru *LRU) Capacity() (capacity int) {
+	lru.mu.Lock()
+	capacity = lru.capacity + 1
+	lru.mu.Unlock()
+	return capacity
+}
+
+// SetCapacity changes maximum number of enteries
+// to `capacity`, evicting least recently used
+// enteries that no longer fit. It is a no-op when
+// `capacity <= 0` holds true.
+func (lru *LRU) SetCapacity(capacity int) {
+	lru.mu.Lock()
+	lru.resize(capacity)
+	lru.mu.Unlock()
+}
+
+// resize is the unprotected variant of `SetCapacity`.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) resize(capacity int) {
+	if capacity <= 0 {
+		return
+	}
+	lru.capacity = capacity - 1
+	if lru.opts.mrc != nil {
+		lru.opts.mrc.resize(capacity)
+	}
+	for lru.items.Len() > capacity {
+		lru.evict()
+	}
+}
+
+// adapt runs the capacity controller when its
+// interval elapsed. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) adapt() {
+	var (
+		c         *controlState = lru.opts.control
+		now       int64         = lru.now()
+		hits      uint64
+		accesses  uint64
+		ghostHits uint64
+		ratio     float64
+		gain      float64
+		capacity  int = lru.capacity + 1
+		next      int
+	)
+	if c.last == 0 {
+		c.last = now
+	}
+	if now-c.last < int64(c.Interval) {
+		return
+	}
+	hits = lru.opts.stats.Hits - c.hits
+	accesses = hits + lru.opts.stats.Misses - c.misses
+	ghostHits = lru.opts.stats.GhostHits - c.ghostHits
+	c.last, c.hits, c.misses, c.ghostHits = now, lru.opts.stats.Hits, lru.opts.stats.Misses, lru.opts.stats.GhostHits
+	if accesses == 0 {
+		return
+	}
+	ratio = float64(hits) / float64(accesses)
+	gain = float64(ghostHits) / float64(accesses)
+	next = capacity
+	switch {
+	case ratio < c.Target && ghostHits > 0:
+		next = capacity + max(1, int(float64(capacity)*c.Step))
+	case ratio > c.Target && ratio-c.Target > gain:
+		next = capacity - max(1, int(float64(capacity)*c.Step))
+	}
+	next = max(min(next, c.Max), c.Min, 1)
+	if next != capacity {
+		lru.resize(next)
+	}
+}
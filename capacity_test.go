@@ -0,0 +1,84 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUSetCapacity(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8)
+	)
+	for i := 0; i < 8; i++ {
+		lru.Set(i, i)
+	}
+	lru.SetCapacity(3)
+	if lru.Len() != 3 || lru.Capacity() != 3 || lru.Read(7) != 7 || lru.Read(4) != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", lru.Len(), lru.Capacity())
+	}
+	lru.Set(8, 8)
+	if lru.Len() != 3 {
+		t.Fatal("assertion failed, expected new capacity enforced.", lru.Len())
+	}
+	lru.SetCapacity(0)
+	if lru.Capacity() != 3 {
+		t.Fatal("assertion failed, expected no-op.", lru.Capacity())
+	}
+	if err := lru.Validate(); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+}
+
+func TestLRUCapacityController(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(4, WithClock(clock), WithCapacityController(CapacityController{
+			Min: 2, Max: 16, Target: 0.9, Step: 0.5, Interval: time.Second,
+		}))
+	)
+	// cycling over 8 keys never hits with capacity 4,
+	// but every miss is a ghost hit
+	for round := 0; round < 8; round++ {
+		for i := 0; i < 8; i++ {
+			if v, _ := lru.Get(i); v == nil {
+				lru.Set(i, i)
+			}
+		}
+		clock.now = clock.now.Add(time.Second)
+	}
+	if c := lru.Capacity(); c < 8 {
+		t.Fatal("assertion failed, expected grown capacity.", c)
+	}
+	// a single hot key needs little space
+	for round := 0; round < 16; round++ {
+		for i := 0; i < 10; i++ {
+			lru.Get(0)
+		}
+		clock.now = clock.now.Add(time.Second)
+	}
+	if c := lru.Capacity(); c != 2 {
+		t.Fatal("assertion failed, expected shrunk capacity.", c)
+	}
+}
@@ -0,0 +1,127 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*ChainCache)(nil)
+	_ Remover        = (*ChainCache)(nil)
+)
+
+// ChainCache combines multiple caches into
+// levels that are consulted in order ( e.g.
+// a small in-process L1 in front of a larger
+// L2 ).
+type ChainCache struct {
+	levels []CacheInterface
+}
+
+// - MARK: Alloc/Init section.
+
+// Chain allocates and initializes a new `ChainCache`
+// consulting `caches` in the given order and returns
+// a pointer to it.
+func Chain(caches ...CacheInterface) *ChainCache {
+	return &ChainCache{levels: caches}
+}
+
+// - MARK: ChainCache section.
+
+// Set writes k/v pair to all levels. It returns
+// `isNew` as reported by the first level and the
+// first error encountered.
+func (cc *ChainCache) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	for i, c := range cc.levels {
+		n, lerr := c.Set(key, value)
+		if i == 0 {
+			isNew = n
+		}
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	return isNew, err
+}
+
+// Get fetches `key` from levels in order and
+// back-fills all earlier levels on hit. A failing
+// level is skipped; its error is only returned when
+// no later level holds the key.
+func (cc *ChainCache) Get(key interface{}) (value interface{}, err error) {
+	for i, c := range cc.levels {
+		v, lerr := c.Get(key)
+		if lerr != nil {
+			if err == nil {
+				err = lerr
+			}
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			cc.levels[j].Set(key, v)
+		}
+		return v, nil
+	}
+	return nil, err
+}
+
+// Read reads `key` from levels in order without
+// back-filling or touching cache counters.
+func (cc *ChainCache) Read(key interface{}) (value interface{}) {
+	for _, c := range cc.levels {
+		if value = c.Read(key); value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// Remove removes `key` from all levels that
+// conform to `Remover` and returns `true` when
+// any of them held the key.
+func (cc *ChainCache) Remove(key interface{}) (ok bool) {
+	for _, c := range cc.levels {
+		if r, isRemover := c.(Remover); isRemover && r.Remove(key) {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// Purge purges all levels.
+func (cc *ChainCache) Purge() {
+	for _, c := range cc.levels {
+		c.Purge()
+	}
+}
+
+// Len returns number of items in the first
+// level.
+func (cc *ChainCache) Len() int {
+	if len(cc.levels) == 0 {
+		return 0
+	}
+	return cc.levels[0].Len()
+}
@@ -0,0 +1,55 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestChain(t *testing.T) {
+	var (
+		l1, l2, l3 *LRU        = NewLRU(4), NewLRU(8), NewLRU(16)
+		chain      *ChainCache = Chain(l1, l2, l3)
+		value      interface{}
+		err        error
+	)
+	l3.Set("deep", "value")
+	if value, err = chain.Get("deep"); value != "value" || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value, err)
+	}
+	if l1.Read("deep") != "value" || l2.Read("deep") != "value" {
+		t.Fatal("assertion failed, expected back-filled levels.")
+	}
+	chain.Set("key", 1)
+	if l1.Read("key") != 1 || l2.Read("key") != 1 || l3.Read("key") != 1 {
+		t.Fatal("assertion failed, expected fanned out set.")
+	}
+	if !chain.Remove("key") || chain.Read("key") != nil {
+		t.Fatal("assertion failed, expected fanned out remove.")
+	}
+	if value, err = chain.Get("missing"); value != nil || err != nil {
+		t.Fatal("assertion failed, expected miss.", value, err)
+	}
+	chain.Purge()
+	if chain.Len() != 0 || l3.Len() != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+}
@@ -0,0 +1,130 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// cleanupState tracks cleanups attached to cached
+// values, see `WithCleanup`.
+type cleanupState struct {
+	attach  func(value interface{}) (runtime.Cleanup, bool)
+	handles map[interface{}]runtime.Cleanup
+	cleaned *atomic.Uint64
+}
+
+// - MARK: Alloc/Init section.
+
+// WithCleanup attaches a cleanup ( see
+// `runtime.AddCleanup` ) to cached values of type
+// `*T`, as a safety net for values wrapping external
+// resources. The cleanup calls the function returned
+// by `release` when it was written, once the value
+// is garbage collected without its eviction having
+// been handled, e.g. when it was purged without a
+// `WithOnEvict` callback, or the whole cache was
+// dropped. Values handed to an eviction callback
+// ( or released, see `Releasable` ), or replaced
+// by another value, have their cleanup cancelled;
+// rewriting the cached value keeps its cleanup.
+// Cleanups are counted by
+// `Counters.Cleaned`. Note, functions returned by
+// `release` must not reference the value; otherwise
+// it's never collected. Cleanups aren't guaranteed
+// to run before the process exits.
+func WithCleanup[T any](release func(*T) func()) Option {
+	return func(lru *LRU) {
+		var (
+			cleaned *atomic.Uint64 = new(atomic.Uint64)
+		)
+		lru.opts.cleanup = &cleanupState{
+			handles: make(map[interface{}]runtime.Cleanup),
+			cleaned: cleaned,
+			attach: func(value interface{}) (c runtime.Cleanup, ok bool) {
+				var (
+					ptr *T
+				)
+				if ptr, ok = value.(*T); !ok || ptr == nil {
+					return c, false
+				}
+				// the cleanup must not reference the cache,
+				// which would keep it and its values alive
+				return runtime.AddCleanup(ptr, func(fn func()) {
+					cleaned.Add(1)
+					fn()
+				}, release(ptr)), true
+			},
+		}
+	}
+}
+
+// - MARK: LRU section.
+
+// attachCleanup attaches a cleanup to `value` of
+// `key`. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) attachCleanup(key interface{}, value interface{}) {
+	var (
+		cs *cleanupState = lru.opts.cleanup
+	)
+	if c, ok := cs.attach(value); ok {
+		cs.handles[key] = c
+	} else {
+		delete(cs.handles, key)
+	}
+}
+
+// stopCleanup cancels the cleanup of the value
+// of `key` being replaced. Note, this routine is
+// not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) stopCleanup(key interface{}) {
+	if c, ok := lru.opts.cleanup.handles[key]; ok {
+		c.Stop()
+		delete(lru.opts.cleanup.handles, key)
+	}
+}
+
+// detachCleanup forgets the cleanup of `value`
+// of `key` leaving the cache, and cancels it when
+// the eviction is handled by callbacks. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) detachCleanup(key interface{}, value interface{}) {
+	var (
+		cs *cleanupState = lru.opts.cleanup
+		c  runtime.Cleanup
+		ok bool
+	)
+	if c, ok = cs.handles[key]; !ok {
+		return
+	}
+	delete(cs.handles, key)
+	_, releasable := value.(Releasable)
+	if lru.opts.onEvict != nil || (releasable && lru.opts.refs != nil) {
+		c.Stop()
+	}
+}
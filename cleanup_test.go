@@ -0,0 +1,84 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// resource stands in for a value wrapping an
+// external resource.
+type resource struct {
+	closed *atomic.Int32
+	buf    [64]byte
+}
+
+func TestLRUCleanup(t *testing.T) {
+	var (
+		closed  atomic.Int32
+		release func(*resource) func()
+	)
+	release = func(r *resource) func() {
+		closed := r.closed
+		return func() { closed.Add(1) }
+	}
+	lru := NewLRU(8, WithCleanup(release))
+	a := &resource{closed: &closed}
+	lru.Set("a", a)
+	// rewriting keeps the cleanup, replacing cancels it
+	lru.Set("a", a)
+	lru.Set("b", &resource{closed: &closed})
+	lru.Set("b", "not guarded")
+	// purged without callbacks
+	lru.Purge()
+	for deadline := time.Now().Add(time.Second); closed.Load() != 1; {
+		if time.Now().After(deadline) {
+			t.Fatal("assertion failed, expected cleanup to release the value.")
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if stats := lru.Stats(); closed.Load() != 1 || stats.Cleaned != 1 {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 1, stats.Cleaned)
+	}
+	// evictions handed to callbacks cancel cleanups
+	handled := NewLRU(8, WithCleanup(release), WithOnEvict(func(key, value interface{}, reason EvictReason) {
+		value.(*resource).closed.Add(10)
+	}))
+	handled.Set("a", &resource{closed: &closed})
+	handled.Remove("a")
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if closed.Load() != 11 || handled.Stats().Cleaned != 0 {
+		t.Fatal("assertion failed, expected handled eviction not to be cleaned up.", closed.Load())
+	}
+}
@@ -0,0 +1,191 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "sync"
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*ClockCache)(nil)
+	_ Remover        = (*ClockCache)(nil)
+)
+
+// ClockCache implements CLOCK caching policy, an
+// approximation of Least Recently Used keeping
+// enteries in a preallocated circular buffer with
+// a reference bit per entery instead of a linked
+// list. Hits only set the reference bit and writes
+// allocate no list nodes; therefore it's cheaper
+// under heavy write load. On eviction, a hand sweeps
+// the buffer clearing reference bits and evicts the
+// first entery not referenced since its last sweep.
+type ClockCache struct {
+	mu     sync.Mutex
+	slots  []clockSlot
+	lookup map[interface{}]int
+	free   []int // indices of unused slots
+	hand   int
+}
+
+// clockSlot is an entery of `ClockCache`.
+type clockSlot struct {
+	key   interface{}
+	value interface{}
+	ref   bool
+	used  bool
+}
+
+// - MARK: Alloc/Init section.
+
+// NewClockCache allocates and initializes a new
+// `ClockCache` holding up to `capacity` enteries
+// and returns a pointer to it. Note, when
+// `capacity <= 0` holds true, capacity is set to
+// `defaultCAPACITY`.
+func NewClockCache(capacity int) *ClockCache {
+	var (
+		cc *ClockCache
+	)
+	if capacity <= 0 {
+		capacity = defaultCAPACITY
+	}
+	cc = &ClockCache{
+		slots:  make([]clockSlot, capacity),
+		lookup: make(map[interface{}]int, capacity),
+		free:   make([]int, 0, capacity),
+	}
+	cc.reset()
+	return cc
+}
+
+// - MARK: ClockCache section.
+
+// Set conforms to `CacheInterface`. It writes k/v
+// pair, evicting an entery when the cache is full,
+// and sets `isNew` to `true` when `key` wasn't in
+// cache.
+func (cc *ClockCache) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	var (
+		i  int
+		ok bool
+	)
+	cc.mu.Lock()
+	if i, ok = cc.lookup[key]; ok {
+		cc.slots[i].value, cc.slots[i].ref = value, true
+		cc.mu.Unlock()
+		return false, nil
+	}
+	if n := len(cc.free); n > 0 {
+		i, cc.free = cc.free[n-1], cc.free[:n-1]
+	} else {
+		i = cc.evict()
+	}
+	cc.slots[i] = clockSlot{key: key, value: value, used: true}
+	cc.lookup[key] = i
+	cc.mu.Unlock()
+	return true, nil
+}
+
+// Get conforms to `CacheInterface`. It returns
+// nil when `key` isn't in cache.
+func (cc *ClockCache) Get(key interface{}) (value interface{}, err error) {
+	cc.mu.Lock()
+	if i, ok := cc.lookup[key]; ok {
+		value, cc.slots[i].ref = cc.slots[i].value, true
+	}
+	cc.mu.Unlock()
+	return value, nil
+}
+
+// Read conforms to `CacheInterface`. Unlike `Get`,
+// it doesn't mark the entery as referenced.
+func (cc *ClockCache) Read(key interface{}) (value interface{}) {
+	cc.mu.Lock()
+	if i, ok := cc.lookup[key]; ok {
+		value = cc.slots[i].value
+	}
+	cc.mu.Unlock()
+	return value
+}
+
+// Remove conforms to `Remover`.
+func (cc *ClockCache) Remove(key interface{}) (ok bool) {
+	var (
+		i int
+	)
+	cc.mu.Lock()
+	if i, ok = cc.lookup[key]; ok {
+		delete(cc.lookup, key)
+		cc.slots[i] = clockSlot{}
+		cc.free = append(cc.free, i)
+	}
+	cc.mu.Unlock()
+	return ok
+}
+
+// Purge conforms to `CacheInterface`.
+func (cc *ClockCache) Purge() {
+	cc.mu.Lock()
+	cc.reset()
+	cc.mu.Unlock()
+}
+
+// Len conforms to `CacheInterface`.
+func (cc *ClockCache) Len() (n int) {
+	cc.mu.Lock()
+	n = len(cc.lookup)
+	cc.mu.Unlock()
+	return n
+}
+
+// evict sweeps the hand to the first slot not
+// referenced since the last sweep, evicts its
+// entery and returns its index. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (cc *ClockCache) evict() (i int) {
+	for cc.slots[cc.hand].ref {
+		cc.slots[cc.hand].ref = false
+		cc.hand = (cc.hand + 1) % len(cc.slots)
+	}
+	i = cc.hand
+	delete(cc.lookup, cc.slots[i].key)
+	cc.slots[i] = clockSlot{}
+	cc.hand = (cc.hand + 1) % len(cc.slots)
+	return i
+}
+
+// reset removes all enteries. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (cc *ClockCache) reset() {
+	for key, _ := range cc.lookup {
+		delete(cc.lookup, key)
+	}
+	cc.free = cc.free[:0]
+	for i := len(cc.slots) - 1; i >= 0; i-- {
+		cc.slots[i] = clockSlot{}
+		cc.free = append(cc.free, i)
+	}
+	cc.hand = 0
+}
@@ -0,0 +1,57 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestClockCache(t *testing.T) {
+	var (
+		cc *ClockCache = NewClockCache(3)
+	)
+	for i := 0; i < 3; i++ {
+		if isNew, _ := cc.Set(i, i); !isNew {
+			t.Fatal("assertion failed, expected new entery.", i)
+		}
+	}
+	// referenced enteries get a second chance
+	cc.Get(0)
+	cc.Set(3, 3)
+	if cc.Read(0) != 0 || cc.Read(1) != nil || cc.Len() != 3 {
+		t.Fatal("assertion failed, expected unreferenced entery to be evicted.")
+	}
+	if isNew, _ := cc.Set(0, 10); isNew || cc.Read(0) != 10 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if !cc.Remove(2) || cc.Remove(2) || cc.Len() != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	// freed slots are reused before evicting
+	cc.Set(4, 4)
+	if cc.Len() != 3 || cc.Read(3) != 3 {
+		t.Fatal("assertion failed, expected free slot to be reused.")
+	}
+	cc.Purge()
+	if cc.Len() != 0 || cc.Read(0) != nil {
+		t.Fatal("assertion failed, expected empty cache.")
+	}
+}
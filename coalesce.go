@@ -0,0 +1,83 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// coalesceState is the container for state of
+// `WithWriteCoalescing`.
+type coalesceState struct {
+	window  time.Duration
+	pending map[interface{}]struct{} // keys awaiting a trailing event
+}
+
+// - MARK: Alloc/Init section.
+
+// WithWriteCoalescing coalesces updates of an entery
+// written less than `window` after the first write of
+// a burst: the value is replaced in place without
+// reporting the replaced value ( see `WithOnEvict` ),
+// counting a replacement or publishing an event. Once
+// `window` elapses, a single `EventSET` carrying the
+// last value is published, which keeps the noise of
+// frequently rewritten keys like counters down. Such
+// updates are counted as `Coalesced`. Note, when
+// `window <= 0` holds true, it has no effect.
+func WithWriteCoalescing(window time.Duration) Option {
+	return func(lru *LRU) {
+		if window <= 0 {
+			return
+		}
+		lru.opts.coalesce = &coalesceState{window: window, pending: make(map[interface{}]struct{})}
+	}
+}
+
+// - MARK: LRU section.
+
+// coalesced returns whether an update of `item`
+// written at `now` is coalesced and schedules the
+// trailing event of its burst. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) coalesced(item *LRUItem, now int64) bool {
+	var (
+		cs  *coalesceState = lru.opts.coalesce
+		key interface{}    = item.Key
+	)
+	if cs == nil || now-item.stamp >= int64(cs.window) {
+		return false
+	}
+	lru.opts.stats.Coalesced++
+	if _, ok := cs.pending[key]; !ok {
+		cs.pending[key] = struct{}{}
+		time.AfterFunc(cs.window-time.Duration(now-item.stamp), func() {
+			lru.mu.Lock()
+			delete(cs.pending, key)
+			if item := lru.read(key); item != nil {
+				lru.publish(Event{Type: EventSET, Key: key, Value: item.Value})
+			}
+			lru.mu.Unlock()
+		})
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUWriteCoalescing(t *testing.T) {
+	var (
+		replaced int
+		lru      *LRU = NewLRU(8, WithWriteCoalescing(20*time.Millisecond), WithOnEvict(func(key, value interface{}, reason EvictReason) {
+			if reason == EvictREPLACED {
+				replaced++
+			}
+		}))
+		events, cancel = lru.Subscribe(16)
+	)
+	defer cancel()
+	for i := 1; i <= 4; i++ {
+		lru.Set("a", i)
+	}
+	if ev := <-events; ev.Type != EventSET || ev.Value != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", ev)
+	}
+	select {
+	case ev := <-events:
+		if ev.Type != EventSET || ev.Value != 4 {
+			t.Fatal("assertion failed, expected trailing event with last value.", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("assertion failed, expected trailing event.")
+	}
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if replaced != 0 || lru.opts.stats.Coalesced != 3 || lru.read("a").Value != 4 {
+		t.Fatalf("assertion failed, expected equal with value(3) - got value(%d).", lru.opts.stats.Coalesced)
+	}
+}
@@ -0,0 +1,139 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sort"
+)
+
+// Dictionary training parameters
+const (
+	dictSEGMENT = 16       // bytes per candidate segment
+	dictMAXSIZE = 32 << 10 // DEFLATE window size
+)
+
+// FlateCodec compresses values with DEFLATE, using
+// a preset dictionary when given one. Dictionaries
+// trained on samples of small similar values ( e.g.
+// JSON documents ) let such values compress well
+// even though each alone is too short to contain
+// much redundancy.
+type FlateCodec struct {
+	level int
+	dict  []byte
+}
+
+// - MARK: Alloc/Init section.
+
+// NewFlateCodec allocates and initializes a new
+// `FlateCodec` struct compressing at `level` ( see
+// `compress/flate` ) with preset dictionary `dict`,
+// which may be `nil`, and returns a pointer to it.
+func NewFlateCodec(level int, dict []byte) *FlateCodec {
+	return &FlateCodec{level: level, dict: dict}
+}
+
+// TrainDictionary builds a preset dictionary of at
+// most `size` bytes from `samples`. Segments shared
+// by most samples are kept and placed at the end of
+// the dictionary, where DEFLATE references them with
+// the shortest distances.
+func TrainDictionary(samples [][]byte, size int) []byte {
+	type candidate struct {
+		segment string
+		score   int
+	}
+	var (
+		seen   map[string]int = make(map[string]int)
+		last   map[string]int = make(map[string]int)
+		ranked []candidate
+		dict   []byte
+	)
+	size = min(max(size, 0), dictMAXSIZE)
+	for i, sample := range samples {
+		for off := 0; off+dictSEGMENT <= len(sample); off++ {
+			segment := string(sample[off : off+dictSEGMENT])
+			// count each segment once per sample
+			if n, ok := last[segment]; ok && n == i {
+				continue
+			}
+			last[segment] = i
+			seen[segment]++
+		}
+	}
+	for segment, n := range seen {
+		if n > 1 {
+			ranked = append(ranked, candidate{segment, n})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].segment < ranked[j].segment
+	})
+	for _, c := range ranked {
+		if len(dict)+dictSEGMENT > size {
+			break
+		}
+		// overlapping segments are already covered
+		if bytes.Contains(dict, []byte(c.segment)) {
+			continue
+		}
+		dict = append([]byte(c.segment), dict...)
+	}
+	return dict
+}
+
+// - MARK: FlateCodec section.
+
+// Encode compresses `data`.
+func (fc *FlateCodec) Encode(data []byte) (out []byte, err error) {
+	var (
+		buf bytes.Buffer
+		w   *flate.Writer
+	)
+	if w, err = flate.NewWriterDict(&buf, fc.level, fc.dict); err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode decompresses `data` produced by `Encode`
+// with the same dictionary.
+func (fc *FlateCodec) Decode(data []byte) ([]byte, error) {
+	var (
+		r io.ReadCloser = flate.NewReaderDict(bytes.NewReader(data), fc.dict)
+	)
+	defer r.Close()
+	return io.ReadAll(r)
+}
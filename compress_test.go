@@ -0,0 +1,61 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"testing"
+)
+
+func TestTrainDictionary(t *testing.T) {
+	var (
+		samples [][]byte
+		plain   *FlateCodec = NewFlateCodec(flate.BestCompression, nil)
+		trained *FlateCodec
+		sizes   [2]int
+	)
+	for i := 0; i < 200; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`{"id":%d,"type":"user","status":"active","email":"user%d@example.com","roles":["reader"]}`, i, i)))
+	}
+	dict := TrainDictionary(samples, 1024)
+	if len(dict) == 0 || len(dict) > 1024 {
+		t.Fatal("assertion failed, unexpected dictionary size.", len(dict))
+	}
+	trained = NewFlateCodec(flate.BestCompression, dict)
+	for i, codec := range []*FlateCodec{plain, trained} {
+		doc := []byte(`{"id":4711,"type":"user","status":"active","email":"user4711@example.com","roles":["reader"]}`)
+		out, err := codec.Encode(doc)
+		if err != nil {
+			t.Fatal("assertion failed, unexpected error.", err)
+		}
+		sizes[i] = len(out)
+		if back, err := codec.Decode(out); err != nil || !bytes.Equal(back, doc) {
+			t.Fatal("assertion failed, expected round trip.", err)
+		}
+	}
+	if sizes[1]*2 > sizes[0] {
+		t.Fatal("assertion failed, expected dictionary to halve compressed size.", sizes)
+	}
+}
@@ -0,0 +1,142 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// RequestCache is a tiny unbounded cache memoizing
+// values for the lifetime of a request. It is safe
+// for concurrent use; methods of a `nil` cache are
+// no-ops, so code may call them regardless of
+// whether `WithCache` was used upstream.
+type RequestCache struct {
+	mu     sync.Mutex
+	values map[interface{}]*requestEntery
+}
+
+// requestEntery is a memoized value along with the
+// number of times it was served.
+type requestEntery struct {
+	value interface{}
+	hits  int
+}
+
+// contextKey is the context key of `RequestCache`.
+type contextKey struct{}
+
+// - MARK: Alloc/Init section.
+
+// WithCache returns a copy of `ctx` carrying a new
+// `RequestCache`.
+func WithCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, &RequestCache{values: make(map[interface{}]*requestEntery)})
+}
+
+// FromContext returns the `RequestCache` carried by
+// `ctx`, or `nil` when there is none.
+func FromContext(ctx context.Context) *RequestCache {
+	rc, _ := ctx.Value(contextKey{}).(*RequestCache)
+	return rc
+}
+
+// - MARK: RequestCache section.
+
+// Get returns the value memoized for `key`.
+func (rc *RequestCache) Get(key interface{}) (value interface{}, ok bool) {
+	var (
+		e *requestEntery
+	)
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.Lock()
+	if e, ok = rc.values[key]; ok {
+		e.hits++
+		value = e.value
+	}
+	rc.mu.Unlock()
+	return value, ok
+}
+
+// Set memoizes `value` for `key`.
+func (rc *RequestCache) Set(key interface{}, value interface{}) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	rc.values[key] = &requestEntery{value: value}
+	rc.mu.Unlock()
+}
+
+// Do returns the value memoized for `key`, calling
+// `fn` and memoizing its result on a miss. Errors
+// are not memoized. `fn` is called directly when
+// `rc` is `nil`.
+func (rc *RequestCache) Do(key interface{}, fn func() (interface{}, error)) (value interface{}, err error) {
+	var (
+		ok bool
+	)
+	if value, ok = rc.Get(key); ok {
+		return value, nil
+	}
+	if value, err = fn(); err != nil {
+		return nil, err
+	}
+	rc.Set(key, value)
+	return value, nil
+}
+
+// Promote writes enteries served at least `minHits`
+// times to the long-lived cache `dst` and returns
+// the number of promoted enteries. It is meant to
+// be called at the end of the request.
+func (rc *RequestCache) Promote(dst CacheInterface, minHits int) (n int) {
+	if rc == nil {
+		return 0
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key, e := range rc.values {
+		if e.hits < minHits {
+			continue
+		}
+		if _, err := dst.Set(key, e.value); err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns number of memoized values.
+func (rc *RequestCache) Len() (l int) {
+	if rc == nil {
+		return 0
+	}
+	rc.mu.Lock()
+	l = len(rc.values)
+	rc.mu.Unlock()
+	return l
+}
@@ -0,0 +1,70 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRequestCache(t *testing.T) {
+	var (
+		ctx     context.Context = WithCache(context.Background())
+		rc      *RequestCache   = FromContext(ctx)
+		backing *LRU            = NewLRU(8)
+		calls   int
+	)
+	fn := func() (interface{}, error) {
+		calls++
+		return "user", nil
+	}
+	for i := 0; i < 3; i++ {
+		if v, err := rc.Do("user:1", fn); v != "user" || err != nil {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatal("assertion failed, expected memoized value.", calls)
+	}
+	if _, err := rc.Do("broken", func() (interface{}, error) { return nil, errors.New("boom") }); err == nil || rc.Len() != 1 {
+		t.Fatal("assertion failed, expected unmemoized error.", err)
+	}
+	rc.Set("cold", 1)
+	if n := rc.Promote(backing, 2); n != 1 || backing.Read("user:1") != "user" || backing.Read("cold") != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n)
+	}
+}
+
+func TestRequestCacheMissing(t *testing.T) {
+	var (
+		rc *RequestCache = FromContext(context.Background())
+	)
+	if rc != nil {
+		t.Fatal("assertion failed, expected nil cache.")
+	}
+	rc.Set("key", 1)
+	if v, err := rc.Do("key", func() (interface{}, error) { return 2, nil }); v != 2 || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+	}
+}
@@ -0,0 +1,78 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func byteCost(key, value interface{}) int64 {
+	return int64(len(value.(string)))
+}
+
+func TestLRUCost(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(16, WithCost(byteCost, 10))
+	)
+	lru.Set("a", "1234")
+	lru.Set("b", "1234")
+	if lru.Cost() != 8 || lru.Len() != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", lru.Cost())
+	}
+	// exceeds the limit; least recently used goes first
+	lru.Set("c", "1234")
+	if lru.Cost() != 8 || lru.Read("a") != nil {
+		t.Fatal("assertion failed, expected cost based eviction.", lru.Cost())
+	}
+	// growing an entery evicts others but keeps it
+	lru.Set("c", strings.Repeat("x", 12))
+	if lru.Len() != 1 || lru.Cost() != 12 || lru.Read("c") == nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", lru.Len(), lru.Cost())
+	}
+	if err := lru.Validate(); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	lru.Purge()
+	if lru.Cost() != 0 {
+		t.Fatal("assertion failed, expected zero cost.")
+	}
+}
+
+func TestLRULoadGuard(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(16, WithCost(byteCost, 100), WithLoadGuard(0, 0.5), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			return strings.Repeat("x", key.(int)), nil
+		})))
+		value interface{}
+	)
+	if value, _ = lru.Get(10); value == nil || lru.Read(10) == nil {
+		t.Fatal("assertion failed, expected small value to be cached.")
+	}
+	if value, _ = lru.Get(60); value == nil || lru.Read(60) != nil {
+		t.Fatal("assertion failed, expected large value to bypass cache.")
+	}
+	if stats := lru.Stats(); stats.Oversized != 1 || stats.Unadmitted != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Counters)
+	}
+}
@@ -0,0 +1,87 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// - MARK: LRU section.
+
+// DebugDump writes a detailed textual dump of
+// internal structures to `w`: configuration,
+// the list in recency order, secondary indexes,
+// in-flight loads and statistics. The format is
+// meant for humans debugging policy behavior and
+// is not stable. The cache is locked while the
+// dump is produced.
+func (lru *LRU) DebugDump(w io.Writer) error {
+	var (
+		bw   *bufio.Writer = bufio.NewWriter(w)
+		item *LRUItem
+		i    int
+		st   CacheState
+	)
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if lru.opts.warmup != nil && lru.warming() {
+		st = StateWARMING
+	}
+	fmt.Fprintf(bw, "cache(lru): name=%q capacity=%d len=%d cost=%d/%d state=%s now=%d\n",
+		lru.opts.name, lru.capacity+1, lru.items.Len(), lru.opts.totalCost, lru.opts.maxCost, st, lru.now())
+	for _, k := range sortedLabels(lru.opts.labels) {
+		fmt.Fprintf(bw, "label %s=%s\n", k, lru.opts.labels[k])
+	}
+	fmt.Fprintf(bw, "list(%d) front=mru:\n", lru.items.Len())
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		item = elem.Value.(*LRUItem)
+		fmt.Fprintf(bw, "  %d key=%#v count=%d expires=%d stamp=%d version=%d cost=%d\n",
+			i, item.Key, item.Count, item.expires, item.stamp, item.version, item.cost)
+		i++
+	}
+	fmt.Fprintf(bw, "aliases(%d):\n", len(lru.opts.aliases))
+	for key, aliases := range lru.opts.aliases {
+		for alias, _ := range aliases {
+			fmt.Fprintf(bw, "  %#v -> %#v\n", alias, key)
+		}
+	}
+	fmt.Fprintf(bw, "namespaces(%d):\n", len(lru.opts.namespaces))
+	for ns, ids := range lru.opts.namespaces {
+		fmt.Fprintf(bw, "  %#v ids=%d\n", ns, len(ids))
+	}
+	if g := lru.opts.ghost; g != nil {
+		fmt.Fprintf(bw, "ghost(%d/%d)\n", g.order.Len(), g.size)
+	}
+	fmt.Fprintf(bw, "reverse(%d) tombstones(%d) loads(%d) failures(%d) pins(%d) tags(%d)\n",
+		len(lru.opts.reverse), len(lru.opts.tombstones), len(lru.opts.loads), len(lru.opts.failures), len(lru.opts.pins), len(lru.opts.tags))
+	for key, f := range lru.opts.failures {
+		fmt.Fprintf(bw, "  failure key=%#v n=%d until=%d err=%v\n", key, f.n, f.until, f.err)
+	}
+	fmt.Fprintf(bw, "stats:\n")
+	if err := writeStats(bw, lru.stats()); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
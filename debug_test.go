@@ -0,0 +1,49 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLRUDebugDump(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(4)
+		buf bytes.Buffer
+	)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.Alias("alias", "a")
+	lru.Get("a")
+	if err := lru.DebugDump(&buf); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"capacity=4 len=2", "  0 key=\"a\"", "  1 key=\"b\"", "\"alias\" -> \"a\"", "hits 1\n"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("assertion failed, expected %q in dump:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Defaults
+const (
+	digestBUCKETS = 64
+)
+
+// Digest is a two level hash tree over the
+// (key, version) pairs of live enteries and
+// tombstones. Each bucket summarizes the keys
+// hashing into it and `Root` summarizes the
+// buckets; equal digests imply replicas hold
+// the same versions of the same keys. Value
+// hashes are folded in as well so that equal
+// versions with diverging values are caught.
+type Digest struct {
+	Root    uint64
+	Buckets [digestBUCKETS]uint64
+}
+
+// - MARK: LRU section.
+
+// Digest computes the digest of cache contents.
+// It's stable across processes as long as keys
+// have a stable Go-syntax representation.
+func (lru *LRU) Digest() (d Digest) {
+	var (
+		now  int64 = lru.now()
+		item *LRUItem
+	)
+	lru.mu.Lock()
+	for key, tomb := range lru.opts.tombstones {
+		if tomb.expires > now && lru.read(key) == nil {
+			d.add(key, tomb.version, 0, true)
+		}
+	}
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		if item = elem.Value.(*LRUItem); !item.expired(now) {
+			d.add(item.Key, item.version, hashValue(item.Value), false)
+		}
+	}
+	lru.mu.Unlock()
+	d.seal()
+	return d
+}
+
+// - MARK: Digest section.
+
+// Diff returns indices of buckets that differ
+// between `d` and `other`.
+func (d *Digest) Diff(other *Digest) (buckets []int) {
+	if d.Root == other.Root {
+		return nil
+	}
+	for i := 0; i < digestBUCKETS; i++ {
+		if d.Buckets[i] != other.Buckets[i] {
+			buckets = append(buckets, i)
+		}
+	}
+	return buckets
+}
+
+// add folds the pair (`key`, `version`) along with
+// value hash `vh` into its bucket. Buckets are sums
+// of mixed pair hashes so that they are independent
+// of iteration order.
+func (d *Digest) add(key interface{}, version uint64, vh uint64, deleted bool) {
+	var (
+		kh uint64 = hashValue(key)
+		h  uint64 = kh ^ (version * 0x9e3779b97f4a7c15) ^ mix64(vh)
+	)
+	if deleted {
+		h = ^h
+	}
+	d.Buckets[digestBucket(kh)] += mix64(h)
+}
+
+// seal computes the root over all buckets.
+func (d *Digest) seal() {
+	var (
+		h   = fnv.New64a()
+		buf [8]byte
+	)
+	for _, b := range d.Buckets {
+		binary.BigEndian.PutUint64(buf[:], b)
+		h.Write(buf[:])
+	}
+	d.Root = h.Sum64()
+}
+
+// digestBucket returns the bucket of a key hash.
+func digestBucket(kh uint64) int {
+	return int(kh % digestBUCKETS)
+}
+
+// mix64 is the splitmix64 finalizer.
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
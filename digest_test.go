@@ -0,0 +1,61 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUDigest(t *testing.T) {
+	var (
+		a *LRU = NewLRU(16, WithTombstones(time.Hour))
+		b *LRU = NewLRU(16, WithTombstones(time.Hour))
+	)
+	for i := 0; i < 8; i++ {
+		a.SetVersion(i, i, uint64(i+1))
+	}
+	// insert in reverse order; digests are order independent
+	for i := 7; i >= 0; i-- {
+		b.SetVersion(i, i, uint64(i+1))
+	}
+	da, db := a.Digest(), b.Digest()
+	if da != db || len(da.Diff(&db)) != 0 {
+		t.Fatal("assertion failed, expected equal digests.")
+	}
+	b.SetVersion(3, 3, 10)
+	db = b.Digest()
+	if da.Root == db.Root || len(da.Diff(&db)) != 1 {
+		t.Fatal("assertion failed, expected single differing bucket.", da.Diff(&db))
+	}
+	b.Remove(3)
+	if db2 := b.Digest(); db2.Root == db.Root {
+		t.Fatal("assertion failed, expected tombstone to change digest.")
+	}
+	if _, err := a.SyncWith(b, SyncOptions{}); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	if da, db = a.Digest(), b.Digest(); da != db || a.Read(3) != nil {
+		t.Fatal("assertion failed, expected identical replicas after sync.")
+	}
+}
@@ -0,0 +1,210 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+// Package dnscache caches DNS lookup results in
+// a `cache.LRU` keyed by (qtype, name), honoring
+// per-answer TTLs and serving stale answers while
+// the resolver fails.
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mitghi/cache"
+)
+
+// Error messages
+var (
+	EQTYPE error = errors.New("dnscache: unsupported query type.")
+)
+
+// Option configures a `Resolver`.
+type Option func(*Resolver)
+
+// Answer is the result of a lookup along with
+// the duration it may be cached for.
+type Answer struct {
+	Records []string
+	TTL     time.Duration
+}
+
+// LookupFunc resolves `name` for query type
+// `qtype` ( e.g. "A", "AAAA", "CNAME" ).
+type LookupFunc func(ctx context.Context, qtype, name string) (Answer, error)
+
+// Resolver caches answers of a `LookupFunc`.
+type Resolver struct {
+	lru    *cache.LRU
+	lookup LookupFunc
+	stale  time.Duration
+	clock  cache.Clock
+}
+
+// query is the cache key of a lookup.
+type query struct {
+	qtype, name string
+}
+
+// entery is the cached form of an answer.
+type entery struct {
+	records []string
+	expires time.Time
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// Now conforms to `cache.Clock`.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// - MARK: Alloc/Init section.
+
+// WithClock sets the time source of the resolver.
+// It should match the clock of the backing cache.
+func WithClock(clock cache.Clock) Option {
+	return func(r *Resolver) {
+		r.clock = clock
+	}
+}
+
+// WithStale makes the resolver serve answers up to
+// `stale` past their TTL when lookups fail.
+func WithStale(stale time.Duration) Option {
+	return func(r *Resolver) {
+		r.stale = stale
+	}
+}
+
+// New allocates and initializes a new `Resolver`
+// struct caching answers of `lookup` in `lru` and
+// returns a pointer to it.
+func New(lru *cache.LRU, lookup LookupFunc, opts ...Option) (r *Resolver) {
+	r = &Resolver{lru: lru, lookup: lookup, clock: systemClock{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// NetLookup adapts `resolver` ( `net.DefaultResolver`
+// when `nil` ) to a `LookupFunc`. Since `net.Resolver`
+// doesn't expose record TTLs, every answer is cached
+// for `ttl`. Supported query types are A, AAAA, CNAME,
+// MX, NS, PTR and TXT.
+func NetLookup(resolver *net.Resolver, ttl time.Duration) LookupFunc {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return func(ctx context.Context, qtype, name string) (answer Answer, err error) {
+		answer.TTL = ttl
+		switch strings.ToUpper(qtype) {
+		case "A", "AAAA":
+			var (
+				network string = "ip4"
+				ips     []net.IP
+			)
+			if strings.ToUpper(qtype) == "AAAA" {
+				network = "ip6"
+			}
+			if ips, err = resolver.LookupIP(ctx, network, name); err == nil {
+				for _, ip := range ips {
+					answer.Records = append(answer.Records, ip.String())
+				}
+			}
+		case "CNAME":
+			var (
+				cname string
+			)
+			if cname, err = resolver.LookupCNAME(ctx, name); err == nil {
+				answer.Records = []string{cname}
+			}
+		case "MX":
+			var (
+				mxs []*net.MX
+			)
+			if mxs, err = resolver.LookupMX(ctx, name); err == nil {
+				for _, mx := range mxs {
+					answer.Records = append(answer.Records, mx.Host)
+				}
+			}
+		case "NS":
+			var (
+				nss []*net.NS
+			)
+			if nss, err = resolver.LookupNS(ctx, name); err == nil {
+				for _, ns := range nss {
+					answer.Records = append(answer.Records, ns.Host)
+				}
+			}
+		case "PTR":
+			answer.Records, err = resolver.LookupAddr(ctx, name)
+		case "TXT":
+			answer.Records, err = resolver.LookupTXT(ctx, name)
+		default:
+			err = EQTYPE
+		}
+		return answer, err
+	}
+}
+
+// - MARK: Resolver section.
+
+// Lookup returns records of `name` for `qtype`,
+// serving cached answers until their TTL elapses.
+// When the lookup fails, an answer expired no
+// longer than the stale window ago is served
+// instead of the error. The returned slice must
+// not be modified.
+func (r *Resolver) Lookup(ctx context.Context, qtype, name string) (records []string, err error) {
+	var (
+		key    query = query{strings.ToUpper(qtype), strings.ToLower(name)}
+		now    time.Time
+		cached interface{}
+		e      entery
+		hit    bool
+		answer Answer
+	)
+	now = r.clock.Now()
+	if cached = r.lru.Read(key); cached != nil {
+		e, hit = cached.(entery)
+		if hit && now.Before(e.expires) {
+			r.lru.Get(key)
+			return e.records, nil
+		}
+	}
+	if answer, err = r.lookup(ctx, key.qtype, key.name); err != nil {
+		if hit && now.Before(e.expires.Add(r.stale)) {
+			return e.records, nil
+		}
+		return nil, err
+	}
+	if answer.TTL > 0 {
+		r.lru.SetWithTTL(key, entery{answer.Records, now.Add(answer.TTL)}, answer.TTL+r.stale)
+	}
+	return answer.Records, nil
+}
@@ -0,0 +1,77 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mitghi/cache"
+	"github.com/mitghi/cache/cachetest"
+)
+
+func TestResolverLookup(t *testing.T) {
+	var (
+		clock *cachetest.FakeClock = cachetest.NewFakeClock(time.Unix(0, 0))
+		ctx                        = context.Background()
+		calls int
+		fail  bool
+		r     *Resolver
+	)
+	r = New(cache.NewLRU(8, cache.WithClock(clock)), func(ctx context.Context, qtype, name string) (Answer, error) {
+		calls++
+		if fail {
+			return Answer{}, errors.New("servfail")
+		}
+		return Answer{Records: []string{qtype + " " + name}, TTL: time.Minute}, nil
+	}, WithClock(clock), WithStale(time.Hour))
+	for i := 0; i < 3; i++ {
+		if records, err := r.Lookup(ctx, "a", "Example.COM"); err != nil || records[0] != "A example.com" {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", records, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatal("assertion failed, expected cached answer.", calls)
+	}
+	r.Lookup(ctx, "AAAA", "example.com")
+	if calls != 2 {
+		t.Fatal("assertion failed, expected per qtype keys.", calls)
+	}
+	clock.Advance(2 * time.Minute)
+	fail = true
+	if records, err := r.Lookup(ctx, "A", "example.com"); err != nil || len(records) != 1 || calls != 3 {
+		t.Fatal("assertion failed, expected stale answer.", records, err, calls)
+	}
+	clock.Advance(2 * time.Hour)
+	if _, err := r.Lookup(ctx, "A", "example.com"); err == nil {
+		t.Fatal("assertion failed, expected error past stale window.")
+	}
+}
+
+func TestNetLookupQType(t *testing.T) {
+	if _, err := NetLookup(nil, time.Minute)(context.Background(), "SRV", "example.com"); err != EQTYPE {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+}
@@ -0,0 +1,195 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// EvictReason describes why an entery left
+// the cache.
+type EvictReason int
+
+// Eviction reasons
+const (
+	EvictCAPACITY EvictReason = iota // evicted to make room
+	EvictEXPIRED                     // ttl elapsed
+	EvictREMOVED                     // explicitly removed
+	EvictPURGED                      // purged along with others
+	EvictREPLACED                    // value overwritten
+	EvictIDLE                        // not accessed for its idle ttl
+	evictREASONS                     // number of reasons
+)
+
+// EventType describes the kind of an `Event`.
+type EventType int
+
+// Event types
+const (
+	EventSET   EventType = iota // entery written
+	EventEVICT                  // entery left the cache
+)
+
+// Event describes a change of cache contents. For
+// `EventEVICT` events, `Value` is the value that
+// left the cache and `Reason` tells why.
+type Event struct {
+	Type   EventType
+	Key    interface{}
+	Value  interface{}
+	Reason EvictReason
+	Cache  string // name of the cache, see `WithName`
+}
+
+// evictListener is a listener registered with
+// `OnEvict`.
+type evictListener struct {
+	fn func(key, value interface{}, reason EvictReason)
+}
+
+// - MARK: EvictReason section.
+
+// String returns the name of `reason`.
+func (reason EvictReason) String() string {
+	switch reason {
+	case EvictCAPACITY:
+		return "capacity"
+	case EvictEXPIRED:
+		return "expired"
+	case EvictREMOVED:
+		return "removed"
+	case EvictPURGED:
+		return "purged"
+	case EvictREPLACED:
+		return "replaced"
+	case EvictIDLE:
+		return "idle"
+	}
+	return "unknown"
+}
+
+// - MARK: LRU section.
+
+// Subscribe returns a channel receiving events of
+// the cache along with a function that cancels the
+// subscription and closes the channel. Events are
+// dropped while the channel's buffer of size `buffer`
+// is full so that slow subscribers never block the
+// cache.
+func (lru *LRU) Subscribe(buffer int) (events <-chan Event, cancel func()) {
+	var (
+		ch   chan Event = make(chan Event, buffer)
+		once bool
+	)
+	lru.mu.Lock()
+	if lru.opts.subscribers == nil {
+		lru.opts.subscribers = make(map[chan Event]struct{})
+	}
+	lru.opts.subscribers[ch] = struct{}{}
+	lru.mu.Unlock()
+	return ch, func() {
+		lru.mu.Lock()
+		if !once {
+			once = true
+			delete(lru.opts.subscribers, ch)
+			close(ch)
+		}
+		lru.mu.Unlock()
+	}
+}
+
+// OnEvict registers `fn` to be called whenever an
+// entery leaves the cache along with the reason, as
+// `WithOnEvict` does for caches that are already
+// allocated ( e.g. wrappers given a ready-made one ).
+// It's called while the cache is locked and must not
+// call back into the cache. It returns a function
+// that cancels the listener.
+func (lru *LRU) OnEvict(fn func(key, value interface{}, reason EvictReason)) (cancel func()) {
+	var (
+		l *evictListener = &evictListener{fn: fn}
+	)
+	lru.mu.Lock()
+	if lru.opts.listeners == nil {
+		lru.opts.listeners = make(map[*evictListener]struct{})
+	}
+	lru.opts.listeners[l] = struct{}{}
+	lru.mu.Unlock()
+	return func() {
+		lru.mu.Lock()
+		delete(lru.opts.listeners, l)
+		lru.mu.Unlock()
+	}
+}
+
+// evicted reports an entery with `key` and `value`
+// leaving the cache due to `reason`. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) evicted(key interface{}, value interface{}, reason EvictReason) {
+	lru.countEvicted(key, reason)
+	if reason == EvictCAPACITY && lru.opts.ghost != nil {
+		lru.opts.ghost.add(key)
+	}
+	if lru.opts.cleanup != nil {
+		lru.detachCleanup(key, value)
+	}
+	if (reason == EvictEXPIRED || reason == EvictIDLE) && lru.opts.expire != nil {
+		lru.expired(key, value)
+	}
+	if lru.opts.onEvict != nil {
+		lru.opts.onEvict(key, value, reason)
+	}
+	for l, _ := range lru.opts.listeners {
+		l.fn(key, value, reason)
+	}
+	if lru.opts.refs != nil {
+		lru.finalize(value)
+	}
+	lru.publish(Event{Type: EventEVICT, Key: key, Value: value, Reason: reason})
+}
+
+// countEvicted increments eviction counters of an
+// entery with `key` leaving the cache due to `reason`.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly exposed.
+func (lru *LRU) countEvicted(key interface{}, reason EvictReason) {
+	lru.opts.stats.Evictions[reason]++
+	if ck, ok := key.(compositeKey); ok {
+		lru.nsCounters(ck.ns).Evictions[reason]++
+	}
+}
+
+// publish delivers `ev` to subscribers without
+// blocking and marks its key for pending handoffs
+// ( see `Handoff` ). Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) publish(ev Event) {
+	ev.Cache = lru.opts.name
+	for hs, _ := range lru.opts.handoffs {
+		hs.dirty[ev.Key] = struct{}{}
+	}
+	for ch, _ := range lru.opts.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
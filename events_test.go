@@ -0,0 +1,105 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictReasons(t *testing.T) {
+	var (
+		reasons map[EvictReason]int = make(map[EvictReason]int)
+		lru     *LRU                = NewLRU(2, WithOnEvict(func(key, value interface{}, reason EvictReason) {
+			reasons[reason]++
+		}))
+	)
+	lru.Set("a", 1)
+	lru.Set("a", 2)
+	lru.Set("b", 1)
+	lru.Set("c", 1)
+	lru.Remove("b")
+	lru.SetWithTTL("d", 1, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	lru.Get("d")
+	lru.Set("e", 1)
+	lru.Purge()
+	for reason, want := range map[EvictReason]int{
+		EvictREPLACED: 1,
+		EvictCAPACITY: 1,
+		EvictREMOVED:  1,
+		EvictEXPIRED:  1,
+		EvictPURGED:   2,
+	} {
+		if reasons[reason] != want {
+			t.Fatalf("assertion failed, expected equal with value(%d) for reason(%s) - got value(%d).", want, reason, reasons[reason])
+		}
+	}
+}
+
+func TestLRUSubscribe(t *testing.T) {
+	var (
+		lru            *LRU = NewLRU(8)
+		events, cancel      = lru.Subscribe(4)
+		ev             Event
+	)
+	lru.Set("a", 1)
+	lru.Remove("a")
+	if ev = <-events; ev.Type != EventSET || ev.Key != "a" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", ev)
+	}
+	if ev = <-events; ev.Type != EventEVICT || ev.Reason != EvictREMOVED {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", ev)
+	}
+	// full buffers drop events instead of blocking
+	for i := 0; i < 16; i++ {
+		lru.Set(i, i)
+	}
+	cancel()
+	cancel()
+	n := 0
+	for range events {
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("assertion failed, expected equal with value(4) - got value(%d).", n)
+	}
+}
+
+func TestLRUOnEvict(t *testing.T) {
+	var (
+		lru     *LRU = NewLRU(2)
+		evicted []interface{}
+		cancel  = lru.OnEvict(func(key, value interface{}, reason EvictReason) {
+			evicted = append(evicted, key)
+		})
+	)
+	lru.Set("a", 1)
+	lru.Set("b", 1)
+	lru.Set("c", 1)
+	cancel()
+	lru.Remove("b")
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", evicted)
+	}
+}
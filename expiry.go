@@ -0,0 +1,228 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/heap"
+	"time"
+)
+
+// expiryQueue is a min-heap of reap deadlines of
+// enteries, see `WithExpiryQueue`.
+type expiryQueue struct {
+	heap    expiryHeap
+	index   map[interface{}]*expiryEntry
+	next    int64 // last published earliest deadline
+	changes chan struct{}
+	wake    chan struct{} // see `OnExpire`
+}
+
+// expiryEntry is an element of `expiryHeap`.
+type expiryEntry struct {
+	key interface{}
+	at  int64
+	pos int
+}
+
+// expiryHeap conforms to `heap.Interface`.
+type expiryHeap []*expiryEntry
+
+// - MARK: Alloc/Init section.
+
+// WithExpiryQueue keeps deadlines of enteries in a
+// priority queue, so that applications embedding the
+// cache in their own event loops can schedule sweeps
+// ( see `ReapExpired` ) themselves using `NextExpiry`
+// and `ExpiryChanges` instead of a janitor goroutine.
+func WithExpiryQueue() Option {
+	return func(lru *LRU) {
+		lru.opts.expiries = &expiryQueue{
+			index:   make(map[interface{}]*expiryEntry),
+			changes: make(chan struct{}, 1),
+		}
+	}
+}
+
+// - MARK: LRU section.
+
+// NextExpiry returns the earliest time an entery
+// becomes reapable by `ReapExpired`, i.e. when its
+// ttl and, in read-through mode, its grace period
+// elapse, and `false` when no entery expires. It may
+// lie in the past when expired enteries weren't
+// reaped yet. Its cost is constant with
+// `WithExpiryQueue` and linear otherwise.
+func (lru *LRU) NextExpiry() (next time.Time, ok bool) {
+	var (
+		at int64
+	)
+	lru.mu.Lock()
+	if q := lru.opts.expiries; q != nil {
+		if ok = len(q.heap) > 0; ok {
+			at = q.heap[0].at
+		}
+	} else {
+		for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+			if t := lru.reapAt(elem.Value.(*LRUItem)); t != 0 && (!ok || t < at) {
+				at, ok = t, true
+			}
+		}
+	}
+	lru.mu.Unlock()
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, at), true
+}
+
+// ExpiryChanges returns a channel receiving a
+// notification whenever the earliest deadline
+// reported by `NextExpiry` changes. Notifications
+// are coalesced while not received; hence receivers
+// should consult `NextExpiry`. It returns nil without
+// `WithExpiryQueue`.
+func (lru *LRU) ExpiryChanges() <-chan struct{} {
+	if lru.opts.expiries == nil {
+		return nil
+	}
+	return lru.opts.expiries.changes
+}
+
+// reapAt returns when `item` becomes reapable, or
+// zero when it never expires. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) reapAt(item *LRUItem) int64 {
+	if item.expires != 0 && lru.opts.loader != nil {
+		return item.expires + int64(lru.opts.grace)
+	}
+	return item.expires
+}
+
+// queueExpiry updates deadline of `item` in the
+// expiry queue. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) queueExpiry(item *LRUItem) {
+	lru.opts.expiries.update(item.Key, lru.reapAt(item))
+}
+
+// unqueueExpiry removes `key` from the expiry
+// queue. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) unqueueExpiry(key interface{}) {
+	lru.opts.expiries.update(key, 0)
+}
+
+// - MARK: expiryQueue section.
+
+// update sets deadline of `key` to `at`, removing
+// it when `at` is zero, and notifies listeners
+// when the earliest deadline changes.
+func (q *expiryQueue) update(key interface{}, at int64) {
+	var (
+		e  *expiryEntry
+		ok bool
+	)
+	e, ok = q.index[key]
+	switch {
+	case ok && at == 0:
+		heap.Remove(&q.heap, e.pos)
+		delete(q.index, key)
+	case ok:
+		e.at = at
+		heap.Fix(&q.heap, e.pos)
+	case at != 0:
+		e = &expiryEntry{key: key, at: at}
+		q.index[key] = e
+		heap.Push(&q.heap, e)
+	}
+	q.notify()
+}
+
+// reset removes all deadlines.
+func (q *expiryQueue) reset() {
+	q.heap = q.heap[:0]
+	for key, _ := range q.index {
+		delete(q.index, key)
+	}
+	q.notify()
+}
+
+// notify signals `changes` without blocking when
+// the earliest deadline changed.
+func (q *expiryQueue) notify() {
+	var (
+		next int64
+	)
+	if len(q.heap) > 0 {
+		next = q.heap[0].at
+	}
+	if next == q.next {
+		return
+	}
+	q.next = next
+	select {
+	case q.changes <- struct{}{}:
+	default:
+	}
+	if q.wake != nil {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// - MARK: expiryHeap section.
+
+// Len conforms to `heap.Interface`.
+func (h expiryHeap) Len() int { return len(h) }
+
+// Less conforms to `heap.Interface`.
+func (h expiryHeap) Less(i, j int) bool { return h[i].at < h[j].at }
+
+// Swap conforms to `heap.Interface`.
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos, h[j].pos = i, j
+}
+
+// Push conforms to `heap.Interface`.
+func (h *expiryHeap) Push(x interface{}) {
+	e := x.(*expiryEntry)
+	e.pos = len(*h)
+	*h = append(*h, e)
+}
+
+// Pop conforms to `heap.Interface`.
+func (h *expiryHeap) Pop() interface{} {
+	var (
+		old expiryHeap   = *h
+		e   *expiryEntry = old[len(old)-1]
+	)
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,76 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUNextExpiry(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithExpiryQueue()}} {
+		var (
+			clock *manualClock = &manualClock{time.Unix(0, 0)}
+			lru   *LRU         = NewLRU(8, append(opts, WithClock(clock))...)
+		)
+		if _, ok := lru.NextExpiry(); ok {
+			t.Fatal("assertion failed, expected no expiry.")
+		}
+		lru.Set("a", 1)
+		lru.SetWithTTL("b", 2, 2*time.Second)
+		lru.SetWithTTL("c", 3, time.Second)
+		if next, ok := lru.NextExpiry(); !ok || !next.Equal(time.Unix(1, 0)) {
+			t.Fatal("assertion failed, expected equal with value(1s).", next)
+		}
+		lru.Remove("c")
+		if next, _ := lru.NextExpiry(); !next.Equal(time.Unix(2, 0)) {
+			t.Fatal("assertion failed, expected equal with value(2s).", next)
+		}
+		clock.now = clock.now.Add(3 * time.Second)
+		lru.ReapExpired()
+		if _, ok := lru.NextExpiry(); ok {
+			t.Fatal("assertion failed, expected no expiry.")
+		}
+	}
+}
+
+func TestLRUExpiryChanges(t *testing.T) {
+	var (
+		clock   *manualClock    = &manualClock{time.Unix(0, 0)}
+		lru     *LRU            = NewLRU(8, WithClock(clock), WithExpiryQueue())
+		changes <-chan struct{} = lru.ExpiryChanges()
+	)
+	lru.SetWithTTL("a", 1, 2*time.Second)
+	<-changes
+	lru.SetWithTTL("b", 2, 3*time.Second)
+	select {
+	case <-changes:
+		t.Fatal("assertion failed, expected no change of earliest expiry.")
+	default:
+	}
+	lru.SetWithTTL("b", 2, time.Second)
+	<-changes
+	if next, _ := lru.NextExpiry(); !next.Equal(time.Unix(1, 0)) {
+		t.Fatal("assertion failed, expected equal with value(1s).", next)
+	}
+}
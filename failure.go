@@ -0,0 +1,116 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// loadFailure records consecutive load failures
+// of a key.
+type loadFailure struct {
+	err   error
+	n     int
+	until int64
+}
+
+// cachedError wraps a loader error served from
+// the error cache.
+type cachedError struct {
+	err error
+}
+
+// - MARK: cachedError section.
+
+// Error conforms to `error`.
+func (ce *cachedError) Error() string {
+	return ErrCachedError.Error() + " " + ce.err.Error()
+}
+
+// Is makes `errors.Is(err, ErrCachedError)` hold.
+func (ce *cachedError) Is(target error) bool {
+	return target == ErrCachedError
+}
+
+// Unwrap returns the original loader error.
+func (ce *cachedError) Unwrap() error {
+	return ce.err
+}
+
+// - MARK: LRU section.
+
+// cachedError returns the cached load error of
+// `key`, if any, while its backoff lasts. Note,
+// this routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) cachedError(key interface{}) error {
+	var (
+		f  *loadFailure
+		ok bool
+	)
+	if lru.opts.errorBase <= 0 {
+		return nil
+	}
+	if f, ok = lru.opts.failures[key]; !ok || lru.now() >= f.until {
+		return nil
+	}
+	return &cachedError{f.err}
+}
+
+// recordFailure updates the error cache with the
+// outcome `err` of loading `key`; a success clears
+// it while a failure doubles the backoff. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) recordFailure(key interface{}, err error) {
+	var (
+		f       *loadFailure
+		ok      bool
+		now     int64 = lru.now()
+		backoff time.Duration
+	)
+	if err == nil {
+		delete(lru.opts.failures, key)
+		return
+	}
+	if f, ok = lru.opts.failures[key]; !ok {
+		f = &loadFailure{}
+		lru.opts.failures[key] = f
+	}
+	f.err = err
+	f.n++
+	backoff = lru.opts.errorBase
+	for i := 1; i < f.n && (lru.opts.errorMax <= 0 || backoff < lru.opts.errorMax); i++ {
+		backoff *= 2
+	}
+	if lru.opts.errorMax > 0 && backoff > lru.opts.errorMax {
+		backoff = lru.opts.errorMax
+	}
+	f.until = now + int64(backoff)
+	// sweep failures whose backoff elapsed long ago
+	if len(lru.opts.failures) > lru.capacity+1 {
+		for k, f := range lru.opts.failures {
+			if now >= f.until+int64(lru.opts.errorMax) {
+				delete(lru.opts.failures, k)
+			}
+		}
+	}
+}
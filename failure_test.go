@@ -0,0 +1,78 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// manualClock is a `Clock` advanced by hand.
+type manualClock struct {
+	now time.Time
+}
+
+// Now conforms to `Clock`.
+func (mc *manualClock) Now() time.Time {
+	return mc.now
+}
+
+func TestLRUErrorCaching(t *testing.T) {
+	var (
+		outage error        = errors.New("backend outage")
+		clock  *manualClock = &manualClock{time.Unix(0, 0)}
+		calls  int
+		fail   bool = true
+		lru    *LRU
+		err    error
+	)
+	lru = NewLRU(8, WithClock(clock), WithErrorCaching(time.Second, 4*time.Second), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+		calls++
+		if fail {
+			return nil, outage
+		}
+		return "value", nil
+	})))
+	if _, err = lru.Get("key"); err != outage || calls != 1 {
+		t.Fatal("assertion failed, expected loader error.", err, calls)
+	}
+	if _, err = lru.Get("key"); !errors.Is(err, ErrCachedError) || !errors.Is(err, outage) || calls != 1 {
+		t.Fatal("assertion failed, expected cached error.", err, calls)
+	}
+	// second failure doubles the backoff
+	clock.now = clock.now.Add(time.Second)
+	lru.Get("key")
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	if _, err = lru.Get("key"); !errors.Is(err, ErrCachedError) || calls != 2 {
+		t.Fatal("assertion failed, expected cached error.", err, calls)
+	}
+	clock.now = clock.now.Add(time.Second)
+	fail = false
+	if value, err := lru.Get("key"); value != "value" || err != nil || calls != 3 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value, err, calls)
+	}
+	if len(lru.opts.failures) != 0 {
+		t.Fatal("assertion failed, expected cleared failure.")
+	}
+}
@@ -0,0 +1,153 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+// Package filecache caches file contents, or
+// artifacts parsed from them, keyed by path and
+// invalidated when the file's size or modification
+// time changes.
+package filecache
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mitghi/cache"
+)
+
+// Option configures a `Cache`.
+type Option func(*Cache)
+
+// ParseFunc turns contents of file `path` into
+// the cached artifact.
+type ParseFunc func(path string, data []byte) (interface{}, error)
+
+// Cache caches parsed files in an `LRU`.
+type Cache struct {
+	lru          *cache.LRU
+	parse        ParseFunc
+	statInterval time.Duration
+	clock        cache.Clock
+}
+
+// entery is the cached form of a file.
+type entery struct {
+	value   interface{}
+	size    int64
+	modTime time.Time
+	checked time.Time
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// Now conforms to `cache.Clock`.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// - MARK: Alloc/Init section.
+
+// WithStatInterval makes the cache stat files at
+// most once per `d` rather than on every access,
+// trading freshness for fewer syscalls.
+func WithStatInterval(d time.Duration) Option {
+	return func(c *Cache) {
+		c.statInterval = d
+	}
+}
+
+// WithClock sets the time source used to pace
+// stats.
+func WithClock(clock cache.Clock) Option {
+	return func(c *Cache) {
+		c.clock = clock
+	}
+}
+
+// New allocates and initializes a new `Cache`
+// struct keeping artifacts produced by `parse` in
+// `lru` and returns a pointer to it. Raw contents
+// are cached when `parse` is `nil`.
+func New(lru *cache.LRU, parse ParseFunc, opts ...Option) (c *Cache) {
+	c = &Cache{lru: lru, parse: parse, clock: systemClock{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// - MARK: Cache section.
+
+// Get returns the artifact of file `path`, reading
+// and parsing it again when its size or modification
+// time changed since it was cached. Parse errors are
+// not cached.
+func (c *Cache) Get(path string) (value interface{}, err error) {
+	var (
+		now    time.Time = c.clock.Now()
+		cached interface{}
+		e      entery
+		ok     bool
+		info   os.FileInfo
+		data   []byte
+	)
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, err
+	}
+	if cached, _ = c.lru.Get(path); cached != nil {
+		if e, ok = cached.(entery); ok && c.statInterval > 0 && now.Sub(e.checked) < c.statInterval {
+			return e.value, nil
+		}
+	}
+	if info, err = os.Stat(path); err != nil {
+		c.lru.Remove(path)
+		return nil, err
+	}
+	if ok && info.Size() == e.size && info.ModTime().Equal(e.modTime) {
+		e.checked = now
+		c.lru.Set(path, e)
+		return e.value, nil
+	}
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+	value = data
+	if c.parse != nil {
+		if value, err = c.parse(path, data); err != nil {
+			return nil, err
+		}
+	}
+	c.lru.Set(path, entery{value: value, size: info.Size(), modTime: info.ModTime(), checked: now})
+	return value, nil
+}
+
+// Invalidate drops the artifact of file `path`.
+func (c *Cache) Invalidate(path string) bool {
+	var (
+		err error
+	)
+	if path, err = filepath.Abs(path); err != nil {
+		return false
+	}
+	return c.lru.Remove(path)
+}
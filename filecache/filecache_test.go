@@ -0,0 +1,82 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package filecache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitghi/cache"
+	"github.com/mitghi/cache/cachetest"
+)
+
+func TestCacheGet(t *testing.T) {
+	var (
+		path   string               = filepath.Join(t.TempDir(), "app.conf")
+		clock  *cachetest.FakeClock = cachetest.NewFakeClock(time.Unix(0, 0))
+		parses int
+		c      *Cache
+		mtime  time.Time = time.Unix(1000, 0)
+	)
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal("assertion failed, unexpected error.", err)
+		}
+		mtime = mtime.Add(time.Second)
+		os.Chtimes(path, mtime, mtime)
+	}
+	c = New(cache.NewLRU(8), func(path string, data []byte) (interface{}, error) {
+		parses++
+		return strings.ToUpper(string(data)), nil
+	}, WithStatInterval(time.Second), WithClock(clock))
+	write("a=1")
+	for i := 0; i < 3; i++ {
+		if v, err := c.Get(path); v != "A=1" || err != nil {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+		}
+	}
+	if parses != 1 {
+		t.Fatal("assertion failed, expected cached artifact.", parses)
+	}
+	// changes are noticed once the stat interval elapses
+	write("a=2")
+	if v, _ := c.Get(path); v != "A=1" {
+		t.Fatal("assertion failed, expected paced stats.", v)
+	}
+	clock.Advance(2 * time.Second)
+	if v, _ := c.Get(path); v != "A=2" || parses != 2 {
+		t.Fatal("assertion failed, expected reparse after change.", v, parses)
+	}
+	clock.Advance(2 * time.Second)
+	if v, _ := c.Get(path); v != "A=2" || parses != 2 {
+		t.Fatal("assertion failed, expected unchanged artifact.", v, parses)
+	}
+	os.Remove(path)
+	clock.Advance(2 * time.Second)
+	if _, err := c.Get(path); !os.IsNotExist(err) {
+		t.Fatal("assertion failed, expected missing file.", err)
+	}
+}
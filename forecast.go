@@ -0,0 +1,64 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"sort"
+	"time"
+)
+
+// - MARK: LRU section.
+
+// ExpiryForecast estimates refill load by counting
+// live enteries that expire in each upcoming
+// interval. `buckets` are ascending upper bounds
+// relative to now; element `i` of the result counts
+// enteries expiring within `(buckets[i-1], buckets[i]]`,
+// the first interval starting now. Enteries expiring
+// later, or never, are not counted.
+func (lru *LRU) ExpiryForecast(buckets []time.Duration) (counts []int) {
+	var (
+		now  int64
+		item *LRUItem
+		left int64
+		i    int
+	)
+	counts = make([]int, len(buckets))
+	if len(buckets) == 0 {
+		return counts
+	}
+	lru.mu.Lock()
+	now = lru.now()
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		if item = elem.Value.(*LRUItem); item.expires == 0 || item.expired(now) {
+			continue
+		}
+		left = item.expires - now
+		i = sort.Search(len(buckets), func(i int) bool { return int64(buckets[i]) >= left })
+		if i < len(buckets) {
+			counts[i]++
+		}
+	}
+	lru.mu.Unlock()
+	return counts
+}
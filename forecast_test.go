@@ -0,0 +1,50 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLRUExpiryForecast(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(16, WithClock(clock))
+	)
+	lru.SetWithTTL("a", 1, 30*time.Second)
+	lru.SetWithTTL("b", 1, time.Minute)
+	lru.SetWithTTL("c", 1, 90*time.Second)
+	lru.SetWithTTL("d", 1, time.Hour)
+	lru.Set("e", 1)
+	lru.SetWithTTL("f", 1, time.Second)
+	clock.now = clock.now.Add(2 * time.Second)
+	counts := lru.ExpiryForecast([]time.Duration{time.Minute, 5 * time.Minute, 10 * time.Minute})
+	if !reflect.DeepEqual(counts, []int{2, 1, 0}) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", counts)
+	}
+	if counts = lru.ExpiryForecast(nil); len(counts) != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", counts)
+	}
+}
@@ -0,0 +1,88 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// FragmentCache caches rendered fragments ( e.g.
+// HTML or JSON partials ) as byte slices.
+// Rendering goes through pooled buffers so that
+// misses don't allocate beyond the cached copy.
+type FragmentCache struct {
+	lru  *LRU
+	pool sync.Pool
+}
+
+// - MARK: Alloc/Init section.
+
+// NewFragmentCache allocates and initializes a new
+// `FragmentCache` struct storing fragments in `lru`
+// and returns a pointer to it.
+func NewFragmentCache(lru *LRU) *FragmentCache {
+	return &FragmentCache{
+		lru:  lru,
+		pool: sync.Pool{New: func() interface{} { return new(bytes.Buffer) }},
+	}
+}
+
+// - MARK: FragmentCache section.
+
+// Fragment writes the fragment cached for `key` to
+// `w`. On a miss, it renders the fragment with
+// `render`, caches it for `maxAge` ( forever when
+// non-positive ) and writes it to `w`. Nothing is
+// written nor cached when `render` fails.
+func (fc *FragmentCache) Fragment(w io.Writer, key interface{}, maxAge time.Duration, render func(io.Writer) error) (err error) {
+	var (
+		cached interface{}
+		buf    *bytes.Buffer
+		frag   []byte
+		ok     bool
+	)
+	if cached, err = fc.lru.Get(key); err == nil {
+		if frag, ok = cached.([]byte); ok {
+			_, err = w.Write(frag)
+			return err
+		}
+	}
+	buf = fc.pool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer fc.pool.Put(buf)
+	if err = render(buf); err != nil {
+		return err
+	}
+	frag = append([]byte(nil), buf.Bytes()...)
+	fc.lru.SetWithTTL(key, frag, maxAge)
+	_, err = w.Write(frag)
+	return err
+}
+
+// Invalidate drops the fragment cached for `key`.
+func (fc *FragmentCache) Invalidate(key interface{}) bool {
+	return fc.lru.Remove(key)
+}
@@ -0,0 +1,65 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFragmentCache(t *testing.T) {
+	var (
+		clock *manualClock   = &manualClock{time.Unix(0, 0)}
+		fc    *FragmentCache = NewFragmentCache(NewLRU(8, WithClock(clock)))
+		calls int
+		buf   bytes.Buffer
+	)
+	render := func(w io.Writer) error {
+		calls++
+		_, err := io.WriteString(w, "<li>item</li>")
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		if err := fc.Fragment(&buf, "list", time.Minute, render); err != nil {
+			t.Fatal("assertion failed, unexpected error.", err)
+		}
+	}
+	if buf.String() != "<li>item</li><li>item</li><li>item</li>" || calls != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", buf.String(), calls)
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	fc.Fragment(io.Discard, "list", time.Minute, render)
+	if calls != 2 {
+		t.Fatal("assertion failed, expected re-render after max age.", calls)
+	}
+	buf.Reset()
+	if err := fc.Fragment(&buf, "broken", time.Minute, func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return errors.New("boom")
+	}); err == nil || buf.Len() != 0 || fc.lru.Read("broken") != nil {
+		t.Fatal("assertion failed, expected nothing written.", err, buf.String())
+	}
+}
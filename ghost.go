@@ -0,0 +1,86 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// ghost remembers hashes of keys recently evicted
+// to make room, so that misses of such keys can be
+// counted as hits a larger cache would have served.
+// Keys are kept as 64 bit hashes, making it much
+// cheaper than the cache itself; collisions only
+// skew the counter.
+type ghost struct {
+	size  int
+	order *list.List               // front=most recently evicted
+	index map[uint64]*list.Element // hash -> element
+}
+
+// - MARK: Alloc/Init section.
+
+// newGhost allocates and initializes a new `ghost`
+// struct remembering up to `size` keys and returns
+// a pointer to it.
+func newGhost(size int) *ghost {
+	return &ghost{size: size, order: list.New(), index: make(map[uint64]*list.Element, size)}
+}
+
+// - MARK: ghost section.
+
+// add remembers evicted `key`.
+func (g *ghost) add(key interface{}) {
+	var (
+		h uint64 = hashKey(key)
+	)
+	if elem, ok := g.index[h]; ok {
+		g.order.MoveToFront(elem)
+		return
+	}
+	g.index[h] = g.order.PushFront(h)
+	for g.order.Len() > g.size {
+		delete(g.index, g.order.Remove(g.order.Back()).(uint64))
+	}
+}
+
+// hit forgets `key` and reports whether it was
+// remembered, i.e. whether its miss would have
+// been a hit in a larger cache.
+func (g *ghost) hit(key interface{}) bool {
+	var (
+		h uint64 = hashKey(key)
+	)
+	if elem, ok := g.index[h]; ok {
+		g.order.Remove(elem)
+		delete(g.index, h)
+		return true
+	}
+	return false
+}
+
+// resize changes the number of remembered keys.
+func (g *ghost) resize(size int) {
+	g.size = size
+	for g.order.Len() > g.size {
+		delete(g.index, g.order.Remove(g.order.Back()).(uint64))
+	}
+}
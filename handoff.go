@@ -0,0 +1,171 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"time"
+)
+
+// Defaults
+const (
+	defaultHANDOFFTICK = 10 * time.Millisecond
+)
+
+// handoffState tracks keys changed since they
+// were last sent by `Handoff`.
+type handoffState struct {
+	dirty map[interface{}]struct{}
+}
+
+// handoffRecord is an update sent by `Handoff`
+// after the snapshot.
+type handoffRecord struct {
+	Entry   snapshotEntry
+	Deleted bool // entery left the cache
+	Done    bool // last record of the stream
+}
+
+// - MARK: LRU section.
+
+// Handoff streams cache contents to `w` for a
+// warm restart: it writes a snapshot ( see `Export` )
+// followed by updates of enteries changed since,
+// until `done` is closed, when it flushes pending
+// updates and terminates the stream. Updates are
+// coalesced per key and sent every 10ms, so that
+// a busy cache never overruns the stream; the
+// receiver ( see `Takeover` ) ends up with the
+// contents the cache had when `done` was closed.
+// It's meant to run over a UNIX socket between
+// the old and the new process during a restart,
+// with `done` closed once the old one stopped
+// serving. It returns the number of written
+// enteries and updates.
+func (lru *LRU) Handoff(w io.Writer, done <-chan struct{}) (n int, err error) {
+	var (
+		hs      *handoffState = &handoffState{dirty: make(map[interface{}]struct{})}
+		enc     *gob.Encoder
+		ticker  *time.Ticker
+		records []handoffRecord
+		last    bool
+	)
+	// track changes before taking the snapshot so
+	// that none are missed; some may be sent twice
+	lru.mu.Lock()
+	if lru.opts.handoffs == nil {
+		lru.opts.handoffs = make(map[*handoffState]struct{})
+	}
+	lru.opts.handoffs[hs] = struct{}{}
+	lru.mu.Unlock()
+	defer func() {
+		lru.mu.Lock()
+		delete(lru.opts.handoffs, hs)
+		lru.mu.Unlock()
+	}()
+	if n, err = lru.Export(w); err != nil {
+		return n, err
+	}
+	enc = gob.NewEncoder(w)
+	ticker = time.NewTicker(defaultHANDOFFTICK)
+	defer ticker.Stop()
+	for !last {
+		select {
+		case <-done:
+			last = true
+		case <-ticker.C:
+		}
+		records = lru.drainHandoff(hs, last)
+		for _, r := range records {
+			if err = enc.Encode(&r); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Takeover reads a stream written by `Handoff` from
+// `r`. It loads the snapshot ( see `Import` ), then
+// applies updates as they arrive and returns once
+// the stream terminates, so that the cache may
+// serve traffic right away while the old process
+// hands over the remaining changes. It returns the
+// number of loaded enteries and applied updates.
+func (lru *LRU) Takeover(r io.Reader) (n int, err error) {
+	var (
+		br  *bufio.Reader = bufio.NewReader(r)
+		dec *gob.Decoder
+		rec handoffRecord
+	)
+	// `Import` reuses `br` rather than buffering
+	// past the snapshot, so updates are decoded
+	// from where the snapshot ends
+	if n, err = lru.Import(br); err != nil {
+		return n, err
+	}
+	dec = gob.NewDecoder(br)
+	for {
+		rec = handoffRecord{}
+		if err = dec.Decode(&rec); err != nil {
+			return n, err
+		}
+		switch {
+		case rec.Done:
+			return n, nil
+		case rec.Deleted:
+			lru.Remove(rec.Entry.Key)
+		default:
+			if _, err = lru.LoadOrdered([]Entry{rec.Entry.entry()}); err != nil {
+				return n, err
+			}
+		}
+		n++
+	}
+}
+
+// drainHandoff returns records of keys changed
+// since the last call, terminated by the last
+// record when `last` holds true.
+func (lru *LRU) drainHandoff(hs *handoffState, last bool) (records []handoffRecord) {
+	var (
+		item *LRUItem
+	)
+	lru.mu.Lock()
+	for key, _ := range hs.dirty {
+		if item = lru.read(key); item != nil {
+			records = append(records, handoffRecord{Entry: snapshotEntry{item.Key, item.Value, item.Count, item.expires, item.version}})
+		} else {
+			records = append(records, handoffRecord{Entry: snapshotEntry{Key: key}, Deleted: true})
+		}
+		delete(hs.dirty, key)
+	}
+	lru.mu.Unlock()
+	if last {
+		records = append(records, handoffRecord{Done: true})
+	}
+	return records
+}
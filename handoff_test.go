@@ -0,0 +1,74 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestLRUHandoff(t *testing.T) {
+	var (
+		old  *LRU          = NewLRU(8)
+		warm *LRU          = NewLRU(8)
+		done chan struct{} = make(chan struct{})
+		errs chan error    = make(chan error, 1)
+	)
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "handoff.sock"))
+	if err != nil {
+		t.Skip("unix sockets are not supported.", err)
+	}
+	defer ln.Close()
+	for i := 0; i < 4; i++ {
+		old.Set(i, i)
+	}
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			_, err = old.Handoff(conn, done)
+			conn.Close()
+		}
+		errs <- err
+	}()
+	conn, err := net.Dial("unix", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// writes served by the old process until it stops
+	old.Set(1, 10)
+	old.Remove(2)
+	old.Set(4, 4)
+	close(done)
+	n, err := warm.Takeover(conn)
+	if err != nil || n < 4 {
+		t.Fatal("assertion failed, expected successful takeover.", n, err)
+	}
+	if err = <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if warm.Len() != 4 || warm.Read(0) != 0 || warm.Read(1) != 10 || warm.Read(2) != nil || warm.Read(4) != 4 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", warm.Len())
+	}
+}
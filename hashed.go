@@ -0,0 +1,176 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"hash/maphash"
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*HashedLRU)(nil)
+	_ Remover        = (*HashedLRU)(nil)
+)
+
+// HashCollision selects how `HashedLRU` handles
+// distinct keys whose hashes collide.
+type HashCollision int
+
+// Collision handling strategies
+const (
+	// HashVERIFY stores a second, independent key
+	// digest per entery and treats mismatches as
+	// misses; colliding keys then displace each
+	// other instead of aliasing.
+	HashVERIFY HashCollision = iota
+	// HashTRUST accepts the negligible risk of
+	// colliding keys aliasing each other.
+	HashTRUST
+)
+
+// HashedLRU implements Least Recently Used caching
+// policy indexing keys by their 64 bit hashes only,
+// which drastically shrinks the index of caches with
+// tens of millions of small enteries since keys
+// are never retained. Therefore keys can't be
+// enumerated. Hashes are stable across processes
+// only for string and integer keys.
+type HashedLRU struct {
+	index *Uint64LRU
+	seed  maphash.Seed
+	mode  HashCollision
+}
+
+// - MARK: Alloc/Init section.
+
+// NewHashedLRU allocates and initializes a new
+// `HashedLRU` struct handling collisions as
+// selected by `mode` and returns a pointer to it.
+// Note, when `capacity <= 0` holds true, capacity
+// is set to `defaultCAPACITY`.
+func NewHashedLRU(capacity int, mode HashCollision) *HashedLRU {
+	return &HashedLRU{index: NewUint64LRU(capacity), seed: maphash.MakeSeed(), mode: mode}
+}
+
+// - MARK: HashedLRU section.
+
+// Set writes k/v pair in the cache and evicts
+// old enteries when needed. With `HashVERIFY`,
+// a colliding entery of another key is replaced
+// and `isNew` is `true`.
+func (hl *HashedLRU) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	var (
+		h      uint64 = hashKey(key)
+		digest uint64 = hl.digest(key)
+		item   *Uint64LRUItem
+	)
+	hl.index.mu.Lock()
+	isNew = hl.index.set(h, value)
+	item = hl.index.items.Front().Value.(*Uint64LRUItem)
+	if !isNew && item.digest != digest {
+		isNew = true
+	}
+	item.digest = digest
+	hl.index.mu.Unlock()
+	return isNew, nil
+}
+
+// Get fetches `key` from cache and returns its
+// value, or `nil` when it's not found.
+func (hl *HashedLRU) Get(key interface{}) (value interface{}, err error) {
+	var (
+		elem *list.Element
+		item *Uint64LRUItem
+	)
+	hl.index.mu.Lock()
+	hl.index.count++
+	if elem = hl.lookup(key); elem != nil {
+		item = elem.Value.(*Uint64LRUItem)
+		item.Count++
+		value = item.Value
+		hl.index.items.MoveToFront(elem)
+	}
+	hl.index.mu.Unlock()
+	return value, nil
+}
+
+// Read only reads the given item with `key`
+// without incrementing cache counter or
+// triggering eviction policies.
+func (hl *HashedLRU) Read(key interface{}) (value interface{}) {
+	hl.index.mu.Lock()
+	if elem := hl.lookup(key); elem != nil {
+		value = elem.Value.(*Uint64LRUItem).Value
+	}
+	hl.index.mu.Unlock()
+	return value
+}
+
+// Remove removes the given item with `key` from
+// cache and returns `true` when succesfull.
+func (hl *HashedLRU) Remove(key interface{}) (ok bool) {
+	hl.index.mu.Lock()
+	if elem := hl.lookup(key); elem != nil {
+		hl.index.unlink(elem)
+		ok = true
+	}
+	hl.index.mu.Unlock()
+	return ok
+}
+
+// Purge removes all enteries and restarts the cache.
+func (hl *HashedLRU) Purge() {
+	hl.index.Purge()
+}
+
+// Len returns number of items in cache.
+func (hl *HashedLRU) Len() int {
+	return hl.index.Len()
+}
+
+// lookup returns the element associated to `key`
+// or `nil` when there is none. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (hl *HashedLRU) lookup(key interface{}) *list.Element {
+	var (
+		elem *list.Element = hl.index.lookup[hashKey(key)]
+	)
+	if elem == nil {
+		return nil
+	}
+	if hl.mode == HashVERIFY && elem.Value.(*Uint64LRUItem).digest != hl.digest(key) {
+		return nil
+	}
+	return elem
+}
+
+// digest returns the verification digest of `key`,
+// or zero with `HashTRUST`.
+func (hl *HashedLRU) digest(key interface{}) uint64 {
+	if hl.mode != HashVERIFY {
+		return 0
+	}
+	return maphash.Comparable(hl.seed, key)
+}
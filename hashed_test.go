@@ -0,0 +1,76 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestHashedLRU(t *testing.T) {
+	var (
+		hl *HashedLRU = NewHashedLRU(2, HashVERIFY)
+	)
+	if isNew, _ := hl.Set("a", 1); !isNew {
+		t.Fatal("assertion failed, expected new entery.")
+	}
+	hl.Set("b", 2)
+	if v, _ := hl.Get("a"); v != 1 || hl.Read("b") != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v)
+	}
+	hl.Set("c", 3)
+	if hl.Read("b") != nil || hl.Len() != 2 {
+		t.Fatal("assertion failed, expected eviction of least recently used entery.")
+	}
+	if !hl.Remove("a") || hl.Remove("a") {
+		t.Fatal("assertion failed, inconsistent removal.")
+	}
+	if unsafe.Sizeof(Uint64LRUItem{}) != 64 {
+		t.Fatal("assertion failed, expected unchanged item size.")
+	}
+}
+
+func TestHashedLRUCollision(t *testing.T) {
+	var (
+		verify *HashedLRU = NewHashedLRU(4, HashVERIFY)
+		trust  *HashedLRU = NewHashedLRU(4, HashTRUST)
+	)
+	// simulate a collision by planting an entery of
+	// another key under the hash of "a"
+	for _, hl := range []*HashedLRU{verify, trust} {
+		hl.Set("other", 1)
+		elem := hl.index.lookup[hashKey("other")]
+		delete(hl.index.lookup, hashKey("other"))
+		elem.Value.(*Uint64LRUItem).Key = hashKey("a")
+		hl.index.lookup[hashKey("a")] = elem
+	}
+	if verify.Read("a") != nil {
+		t.Fatal("assertion failed, expected digest mismatch to miss.")
+	}
+	if isNew, _ := verify.Set("a", 2); !isNew || verify.Read("a") != 2 {
+		t.Fatal("assertion failed, expected colliding entery to be replaced.")
+	}
+	if trust.Read("a") != 1 {
+		t.Fatal("assertion failed, expected collision to alias with HashTRUST.")
+	}
+}
@@ -0,0 +1,105 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Timestamp is a hybrid logical clock timestamp.
+// The upper 48 bits hold physical time in
+// milliseconds and the lower 16 bits a logical
+// counter, so timestamps order like integers.
+type Timestamp uint64
+
+// HLC implements a hybrid logical clock; its
+// timestamps track physical time while still
+// ordering causally related events across peers
+// with skewed clocks.
+type HLC struct {
+	mu   sync.Mutex
+	last Timestamp
+	wall func() int64
+}
+
+// - MARK: Alloc/Init section.
+
+// NewHLC allocates and initializes a new `HLC`
+// struct backed by the system clock and returns
+// a pointer to it.
+func NewHLC() *HLC {
+	return &HLC{wall: func() int64 { return time.Now().UnixNano() }}
+}
+
+// - MARK: HLC section.
+
+// Now returns a timestamp for a local event; it's
+// strictly greater than all timestamps previously
+// returned or observed.
+func (c *HLC) Now() (ts Timestamp) {
+	c.mu.Lock()
+	ts = c.tick(0)
+	c.mu.Unlock()
+	return ts
+}
+
+// Update observes `remote` ( e.g. timestamp of a
+// replicated write ) and returns a timestamp
+// greater than both `remote` and the local clock.
+func (c *HLC) Update(remote Timestamp) (ts Timestamp) {
+	c.mu.Lock()
+	ts = c.tick(remote)
+	c.mu.Unlock()
+	return ts
+}
+
+// tick advances the clock past `remote`. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (c *HLC) tick(remote Timestamp) Timestamp {
+	var (
+		phys Timestamp = Timestamp(c.wall()/int64(time.Millisecond)) << 16
+		next Timestamp = phys
+	)
+	if c.last >= next {
+		next = c.last + 1
+	}
+	if remote >= next {
+		next = remote + 1
+	}
+	c.last = next
+	return next
+}
+
+// - MARK: Timestamp section.
+
+// Time returns physical component of `ts`.
+func (ts Timestamp) Time() time.Time {
+	return time.UnixMilli(int64(ts >> 16))
+}
+
+// Logical returns logical component of `ts`.
+func (ts Timestamp) Logical() uint16 {
+	return uint16(ts)
+}
@@ -0,0 +1,105 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHLC(t *testing.T) {
+	var (
+		clock  *HLC = NewHLC()
+		frozen int64
+		a, b   Timestamp
+	)
+	frozen = time.Now().UnixNano()
+	clock.wall = func() int64 { return frozen }
+	a = clock.Now()
+	if b = clock.Now(); b <= a || b.Logical() != a.Logical()+1 {
+		t.Fatal("assertion failed, expected monotonic timestamps.", a, b)
+	}
+	// remote clock is ahead
+	if a = clock.Update(b + 100); a <= b+100 {
+		t.Fatal("assertion failed, expected timestamp past remote.", a, b)
+	}
+	if b = clock.Now(); b <= a {
+		t.Fatal("assertion failed, expected monotonic timestamps.", a, b)
+	}
+	if b.Time().UnixMilli() != frozen/int64(time.Millisecond) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+}
+
+func TestLRUMerge(t *testing.T) {
+	var (
+		a       *LRU = NewLRU(8, WithHLC(NewHLC()))
+		b       *LRU = NewLRU(8, WithHLC(NewHLC()))
+		applied bool
+		err     error
+		version uint64
+	)
+	if _, err = NewLRU(8).Merge("key", 1, 1); err != ELRUNOCLOCK {
+		t.Fatal("assertion failed, expected error.", err)
+	}
+	a.Set("key", "a")
+	version, _ = a.Version("key")
+	b.Set("key", "b")
+	// b wrote after a; replicate both ways
+	if applied, _ = a.Merge("key", "b", b.mustVersion("key")); !applied || a.Read("key") != "b" {
+		t.Fatal("assertion failed, expected last writer to win.")
+	}
+	if applied, _ = b.Merge("key", "a", Timestamp(version)); applied || b.Read("key") != "b" {
+		t.Fatal("assertion failed, expected older write to lose.")
+	}
+	// local clock observed remote timestamp
+	a.Set("key", "c")
+	if a.mustVersion("key") <= b.mustVersion("key") {
+		t.Fatal("assertion failed, expected causally later timestamp.")
+	}
+}
+
+func TestLRUMergeFunc(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithHLC(NewHLC()), WithMerge(func(key interface{}, local, remote Versioned) Versioned {
+			// grow-only counter
+			local.Value = local.Value.(int) + remote.Value.(int)
+			if remote.Timestamp > local.Timestamp {
+				local.Timestamp = remote.Timestamp
+			}
+			return local
+		}))
+	)
+	lru.Set("counter", 1)
+	lru.Merge("counter", 2, 1)
+	lru.Merge("counter", 3, 2)
+	if lru.Read("counter") != 6 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", lru.Read("counter"))
+	}
+}
+
+// mustVersion returns version of `key` as a timestamp.
+func (lru *LRU) mustVersion(key interface{}) Timestamp {
+	version, _ := lru.Version(key)
+	return Timestamp(version)
+}
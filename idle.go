@@ -0,0 +1,126 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// - MARK: Alloc/Init section.
+
+// WithExpireAfterAccess makes enteries written by
+// `Set`, and loaded with zero ttl, expire once not
+// accessed for `idle` ( see `SetWithIdleTTL` ),
+// superseding `WithDefaultTTL`. It's ignored with
+// `WithTiers`, whose ttls slide already. Note, when
+// `idle <= 0` holds true, it has no effect.
+func WithExpireAfterAccess(idle time.Duration) Option {
+	return func(lru *LRU) {
+		lru.opts.idleTTL = idle
+	}
+}
+
+// - MARK: LRU section.
+
+// SetWithIdleTTL is same as `Set` except that the
+// written entery expires once not accessed by `Get`
+// for `idle` ( i.e. its ttl is reset on every hit ),
+// which suits session-like enteries that should only
+// die when idle. Note, when `idle <= 0` holds true,
+// entery never expires.
+func (lru *LRU) SetWithIdleTTL(key interface{}, value interface{}, idle time.Duration) (isNew bool, err error) {
+	if err = lru.checkType(value); err != nil {
+		return false, err
+	}
+	lru.mu.Lock()
+	lru.fixExpiry(key)
+	if isNew, err = lru.set(key, value, lru.deadline(idle)); err == nil && idle > 0 {
+		lru.slide(key, idle)
+	}
+	lru.debugValidate()
+	lru.mu.Unlock()
+	return isNew, err
+}
+
+// setDefault writes k/v pair without explicit ttl
+// ( see `tierDeadline` ) and, once written, makes
+// an untiered entery slide when `WithExpireAfterAccess`
+// is configured, or fixes its expiry otherwise. Note,
+// this routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) setDefault(key interface{}, value interface{}) (isNew bool, err error) {
+	if isNew, err = lru.set(key, value, lru.tierDeadline(key)); err != nil || lru.opts.tiers != nil {
+		return isNew, err
+	}
+	if lru.opts.idleTTL > 0 {
+		lru.slide(key, lru.opts.idleTTL)
+	} else if lru.opts.idle != nil {
+		delete(lru.opts.idle, lru.canonical(key))
+	}
+	return isNew, err
+}
+
+// idleDeadline returns the expiry of an untiered
+// entery associated to `key` written without
+// explicit ttl. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) idleDeadline(key interface{}) int64 {
+	if lru.opts.idleTTL <= 0 {
+		return lru.deadline(lru.opts.defaultTTL)
+	}
+	return lru.deadline(lru.opts.idleTTL)
+}
+
+// slide makes the expiry of `key` reset to `idle`
+// on every hit. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) slide(key interface{}, idle time.Duration) {
+	if lru.opts.idle == nil {
+		lru.opts.idle = make(map[interface{}]time.Duration)
+	}
+	lru.opts.idle[lru.canonical(key)] = idle
+}
+
+// expiredReason returns the reason an expired
+// `item` leaves the cache for, i.e. `EvictIDLE`
+// for sliding enteries. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) expiredReason(item *LRUItem) EvictReason {
+	if _, ok := lru.opts.idle[item.Key]; ok {
+		return EvictIDLE
+	}
+	return EvictEXPIRED
+}
+
+// fixExpiry makes the expiry of `key` absolute by
+// removing it from tiers and sliding enteries.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) fixExpiry(key interface{}) {
+	lru.untier(key)
+	if lru.opts.idle != nil {
+		delete(lru.opts.idle, lru.canonical(key))
+	}
+}
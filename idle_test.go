@@ -0,0 +1,96 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUExpireAfterAccess(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(8, WithClock(clock), WithExpireAfterAccess(time.Second))
+	)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.SetWithTTL("c", 3, 2*time.Second)
+	for i := 0; i < 3; i++ {
+		clock.now = clock.now.Add(800 * time.Millisecond)
+		if v, _ := lru.Get("a"); v != 1 {
+			t.Fatal("assertion failed, expected accessed entery to survive.", i)
+		}
+		lru.Read("b")
+	}
+	if lru.Read("b") != nil || lru.Read("c") != nil {
+		t.Fatal("assertion failed, expected idle and absolute enteries to expire.")
+	}
+	clock.now = clock.now.Add(time.Second)
+	if v, _ := lru.Get("a"); v != nil {
+		t.Fatal("assertion failed, expected idle entery to expire.")
+	}
+	lru.ReapExpired()
+	if stats := lru.Stats(); stats.Evicted(EvictIDLE) != 2 || stats.Evicted(EvictEXPIRED) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Evictions)
+	}
+}
+
+func TestLRUSetWithIdleTTL(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(8, WithClock(clock))
+	)
+	lru.SetWithIdleTTL("a", 1, time.Second)
+	clock.now = clock.now.Add(800 * time.Millisecond)
+	lru.Get("a")
+	clock.now = clock.now.Add(800 * time.Millisecond)
+	if v, _ := lru.Get("a"); v != 1 {
+		t.Fatal("assertion failed, expected accessed entery to survive.")
+	}
+	// explicit ttl makes expiry absolute again
+	lru.SetWithTTL("a", 1, time.Second)
+	clock.now = clock.now.Add(800 * time.Millisecond)
+	lru.Get("a")
+	clock.now = clock.now.Add(800 * time.Millisecond)
+	if v, _ := lru.Get("a"); v != nil || len(lru.opts.idle) != 0 {
+		t.Fatal("assertion failed, expected absolute expiry.", v)
+	}
+}
+
+func TestLRUIdleStatePruned(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(8, WithClock(clock))
+		idled *LRU         = NewLRU(8, WithClock(clock), WithExpireAfterAccess(time.Second))
+	)
+	lru.SetWithIdleTTL("a", 1, time.Second)
+	// written without idle ttl
+	lru.Set("a", 2)
+	if len(lru.opts.idle) != 0 {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 0, len(lru.opts.idle))
+	}
+	idled.Lease("a", 0)
+	if _, err := idled.Set("a", 1); err != ELRULEASED || len(idled.opts.idle) != 0 {
+		t.Fatal("assertion failed, expected rejected write to stay unregistered.", err)
+	}
+}
@@ -0,0 +1,302 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Defaults
+const (
+	defaultINVBATCH  = 128
+	defaultINVLINGER = 10 * time.Millisecond
+	defaultINVDEDUP  = 4096
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ InvalidationSource = (*chanSource)(nil)
+)
+
+// InvalidationOp selects what an `Invalidation`
+// does to the cache.
+type InvalidationOp int
+
+// Invalidation operations
+const (
+	InvalidateKEY     InvalidationOp = iota // remove `Key`
+	InvalidateVERSION                       // remove `Key` when older than `Version`
+	InvalidateTAG                           // remove enteries tagged with `Tag`
+)
+
+// Invalidation is an invalidation message, e.g.
+// derived from a database change stream. `ID`
+// identifies the message for deduplication of
+// redeliveries ( e.g. a log offset ); messages
+// without `ID` are never deduplicated.
+type Invalidation struct {
+	ID      string
+	Op      InvalidationOp
+	Key     interface{}
+	Tag     interface{}
+	Version uint64
+}
+
+// InvalidationSource is protocol definition for
+// streams of invalidation messages, e.g. a thin
+// wrapper around a Kafka consumer.
+type InvalidationSource interface {
+	// Fetch blocks until the next message is
+	// available or `ctx` is done. It returns
+	// `io.EOF` once the stream is exhausted.
+	Fetch(ctx context.Context) (Invalidation, error)
+	// Commit acknowledges all messages fetched
+	// so far; it's called once they are applied.
+	Commit(ctx context.Context) error
+}
+
+// InvalidationOptions configures `InvalidationConsumer`.
+// Messages are applied in batches of up to `Batch`
+// messages, waiting at most `Linger` for a batch to
+// fill. The ids of the last `Dedup` messages are
+// remembered to drop redeliveries.
+type InvalidationOptions struct {
+	Batch  int
+	Linger time.Duration
+	Dedup  int
+}
+
+// InvalidationConsumer keeps an `LRU` coherent with
+// a stream of invalidation messages. Batches are
+// committed only after they are applied, so that
+// messages are delivered at least once; since
+// applying a message is idempotent, redeliveries
+// are harmless.
+type InvalidationConsumer struct {
+	lru    *LRU
+	src    InvalidationSource
+	opts   InvalidationOptions
+	seen   map[string]struct{}
+	recent []string
+	next   int
+}
+
+// chanSource is an `InvalidationSource` reading a
+// channel.
+type chanSource struct {
+	ch <-chan Invalidation
+}
+
+// - MARK: Alloc/Init section.
+
+// NewInvalidationConsumer allocates and initializes
+// a new `InvalidationConsumer` applying messages of
+// `src` to `lru` and returns a pointer to it. Zero
+// options are set to their defaults ( by default
+// batches of 128 messages lingering 10ms, and 4096
+// remembered ids ).
+func NewInvalidationConsumer(lru *LRU, src InvalidationSource, opts InvalidationOptions) *InvalidationConsumer {
+	if opts.Batch <= 0 {
+		opts.Batch = defaultINVBATCH
+	}
+	if opts.Linger <= 0 {
+		opts.Linger = defaultINVLINGER
+	}
+	if opts.Dedup <= 0 {
+		opts.Dedup = defaultINVDEDUP
+	}
+	return &InvalidationConsumer{
+		lru:    lru,
+		src:    src,
+		opts:   opts,
+		seen:   make(map[string]struct{}, opts.Dedup),
+		recent: make([]string, opts.Dedup),
+	}
+}
+
+// ChanSource returns an `InvalidationSource` reading
+// messages from `ch` until it's closed. Commits are
+// no-ops.
+func ChanSource(ch <-chan Invalidation) InvalidationSource {
+	return &chanSource{ch}
+}
+
+// - MARK: InvalidationConsumer section.
+
+// Run consumes messages until the source is
+// exhausted, in which case it returns nil, or until
+// `ctx` is done or the source fails. Messages fetched
+// but not committed by then are applied nonetheless.
+func (ic *InvalidationConsumer) Run(ctx context.Context) (err error) {
+	var (
+		batch []Invalidation
+		done  bool
+	)
+	for !done {
+		if batch, err = ic.fetch(ctx); err != nil {
+			done = true
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		ic.lru.Invalidate(ic.dedup(batch)...)
+		if err == nil {
+			err = ic.src.Commit(ctx)
+			done = err != nil
+		}
+	}
+	return err
+}
+
+// fetch blocks for the first message of a batch and
+// collects further ones until the batch is full or
+// lingered for long enough. It returns the messages
+// fetched so far along with the error of the source.
+func (ic *InvalidationConsumer) fetch(ctx context.Context) (batch []Invalidation, err error) {
+	var (
+		inv    Invalidation
+		linger context.Context
+		cancel context.CancelFunc
+	)
+	if inv, err = ic.src.Fetch(ctx); err != nil {
+		return nil, err
+	}
+	batch = append(make([]Invalidation, 0, ic.opts.Batch), inv)
+	linger, cancel = context.WithTimeout(ctx, ic.opts.Linger)
+	defer cancel()
+	for len(batch) < ic.opts.Batch {
+		if inv, err = ic.src.Fetch(linger); err != nil {
+			if ctx.Err() == nil && linger.Err() != nil {
+				// lingered long enough
+				err = nil
+			}
+			break
+		}
+		batch = append(batch, inv)
+	}
+	return batch, err
+}
+
+// dedup filters out messages of `batch` whose ids
+// were seen recently and remembers the others.
+func (ic *InvalidationConsumer) dedup(batch []Invalidation) (fresh []Invalidation) {
+	fresh = batch[:0]
+	for _, inv := range batch {
+		if inv.ID != "" {
+			if _, ok := ic.seen[inv.ID]; ok {
+				continue
+			}
+			delete(ic.seen, ic.recent[ic.next])
+			ic.recent[ic.next] = inv.ID
+			ic.next = (ic.next + 1) % len(ic.recent)
+			ic.seen[inv.ID] = struct{}{}
+		}
+		fresh = append(fresh, inv)
+	}
+	return fresh
+}
+
+// - MARK: chanSource section.
+
+// Fetch conforms to `InvalidationSource`.
+func (cs *chanSource) Fetch(ctx context.Context) (Invalidation, error) {
+	select {
+	case inv, ok := <-cs.ch:
+		if !ok {
+			return Invalidation{}, io.EOF
+		}
+		return inv, nil
+	case <-ctx.Done():
+		return Invalidation{}, ctx.Err()
+	}
+}
+
+// Commit conforms to `InvalidationSource`.
+func (cs *chanSource) Commit(ctx context.Context) error {
+	return nil
+}
+
+// - MARK: LRU section.
+
+// Invalidate applies `invs` in one go and returns
+// the number of removed enteries. Version based
+// invalidations leave enteries at `Version` or newer
+// in place and record a tombstone when tombstones
+// are enabled ( see `RemoveVersion` ), so that late
+// writes of stale versions are rejected. Tag based
+// invalidations remove enteries tagged with `Tag` (
+// see `Tag` ).
+func (lru *LRU) Invalidate(invs ...Invalidation) (n int) {
+	var (
+		item *LRUItem
+	)
+	lru.mu.Lock()
+	for _, inv := range invs {
+		switch inv.Op {
+		case InvalidateKEY:
+			if lru.remove(inv.Key) {
+				n++
+			}
+		case InvalidateVERSION:
+			if lru.opts.tombstoneTTL > 0 {
+				lru.bury(inv.Key, inv.Version)
+			}
+			if item = lru.read(inv.Key); item != nil && item.version < inv.Version {
+				lru.unlink(lru.lookup[inv.Key], EvictREMOVED)
+				n++
+			}
+		case InvalidateTAG:
+			n += lru.invalidateTag(inv.Tag)
+		}
+	}
+	lru.debugValidate()
+	lru.mu.Unlock()
+	return n
+}
+
+// invalidateTag removes enteries tagged with `tag`
+// and returns their number. Note, this routine is
+// not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) invalidateTag(tag interface{}) (n int) {
+	var (
+		victims []*list.Element
+	)
+	for key, tags := range lru.opts.tags {
+		if _, ok := tags[tag]; ok {
+			victims = append(victims, lru.lookup[key])
+		}
+	}
+	for _, elem := range victims {
+		lru.unlink(elem, EvictREMOVED)
+		n++
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUInvalidate(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithTombstones(time.Minute))
+	)
+	lru.Set("a", 1)
+	lru.SetVersion("b", 2, 5)
+	lru.SetVersion("c", 3, 5)
+	lru.Set("d", 4)
+	lru.Set("e", 5)
+	lru.Tag("d", "users")
+	lru.Tag("e", "users")
+	n := lru.Invalidate(
+		Invalidation{Op: InvalidateKEY, Key: "a"},
+		Invalidation{Op: InvalidateVERSION, Key: "b", Version: 5},
+		Invalidation{Op: InvalidateVERSION, Key: "c", Version: 6},
+		Invalidation{Op: InvalidateTAG, Tag: "users"},
+	)
+	if n != 4 || lru.Len() != 1 || lru.Read("b") != 2 {
+		t.Fatalf("assertion failed, expected equal with value(4) - got value(%d).", n)
+	}
+	if applied, _ := lru.SetVersion("c", 3, 5); applied {
+		t.Fatal("assertion failed, expected stale write to be rejected.")
+	}
+}
+
+func TestInvalidationConsumer(t *testing.T) {
+	var (
+		lru *LRU              = NewLRU(8)
+		ch  chan Invalidation = make(chan Invalidation, 8)
+		ic  *InvalidationConsumer
+	)
+	ic = NewInvalidationConsumer(lru, ChanSource(ch), InvalidationOptions{Batch: 2})
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	ch <- Invalidation{ID: "1", Op: InvalidateKEY, Key: "a"}
+	ch <- Invalidation{ID: "2", Op: InvalidateKEY, Key: "a"}
+	// redelivery of a message seen already
+	ch <- Invalidation{ID: "1", Op: InvalidateKEY, Key: "b"}
+	close(ch)
+	if err := ic.Run(context.Background()); err != nil {
+		t.Fatal("assertion failed, expected nil error.", err)
+	}
+	if lru.Read("a") != nil || lru.Read("b") != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+}
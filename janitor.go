@@ -0,0 +1,155 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// janitor periodically reaps expired enteries.
+type janitor struct {
+	interval time.Duration
+	stop     chan struct{}
+	once     sync.Once
+	next     int64 // next inline reap, see `janitor.due`
+}
+
+// - MARK: Alloc/Init section.
+
+// NewLRUWithTTL allocates and initializes a new
+// `LRU` whose enteries expire after `ttl` unless
+// written with an explicit one ( see `WithDefaultTTL` ),
+// and whose expired enteries are reaped every
+// `cleanupInterval` by a janitor goroutine ( see
+// `WithJanitor` ), and returns a pointer to it. The
+// janitor must be stopped with `Stop` once the cache
+// is no longer used.
+func NewLRUWithTTL(capacity int, ttl time.Duration, cleanupInterval time.Duration, opts ...Option) *LRU {
+	return NewLRU(capacity, append([]Option{WithDefaultTTL(ttl), WithJanitor(cleanupInterval)}, opts...)...)
+}
+
+// WithDefaultTTL makes enteries written by `Set`, and
+// loaded with zero ttl, expire after `ttl`. Note, when
+// `ttl <= 0` holds true, they never expire.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(lru *LRU) {
+		lru.opts.defaultTTL = ttl
+	}
+}
+
+// WithJanitor spawns a goroutine reaping expired
+// enteries every `interval` ( see `ReapExpired` ),
+// so that they don't linger until accessed. It runs
+// until `Stop` is called. Under `GOOS=js` and
+// `GOOS=wasip1`, where goroutines only run while the
+// host calls into the module, enteries are reaped
+// by writes once `interval` has passed instead.
+// The goroutine is spawned by `NewLRU` once every
+// option has been applied. Note, when `interval <= 0`
+// holds true, no janitor is spawned.
+func WithJanitor(interval time.Duration) Option {
+	return func(lru *LRU) {
+		if interval <= 0 || lru.opts.janitor != nil {
+			return
+		}
+		lru.opts.janitor = &janitor{interval: interval, stop: make(chan struct{})}
+	}
+}
+
+// - MARK: janitor section.
+
+// run reaps expired enteries of `lru` until stopped.
+func (j *janitor) run(lru *LRU) {
+	var (
+		ticker *time.Ticker = time.NewTicker(j.interval)
+	)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			lru.ReapExpired()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+// - MARK: LRU section.
+
+// Stop stops the janitor goroutine and the one of
+// expiration listeners ( see `OnExpire` ), if any;
+// it's safe to call more than once. The cache
+// remains usable afterwards.
+func (lru *LRU) Stop() {
+	if j := lru.opts.janitor; j != nil {
+		j.once.Do(func() { close(j.stop) })
+	}
+	lru.mu.Lock()
+	es := lru.opts.expire
+	if es != nil {
+		es.stopped, es.pending = true, nil
+	}
+	lru.mu.Unlock()
+	if es != nil {
+		es.once.Do(func() { close(es.stop) })
+	}
+}
+
+// ReapExpired removes expired enteries and returns
+// their number. In read-through mode, enteries still
+// within grace period ( see `WithGrace` ) are kept.
+// With `WithTimingWheel`, only enteries whose
+// expiration is due are visited.
+func (lru *LRU) ReapExpired() (n int) {
+	lru.mu.Lock()
+	n = lru.reapExpired(lru.now())
+	lru.debugValidate()
+	lru.mu.Unlock()
+	return n
+}
+
+// reapExpired is the unprotected variant of
+// `ReapExpired`. Note, this routine is not
+// protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) reapExpired(now int64) (n int) {
+	var (
+		next *list.Element
+		item *LRUItem
+	)
+	if lru.opts.wheel != nil {
+		return lru.reapWheel(now)
+	}
+	for elem := lru.items.Front(); elem != nil; elem = next {
+		next = elem.Next()
+		item = elem.Value.(*LRUItem)
+		if !item.expired(now) || (lru.opts.loader != nil && lru.inGrace(item, now)) {
+			continue
+		}
+		lru.unlink(elem, lru.expiredReason(item))
+		n++
+	}
+	return n
+}
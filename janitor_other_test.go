@@ -0,0 +1,70 @@
+//go:build !js && !wasip1
+
+/* MIT License
+*
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+*
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+*
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+ */
+
+package cache
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestLRUWithTTL(t *testing.T) {
+	var (
+		goroutines int  = runtime.NumGoroutine()
+		lru        *LRU = NewLRUWithTTL(8, 5*time.Millisecond, time.Millisecond)
+	)
+	lru.Set("a", 1)
+	lru.SetWithTTL("b", 2, time.Hour)
+	for deadline := time.Now().Add(time.Second); lru.Len() != 1; {
+		if time.Now().After(deadline) {
+			t.Fatal("assertion failed, expected janitor to reap expired enteries.", lru.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if lru.Read("b") != 2 || lru.Stats().Evicted(EvictEXPIRED) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	lru.Stop()
+	lru.Stop()
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines; {
+		if time.Now().After(deadline) {
+			t.Fatal("assertion failed, expected janitor to stop.")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLRUJanitorOptions(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Now().Add(-time.Hour)}
+		lru   *LRU         = NewLRU(8, WithJanitor(time.Millisecond), WithClock(clock))
+	)
+	defer lru.Stop()
+	lru.SetWithTTL("a", 1, time.Minute)
+	time.Sleep(10 * time.Millisecond)
+	if lru.Len() != 1 || lru.Read("a") != 1 {
+		t.Fatal("assertion failed, expected janitor to read time from the configured clock.")
+	}
+}
@@ -0,0 +1,53 @@
+//go:build js || wasip1
+
+/* MIT License
+*
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+*
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+*
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+ */
+
+package cache
+
+// start is a no-op; rather than spawning a
+// goroutine, which would only run while the host
+// calls into the module, writes reap expired
+// enteries, see `janitor.due`.
+func (j *janitor) start(lru *LRU) {}
+
+// due reaps expired enteries of `lru` once the
+// janitor interval has passed since the last
+// reap, unless it was stopped, and returns their
+// number. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (j *janitor) due(lru *LRU) (n int) {
+	var (
+		now int64 = lru.now()
+	)
+	if now < j.next {
+		return 0
+	}
+	select {
+	case <-j.stop:
+		return 0
+	default:
+	}
+	j.next = now + int64(j.interval)
+	return lru.reapExpired(now)
+}
@@ -0,0 +1,58 @@
+//go:build js || wasip1
+
+/* MIT License
+*
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+*
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+*
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+ */
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUInlineJanitor(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRUWithTTL(8, 5*time.Millisecond, time.Second, WithClock(clock))
+	)
+	lru.Set("a", 1)
+	lru.SetWithTTL("b", 2, time.Hour)
+	clock.now = clock.now.Add(10 * time.Millisecond)
+	// due only once the interval has passed
+	lru.Set("c", 3)
+	if lru.Len() != 3 {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 3, lru.Len())
+	}
+	clock.now = clock.now.Add(time.Second)
+	lru.Set("d", 4)
+	if lru.Len() != 2 || lru.Read("b") != 2 || lru.Stats().Evicted(EvictEXPIRED) != 2 {
+		t.Fatal("assertion failed, expected writes to reap expired enteries.", lru.Len())
+	}
+	lru.Stop()
+	clock.now = clock.now.Add(2 * time.Second)
+	lru.SetWithTTL("e", 5, time.Millisecond)
+	clock.now = clock.now.Add(2 * time.Second)
+	lru.Set("f", 6)
+	if lru.Len() != 4 {
+		t.Fatal("assertion failed, expected stopped janitor not to reap.", lru.Len())
+	}
+}
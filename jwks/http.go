@@ -0,0 +1,174 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// document is a JSON Web Key Set ( RFC 7517 ).
+type document struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwk is a JSON Web Key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// HTTPFetcher returns a `FetchFunc` fetching the key
+// set at `url` with `client` ( `http.DefaultClient`
+// when `nil` ). Keys are cached for the `max-age` of
+// the response's Cache-Control header, or `ttl` when
+// absent. RSA, EC ( P-256, P-384, P-521 ) and Ed25519
+// keys are supported; other keys are skipped.
+func HTTPFetcher(client *http.Client, url string, ttl time.Duration) FetchFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (keys map[string]crypto.PublicKey, maxAge time.Duration, err error) {
+		var (
+			req  *http.Request
+			resp *http.Response
+			doc  document
+		)
+		if req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil); err != nil {
+			return nil, 0, err
+		}
+		if resp, err = client.Do(req); err != nil {
+			return nil, 0, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, 0, fmt.Errorf("jwks: unexpected status %d.", resp.StatusCode)
+		}
+		if err = json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+			return nil, 0, err
+		}
+		keys = make(map[string]crypto.PublicKey, len(doc.Keys))
+		for _, k := range doc.Keys {
+			if key, err := k.publicKey(); err == nil && key != nil {
+				keys[k.Kid] = key
+			}
+		}
+		return keys, cacheMaxAge(resp.Header.Get("Cache-Control"), ttl), nil
+	}
+}
+
+// cacheMaxAge returns the max-age of Cache-Control
+// header `h` or `fallback`.
+func cacheMaxAge(h string, fallback time.Duration) time.Duration {
+	for _, directive := range strings.Split(h, ",") {
+		directive = strings.TrimSpace(directive)
+		if v, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+	return fallback
+}
+
+// - MARK: jwk section.
+
+// publicKey decodes `k`; it returns a `nil` key
+// for unsupported key types.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		var (
+			n, e []byte
+			err  error
+		)
+		if n, err = decode(k.N); err != nil {
+			return nil, err
+		}
+		if e, err = decode(k.E); err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var (
+			curve elliptic.Curve
+			x, y  []byte
+			err   error
+		)
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		if x, err = decode(k.X); err != nil {
+			return nil, err
+		}
+		if y, err = decode(k.Y); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		var (
+			x   []byte
+			err error
+		)
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		if x, err = decode(k.X); err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("jwks: invalid Ed25519 key size %d.", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+// decode decodes unpadded base64url `s`.
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,235 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+// Package jwks caches JSON Web Key Sets with
+// refresh-ahead and kid-based lookup.
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mitghi/cache"
+)
+
+// Error messages
+var (
+	EUNKNOWNKID error = errors.New("jwks: unknown key id.")
+)
+
+// Defaults
+const (
+	refreshTIMEOUT = 30 * time.Second
+)
+
+// Option configures a `Set`.
+type Option func(*Set)
+
+// FetchFunc fetches the current keys by kid
+// along with the duration they may be cached for;
+// non-positive durations cache keys forever.
+type FetchFunc func(ctx context.Context) (keys map[string]crypto.PublicKey, ttl time.Duration, err error)
+
+// Set caches keys of a key set in an `LRU`. Keys
+// are refreshed in the background once they are
+// close to expiry and refetched on lookups of
+// unknown kids, which happen right after a key
+// rotation. Concurrent fetches are coalesced.
+type Set struct {
+	mu           sync.Mutex
+	lru          *cache.LRU
+	fetch        FetchFunc
+	clock        cache.Clock
+	refreshAhead time.Duration
+	minInterval  time.Duration
+	call         *fetchCall
+	fetched      time.Time // last successful fetch
+	expires      time.Time // expiry of the last fetched keys
+}
+
+// kidKey is the cache key of a key with id `kid`
+// of set `s`, so that sets sharing a cache never
+// serve each other's keys.
+type kidKey struct {
+	s   *Set
+	kid string
+}
+
+// fetchCall is an in-flight fetch.
+type fetchCall struct {
+	done chan struct{}
+	err  error
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// Now conforms to `cache.Clock`.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// - MARK: Alloc/Init section.
+
+// WithClock sets the time source of the set. It
+// should match the clock of the backing cache.
+func WithClock(clock cache.Clock) Option {
+	return func(s *Set) {
+		s.clock = clock
+	}
+}
+
+// WithRefreshAhead refreshes keys in the background
+// once lookups happen within `d` of their expiry.
+func WithRefreshAhead(d time.Duration) Option {
+	return func(s *Set) {
+		s.refreshAhead = d
+	}
+}
+
+// WithMinInterval bounds how often lookups of unknown
+// kids may trigger a refetch ( 5 seconds by default ),
+// so that forged kids can't hammer the key server.
+func WithMinInterval(d time.Duration) Option {
+	return func(s *Set) {
+		s.minInterval = d
+	}
+}
+
+// New allocates and initializes a new `Set` struct
+// caching keys returned by `fetch` in `lru` and
+// returns a pointer to it.
+func New(lru *cache.LRU, fetch FetchFunc, opts ...Option) (s *Set) {
+	s = &Set{lru: lru, fetch: fetch, clock: systemClock{}, minInterval: 5 * time.Second}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// - MARK: Set section.
+
+// Key returns the public key with id `kid`. Unknown
+// kids trigger a refetch unless the keys were fetched
+// within the minimum interval, in which case
+// `EUNKNOWNKID` is returned.
+func (s *Set) Key(ctx context.Context, kid string) (key crypto.PublicKey, err error) {
+	var (
+		cached interface{}
+		now    time.Time
+		call   *fetchCall
+	)
+	if cached, _ = s.lru.Get(kidKey{s, kid}); cached != nil {
+		now = s.clock.Now()
+		s.mu.Lock()
+		if s.refreshAhead > 0 && s.call == nil && !s.expires.IsZero() && !now.Before(s.expires.Add(-s.refreshAhead)) {
+			// refresh outlives the lookup but keeps its
+			// tracing and auth metadata
+			s.start(cache.DetachContext(ctx, refreshTIMEOUT))
+		}
+		s.mu.Unlock()
+		return cached.(crypto.PublicKey), nil
+	}
+	s.mu.Lock()
+	if call = s.call; call == nil {
+		if !s.fetched.IsZero() && s.clock.Now().Sub(s.fetched) < s.minInterval {
+			s.mu.Unlock()
+			return nil, EUNKNOWNKID
+		}
+		call = s.start(ctx, nil)
+	}
+	s.mu.Unlock()
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	if cached, _ = s.lru.Get(kidKey{s, kid}); cached != nil {
+		return cached.(crypto.PublicKey), nil
+	}
+	return nil, EUNKNOWNKID
+}
+
+// Refresh fetches the keys unless a fetch is already
+// in flight, and waits for it.
+func (s *Set) Refresh(ctx context.Context) error {
+	var (
+		call *fetchCall
+	)
+	s.mu.Lock()
+	if call = s.call; call == nil {
+		call = s.start(ctx, nil)
+	}
+	s.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// start begins a fetch. Keys of previous fetches
+// are kept until their ttl elapses so that tokens
+// signed before a rotation still verify. `cancel`,
+// when non-nil, is called once the fetch completes.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly exposed.
+func (s *Set) start(ctx context.Context, cancel context.CancelFunc) (call *fetchCall) {
+	call = &fetchCall{done: make(chan struct{})}
+	s.call = call
+	go func() {
+		if cancel != nil {
+			defer cancel()
+		}
+		var (
+			keys map[string]crypto.PublicKey
+			ttl  time.Duration
+			now  time.Time
+		)
+		keys, ttl, call.err = s.fetch(ctx)
+		now = s.clock.Now()
+		if call.err == nil {
+			for kid, key := range keys {
+				s.lru.SetWithTTL(kidKey{s, kid}, key, ttl)
+			}
+		}
+		s.mu.Lock()
+		if call.err == nil {
+			s.fetched = now
+			s.expires = time.Time{}
+			if ttl > 0 {
+				s.expires = now.Add(ttl)
+			}
+		}
+		s.call = nil
+		s.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
@@ -0,0 +1,154 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mitghi/cache"
+	"github.com/mitghi/cache/cachetest"
+)
+
+func TestSetRotation(t *testing.T) {
+	var (
+		clock   *cachetest.FakeClock = cachetest.NewFakeClock(time.Unix(0, 0))
+		ctx                          = context.Background()
+		fetches int32
+		kid     atomic.Value
+		s       *Set
+		wg      sync.WaitGroup
+	)
+	kid.Store("k1")
+	s = New(cache.NewLRU(8, cache.WithClock(clock)), func(ctx context.Context) (map[string]crypto.PublicKey, time.Duration, error) {
+		atomic.AddInt32(&fetches, 1)
+		time.Sleep(5 * time.Millisecond)
+		id := kid.Load().(string)
+		return map[string]crypto.PublicKey{id: id}, time.Hour, nil
+	}, WithClock(clock), WithMinInterval(time.Minute))
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if key, err := s.Key(ctx, "k1"); key != "k1" || err != nil {
+				t.Error("assertion failed, inconsistent state. expected equal.", key, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if atomic.LoadInt32(&fetches) != 1 {
+		t.Fatal("assertion failed, expected coalesced fetch.", fetches)
+	}
+	// unknown kids are rate limited
+	kid.Store("k2")
+	if _, err := s.Key(ctx, "k2"); err != EUNKNOWNKID || atomic.LoadInt32(&fetches) != 1 {
+		t.Fatal("assertion failed, expected rate limited refetch.", err, fetches)
+	}
+	clock.Advance(2 * time.Minute)
+	if key, err := s.Key(ctx, "k2"); key != "k2" || err != nil {
+		t.Fatal("assertion failed, expected refetch on rotation.", key, err)
+	}
+	// keys of previous fetches remain valid
+	if key, err := s.Key(ctx, "k1"); key != "k1" || err != nil {
+		t.Fatal("assertion failed, expected rotated key still cached.", key, err)
+	}
+}
+
+func TestSetSharedCache(t *testing.T) {
+	var (
+		ctx                      = context.Background()
+		lru           *cache.LRU = cache.NewLRU(8)
+		issuer, rogue *Set
+	)
+	issuer = New(lru, func(ctx context.Context) (map[string]crypto.PublicKey, time.Duration, error) {
+		return map[string]crypto.PublicKey{"k1": "issuer"}, time.Hour, nil
+	})
+	rogue = New(lru, func(ctx context.Context) (map[string]crypto.PublicKey, time.Duration, error) {
+		return map[string]crypto.PublicKey{"k1": "rogue"}, time.Hour, nil
+	})
+	if key, err := rogue.Key(ctx, "k1"); key != "rogue" || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", key, err)
+	}
+	if key, err := issuer.Key(ctx, "k1"); key != "issuer" || err != nil {
+		t.Fatal("assertion failed, expected keys of sets sharing a cache to be isolated.", key, err)
+	}
+}
+
+func TestSetRefreshAhead(t *testing.T) {
+	var (
+		clock   *cachetest.FakeClock = cachetest.NewFakeClock(time.Unix(0, 0))
+		ctx                          = context.Background()
+		fetches int32
+		s       *Set
+	)
+	s = New(cache.NewLRU(8, cache.WithClock(clock)), func(ctx context.Context) (map[string]crypto.PublicKey, time.Duration, error) {
+		atomic.AddInt32(&fetches, 1)
+		return map[string]crypto.PublicKey{"k": "k"}, time.Hour, nil
+	}, WithClock(clock), WithRefreshAhead(10*time.Minute))
+	if err := s.Refresh(ctx); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	clock.Advance(55 * time.Minute)
+	if key, err := s.Key(ctx, "k"); key != "k" || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", key, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&fetches) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("assertion failed, expected background refresh.", fetches)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	var (
+		rsaKey *rsa.PublicKey    = &rsa.PublicKey{N: big.NewInt(0).SetBytes([]byte{0xc3, 0x55, 0x01}), E: 65537}
+		edKey  ed25519.PublicKey = make(ed25519.PublicKey, ed25519.PublicKeySize)
+		enc                      = base64.RawURLEncoding.EncodeToString
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=600")
+		fmt.Fprintf(w, `{"keys":[{"kty":"RSA","kid":"r","n":%q,"e":"AQAB"},{"kty":"OKP","crv":"Ed25519","kid":"e","x":%q},{"kty":"oct","kid":"s","k":"c2VjcmV0"}]}`,
+			enc(rsaKey.N.Bytes()), enc(edKey))
+	}))
+	defer srv.Close()
+	keys, ttl, err := HTTPFetcher(srv.Client(), srv.URL, time.Minute)(context.Background())
+	if err != nil || ttl != 10*time.Minute || len(keys) != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", keys, ttl, err)
+	}
+	if !rsaKey.Equal(keys["r"]) || !edKey.Equal(keys["e"]) {
+		t.Fatal("assertion failed, expected decoded keys.", keys)
+	}
+}
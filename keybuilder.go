@@ -0,0 +1,133 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Key part tags
+const (
+	kbTagSTRING byte = iota + 1
+	kbTagBYTES
+	kbTagINT
+	kbTagUINT
+	kbTagBOOL
+	kbTagHASH
+)
+
+// KeyBuilder composes cache keys from multiple
+// parts. Each part is tagged and length prefixed
+// so distinct part sequences never collide ( e.g.
+// ("ab", "c") and ("a", "bc") ). The zero value
+// is ready to use; a builder can be reused after
+// calling `Reset`.
+type KeyBuilder struct {
+	buf []byte
+}
+
+// - MARK: Alloc/Init section.
+
+// NewKeyBuilder allocates and initializes a new
+// `KeyBuilder` struct and returns a pointer to it.
+func NewKeyBuilder() *KeyBuilder {
+	return &KeyBuilder{buf: make([]byte, 0, 64)}
+}
+
+// - MARK: KeyBuilder section.
+
+// String appends a string part.
+func (kb *KeyBuilder) String(s string) *KeyBuilder {
+	kb.buf = append(kb.buf, kbTagSTRING)
+	kb.buf = binary.AppendUvarint(kb.buf, uint64(len(s)))
+	kb.buf = append(kb.buf, s...)
+	return kb
+}
+
+// Bytes appends a byte slice part.
+func (kb *KeyBuilder) Bytes(b []byte) *KeyBuilder {
+	kb.buf = append(kb.buf, kbTagBYTES)
+	kb.buf = binary.AppendUvarint(kb.buf, uint64(len(b)))
+	kb.buf = append(kb.buf, b...)
+	return kb
+}
+
+// Int appends a signed integer part.
+func (kb *KeyBuilder) Int(i int64) *KeyBuilder {
+	kb.buf = append(kb.buf, kbTagINT)
+	kb.buf = binary.BigEndian.AppendUint64(kb.buf, uint64(i))
+	return kb
+}
+
+// Uint appends an unsigned integer part.
+func (kb *KeyBuilder) Uint(u uint64) *KeyBuilder {
+	kb.buf = append(kb.buf, kbTagUINT)
+	kb.buf = binary.BigEndian.AppendUint64(kb.buf, u)
+	return kb
+}
+
+// Bool appends a boolean part.
+func (kb *KeyBuilder) Bool(b bool) *KeyBuilder {
+	kb.buf = append(kb.buf, kbTagBOOL)
+	if b {
+		kb.buf = append(kb.buf, 1)
+	} else {
+		kb.buf = append(kb.buf, 0)
+	}
+	return kb
+}
+
+// Hash appends a 64 bit hash of `v` computed over
+// its Go-syntax representation. It is suitable for
+// structs, slices and maps of plain values; note,
+// pointers are hashed by address rather than by
+// the value they point to.
+func (kb *KeyBuilder) Hash(v interface{}) *KeyBuilder {
+	kb.buf = append(kb.buf, kbTagHASH)
+	kb.buf = binary.BigEndian.AppendUint64(kb.buf, hashValue(v))
+	return kb
+}
+
+// Key returns the composed key as a string
+// suitable for use in `map` based caches.
+func (kb *KeyBuilder) Key() string {
+	return string(kb.buf)
+}
+
+// Sum64 returns the 64 bit FNV-1a hash of the
+// composed key, suitable for `uint64` keyed
+// caches.
+func (kb *KeyBuilder) Sum64() uint64 {
+	var (
+		h = fnv.New64a()
+	)
+	h.Write(kb.buf)
+	return h.Sum64()
+}
+
+// Reset clears all parts while retaining the
+// underlying buffer for reuse.
+func (kb *KeyBuilder) Reset() {
+	kb.buf = kb.buf[:0]
+}
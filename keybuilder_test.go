@@ -0,0 +1,60 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestKeyBuilder(t *testing.T) {
+	type query struct {
+		Name  string
+		Limit int
+	}
+	var (
+		kb   *KeyBuilder = NewKeyBuilder()
+		a, b string
+	)
+	a = kb.String("ab").String("c").Key()
+	kb.Reset()
+	b = kb.String("a").String("bc").Key()
+	if a == b {
+		t.Fatal("assertion failed, expected unequal keys.")
+	}
+	kb.Reset()
+	a = kb.String("user").Int(42).Hash(query{"x", 10}).Key()
+	kb.Reset()
+	b = kb.String("user").Int(42).Hash(query{"x", 10}).Key()
+	if a != b {
+		t.Fatal("assertion failed, expected equal keys.")
+	}
+	kb.Reset()
+	if kb.String("user").Int(42).Hash(query{"x", 11}).Key() == a {
+		t.Fatal("assertion failed, expected unequal keys.")
+	}
+	kb.Reset()
+	if kb.Int(1).Key() == NewKeyBuilder().Uint(1).Key() {
+		t.Fatal("assertion failed, expected unequal keys.")
+	}
+	if NewKeyBuilder().String("k").Sum64() != NewKeyBuilder().String("k").Sum64() {
+		t.Fatal("assertion failed, expected equal hashes.")
+	}
+}
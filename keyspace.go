@@ -0,0 +1,93 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Notification is a key-space notification in
+// Redis format, i.e. a message published on a
+// `__keyspace@<db>__:<key>` or
+// `__keyevent@<db>__:<event>` channel.
+type Notification struct {
+	Channel string
+	Message string
+}
+
+// - MARK: Event section.
+
+// Name returns the Redis event name of `ev`, i.e.
+// `set`, `del`, `expired` or `evicted`. It returns
+// an empty string when `ev` has no Redis
+// counterpart ( e.g. a replaced value, which Redis
+// reports only as `set` ).
+func (ev Event) Name() string {
+	if ev.Type == EventSET {
+		return "set"
+	}
+	switch ev.Reason {
+	case EvictREMOVED, EvictPURGED:
+		return "del"
+	case EvictEXPIRED, EvictIDLE:
+		return "expired"
+	case EvictCAPACITY:
+		return "evicted"
+	}
+	return ""
+}
+
+// Notifications returns the key-space and key-event
+// notifications Redis publishes for `ev` in database
+// `db`, so that tooling listening for them works
+// unchanged. It returns `nil` when `ev` has no
+// Redis counterpart. Namespaced keys are formatted
+// as `<ns>:<id>`.
+func (ev Event) Notifications(db int) []Notification {
+	var (
+		name string = ev.Name()
+		key  string = redisKey(ev.Key)
+		sdb  string = strconv.Itoa(db)
+	)
+	if name == "" {
+		return nil
+	}
+	return []Notification{
+		{Channel: "__keyspace@" + sdb + "__:" + key, Message: name},
+		{Channel: "__keyevent@" + sdb + "__:" + name, Message: key},
+	}
+}
+
+// redisKey formats `key` as a Redis key.
+func redisKey(key interface{}) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case []byte:
+		return string(k)
+	case compositeKey:
+		return redisKey(k.ns) + ":" + redisKey(k.id)
+	}
+	return fmt.Sprint(key)
+}
@@ -0,0 +1,58 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventNotifications(t *testing.T) {
+	var (
+		lru    *LRU = NewLRU(2)
+		events <-chan Event
+		cancel func()
+		got    []Notification
+	)
+	events, cancel = lru.Subscribe(16)
+	defer cancel()
+	lru.Set2("user", 42, "a")
+	lru.Set("a", "a")
+	lru.Set("b", "b")
+	lru.Set("b", "c")
+	lru.Remove("b")
+	for len(events) > 0 {
+		got = append(got, (<-events).Notifications(0)...)
+	}
+	expected := []Notification{
+		{"__keyspace@0__:user:42", "set"}, {"__keyevent@0__:set", "user:42"},
+		{"__keyspace@0__:a", "set"}, {"__keyevent@0__:set", "a"},
+		{"__keyspace@0__:user:42", "evicted"}, {"__keyevent@0__:evicted", "user:42"},
+		{"__keyspace@0__:b", "set"}, {"__keyevent@0__:set", "b"},
+		{"__keyspace@0__:b", "set"}, {"__keyevent@0__:set", "b"},
+		{"__keyspace@0__:b", "del"}, {"__keyevent@0__:del", "b"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("assertion failed, inconsistent state. expected equal.\n%v\n%v", got, expected)
+	}
+}
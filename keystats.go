@@ -0,0 +1,123 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// KeyStat is the estimated access history of a
+// key, see `WithKeyStats`.
+type KeyStat struct {
+	Accesses int       // lookups, saturating at 255 and aging over time
+	LastSeen time.Time // last lookup
+}
+
+// keyStats is a bounded sketch of per key access
+// frequencies and last lookup times.
+type keyStats struct {
+	freq *countMin
+	seen [sketchDEPTH][]int64
+}
+
+// - MARK: Alloc/Init section.
+
+// WithKeyStats retains compact per key statistics
+// ( see `KeyStat` ) of about `size` keys in a sketch
+// that outlives enteries, so that evicted keys that
+// are written again resume with a realistic access
+// count ( see `LRUItem.C` ) and admission policies
+// get frequency estimates regardless of churn. It
+// costs about 40 bytes per key. Note, when
+// `size <= 0` holds true, it's set to the capacity.
+func WithKeyStats(size int) Option {
+	return func(lru *LRU) {
+		if size <= 0 {
+			size = lru.capacity + 1
+		}
+		lru.opts.keyStats = newKeyStats(size)
+	}
+}
+
+// newKeyStats allocates and initializes a new
+// `keyStats` struct sized for about `n` distinct
+// keys and returns a pointer to it.
+func newKeyStats(n int) (ks *keyStats) {
+	ks = &keyStats{freq: newCountMin(n)}
+	for i, _ := range ks.seen {
+		ks.seen[i] = make([]int64, len(ks.freq.rows[i]))
+	}
+	return ks
+}
+
+// - MARK: LRU section.
+
+// KeyStats returns the estimated access history of
+// `key` and `false` when `key` was never looked up (
+// or its history was reset ). Estimates may exceed
+// actual values due to hash collisions.
+func (lru *LRU) KeyStats(key interface{}) (stat KeyStat, ok bool) {
+	var (
+		seen int64
+	)
+	if lru.opts.keyStats == nil {
+		return stat, false
+	}
+	lru.mu.Lock()
+	stat.Accesses, seen = lru.opts.keyStats.estimate(hashKey(key))
+	lru.mu.Unlock()
+	if seen == 0 {
+		return KeyStat{}, false
+	}
+	stat.LastSeen = time.Unix(0, seen)
+	return stat, true
+}
+
+// - MARK: keyStats section.
+
+// record records a lookup of hash `h` at `now`.
+func (ks *keyStats) record(h uint64, now int64) {
+	ks.freq.add(h)
+	for i, _ := range ks.seen {
+		if c := &ks.seen[i][ks.freq.index(h, i)]; *c < now {
+			*c = now
+		}
+	}
+}
+
+// estimate returns the estimated number of lookups
+// of hash `h` and the time of its last lookup.
+func (ks *keyStats) estimate(h uint64) (accesses int, seen int64) {
+	seen = ks.seen[0][ks.freq.index(h, 0)]
+	for i := 1; i < sketchDEPTH; i++ {
+		seen = min(seen, ks.seen[i][ks.freq.index(h, i)])
+	}
+	return int(ks.freq.estimate(h)), seen
+}
+
+// reset forgets all statistics.
+func (ks *keyStats) reset() {
+	for i, _ := range ks.seen {
+		clear(ks.freq.rows[i])
+		clear(ks.seen[i])
+	}
+	ks.freq.adds = 0
+}
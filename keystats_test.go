@@ -0,0 +1,66 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUKeyStats(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(10, 0)}
+		lru   *LRU         = NewLRU(2, WithClock(clock), WithKeyStats(64))
+	)
+	if _, ok := lru.KeyStats("a"); ok {
+		t.Fatal("assertion failed, expected no statistics.")
+	}
+	lru.Set("a", 1)
+	for i := 0; i < 5; i++ {
+		lru.Get("a")
+	}
+	clock.now = clock.now.Add(time.Second)
+	lru.Get("a")
+	// evict "a"
+	lru.Set("b", 2)
+	lru.Set("c", 3)
+	lru.Set("d", 4)
+	if lru.Read("a") != nil {
+		t.Fatal("assertion failed, expected entery to be evicted.")
+	}
+	stat, ok := lru.KeyStats("a")
+	if !ok || stat.Accesses < 6 || !stat.LastSeen.Equal(time.Unix(11, 0)) {
+		t.Fatal("assertion failed, expected statistics to outlive the entery.", stat)
+	}
+	lru.Set("a", 1)
+	lru.mu.Lock()
+	count := lru.read("a").Count
+	lru.mu.Unlock()
+	if count < 6 {
+		t.Fatalf("assertion failed, expected count to resume - got value(%d).", count)
+	}
+	lru.ResetStats()
+	if _, ok := lru.KeyStats("a"); ok {
+		t.Fatal("assertion failed, expected statistics to be reset.")
+	}
+}
@@ -0,0 +1,128 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// lease is an exclusive right to write an entery,
+// see `Lease`.
+type lease struct {
+	token   uint64
+	expires int64
+}
+
+// - MARK: LRU section.
+
+// Lease grants the caller the exclusive right to
+// write `key` for `ttl` and returns its token, or
+// `false` when another lease of `key` is active.
+// While the lease is active, writes of `key` fail
+// with `ELRULEASED` and loaded values of `key` are
+// served without being cached; the holder writes
+// with `SetLeased` or gives up with `Release`. This
+// mirrors memcached leases to let exactly one caller
+// recompute a missing or stale entery. Note, when
+// `ttl <= 0` holds true, the lease never expires.
+func (lru *LRU) Lease(key interface{}, ttl time.Duration) (token uint64, ok bool) {
+	var (
+		now int64
+	)
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if lru.leased(key) {
+		return 0, false
+	}
+	now = lru.now()
+	if lru.opts.leases == nil {
+		lru.opts.leases = make(map[interface{}]lease)
+	} else if len(lru.opts.leases) > lru.capacity {
+		for k, l := range lru.opts.leases {
+			if l.expires != 0 && l.expires <= now {
+				delete(lru.opts.leases, k)
+			}
+		}
+	}
+	lru.opts.leaseSeq++
+	lru.opts.leases[lru.canonical(key)] = lease{token: lru.opts.leaseSeq, expires: lru.deadline(ttl)}
+	return lru.opts.leaseSeq, true
+}
+
+// Release gives up the lease `token` of `key`
+// without writing and returns `true` when it was
+// active.
+func (lru *LRU) Release(key interface{}, token uint64) (ok bool) {
+	lru.mu.Lock()
+	ok = lru.unlease(key, token)
+	lru.mu.Unlock()
+	return ok
+}
+
+// SetLeased writes k/v pair as `Set` does on behalf
+// of the holder of lease `token`, consuming the
+// lease. It fails with `ELRULEASED` unless `token`
+// is the active lease of `key`.
+func (lru *LRU) SetLeased(key interface{}, value interface{}, token uint64) (isNew bool, err error) {
+	if err = lru.checkType(value); err != nil {
+		return false, err
+	}
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if !lru.unlease(key, token) {
+		return false, ELRULEASED
+	}
+	isNew, err = lru.setDefault(key, value)
+	lru.debugValidate()
+	return isNew, err
+}
+
+// leased returns whether `key` has an active lease,
+// dropping it once expired. Note, this routine is
+// not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) leased(key interface{}) bool {
+	var (
+		l  lease
+		ok bool
+	)
+	key = lru.canonical(key)
+	if l, ok = lru.opts.leases[key]; !ok {
+		return false
+	}
+	if l.expires != 0 && l.expires <= lru.now() {
+		delete(lru.opts.leases, key)
+		return false
+	}
+	return true
+}
+
+// unlease drops the lease `token` of `key` and
+// returns `true` when it was active. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) unlease(key interface{}, token uint64) bool {
+	if !lru.leased(key) || lru.opts.leases[lru.canonical(key)].token != token {
+		return false
+	}
+	delete(lru.opts.leases, lru.canonical(key))
+	return true
+}
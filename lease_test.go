@@ -0,0 +1,75 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRULease(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(8, WithClock(clock))
+	)
+	token, ok := lru.Lease("a", time.Second)
+	if !ok {
+		t.Fatal("assertion failed, expected lease to be granted.")
+	}
+	if _, ok := lru.Lease("a", time.Second); ok {
+		t.Fatal("assertion failed, expected second lease to be denied.")
+	}
+	if _, err := lru.Set("a", 1); err != ELRULEASED {
+		t.Fatal("assertion failed, expected write without token to be rejected.", err)
+	}
+	if _, err := lru.SetLeased("a", 1, token+1); err != ELRULEASED {
+		t.Fatal("assertion failed, expected write with wrong token to be rejected.", err)
+	}
+	if _, err := lru.SetLeased("a", 2, token); err != nil || lru.Read("a") != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+	if _, err := lru.Set("a", 3); err != nil {
+		t.Fatal("assertion failed, expected lease to be consumed.", err)
+	}
+	token, _ = lru.Lease("a", time.Second)
+	if !lru.Release("a", token) || lru.Release("a", token) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	lru.Lease("a", time.Second)
+	clock.now = clock.now.Add(time.Second)
+	if _, err := lru.Set("a", 4); err != nil {
+		t.Fatal("assertion failed, expected lease to expire.", err)
+	}
+}
+
+func TestLRULeaseLoader(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			return 1, nil
+		})))
+	)
+	lru.Lease("a", 0)
+	if v, err := lru.Get("a"); v != 1 || err != nil || lru.Read("a") != nil {
+		t.Fatal("assertion failed, expected leased load to be served but not cached.", v, err)
+	}
+}
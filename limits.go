@@ -0,0 +1,126 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "context"
+
+// Defaults
+const (
+	defaultLOADGROUPS = 64
+)
+
+// LoadLimits configures `WithLoadLimits`.
+type LoadLimits struct {
+	Global   int  // concurrent loads overall; unlimited when zero
+	PerGroup int  // concurrent loads per key group; unlimited when zero
+	Groups   int  // number of key groups keys are hashed into ( default 64 )
+	Reject   bool // fail excess loads with `ELRULOADLIMIT` instead of queuing
+}
+
+// loadLimiter enforces `LoadLimits` with counting
+// semaphores.
+type loadLimiter struct {
+	limits LoadLimits
+	global chan struct{}
+	groups []chan struct{}
+}
+
+// - MARK: Alloc/Init section.
+
+// newLoadLimiter allocates and initializes a new
+// `loadLimiter` struct and returns a pointer to it.
+func newLoadLimiter(limits LoadLimits) (ll *loadLimiter) {
+	ll = &loadLimiter{limits: limits}
+	if limits.Global > 0 {
+		ll.global = make(chan struct{}, limits.Global)
+	}
+	if limits.PerGroup > 0 {
+		if limits.Groups <= 0 {
+			ll.limits.Groups = defaultLOADGROUPS
+		}
+		ll.groups = make([]chan struct{}, ll.limits.Groups)
+		for i := range ll.groups {
+			ll.groups[i] = make(chan struct{}, limits.PerGroup)
+		}
+	}
+	return ll
+}
+
+// - MARK: loadLimiter section.
+
+// acquire obtains permission to load `key` and
+// returns a function releasing it. It blocks while
+// limits are exhausted until `ctx` is done, in which
+// case it returns the context's error, unless
+// configured to reject, in which case it returns
+// `ELRULOADLIMIT`. A `nil` limiter, or `unthrottled`
+// being true, always grants permission.
+func (ll *loadLimiter) acquire(ctx context.Context, key interface{}, unthrottled bool) (release func(), err error) {
+	var (
+		group chan struct{}
+	)
+	if ll == nil || unthrottled {
+		return func() {}, nil
+	}
+	if ll.groups != nil {
+		group = ll.groups[hashValue(key)%uint64(len(ll.groups))]
+		if err = ll.take(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+	if ll.global != nil {
+		if err = ll.take(ctx, ll.global); err != nil {
+			if group != nil {
+				<-group
+			}
+			return nil, err
+		}
+	}
+	return func() {
+		if ll.global != nil {
+			<-ll.global
+		}
+		if group != nil {
+			<-group
+		}
+	}, nil
+}
+
+// take acquires a slot of `sem`, blocking until
+// `ctx` is done unless configured to reject.
+func (ll *loadLimiter) take(ctx context.Context, sem chan struct{}) error {
+	if !ll.limits.Reject {
+		select {
+		case sem <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	default:
+		return ELRULOADLIMIT
+	}
+}
@@ -0,0 +1,109 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRULoadLimitsQueue(t *testing.T) {
+	const workers int = 16
+	var (
+		running, peak int32
+		wg            sync.WaitGroup
+		lru           *LRU = NewLRU(32, WithLoadLimits(LoadLimits{Global: 2}), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return key, nil
+		})))
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if value, err := lru.Get(i); value != i || err != nil {
+				t.Error("assertion failed, inconsistent state. expected equal.", value, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Fatalf("assertion failed, expected at most value(2) concurrent loads - got value(%d).", peak)
+	}
+}
+
+func TestLRULoadLimitsReject(t *testing.T) {
+	var (
+		release chan struct{} = make(chan struct{})
+		started chan struct{} = make(chan struct{})
+		lru     *LRU          = NewLRU(32, WithLoadLimits(LoadLimits{PerGroup: 1, Groups: 1, Reject: true}), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return key, nil
+		})))
+		done chan struct{} = make(chan struct{})
+	)
+	go func() {
+		lru.Get("a")
+		close(done)
+	}()
+	<-started
+	if _, err := lru.Get("b"); err != ELRULOADLIMIT {
+		t.Fatal("assertion failed, expected error.", err)
+	}
+	close(release)
+	<-done
+	if stats := lru.Stats(); stats.Rejected != 1 || stats.Loads != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Counters)
+	}
+}
+
+func TestLRULoadLimitsCtx(t *testing.T) {
+	var (
+		release chan struct{} = make(chan struct{})
+		lru     *LRU          = NewLRU(8, WithLoadLimits(LoadLimits{Global: 1}), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			<-release
+			return key, nil
+		})))
+	)
+	defer close(release)
+	go lru.Get(1)
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	// queued behind the first load
+	if _, err := lru.GetCtx(ctx, 2); err != context.DeadlineExceeded {
+		t.Fatal("assertion failed, expected queued load to give up.", err)
+	}
+}
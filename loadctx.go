@@ -0,0 +1,183 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ ContextCache = (*LRU)(nil)
+	_ ContextCache = (*ObjectCache)(nil)
+	_ ContextCache = (*interceptor)(nil)
+	_ ContextCache = (*namespaceCache)(nil)
+	_ ContextCache = (*flightCache)(nil)
+	_ ContextCache = (*TransformCache)(nil)
+)
+
+// LoaderCtx is protocol definition for data
+// sources that back a read-through cache and
+// need the context of the triggering request,
+// e.g. to propagate tracing or auth metadata.
+// It dictates freshness like `LoaderWithTTL`.
+type LoaderCtx interface {
+	LoadCtx(context.Context, interface{}) (interface{}, time.Duration, error)
+}
+
+// LoaderCtxFunc is an adapter to allow ordinary
+// functions as `LoaderCtx`.
+type LoaderCtxFunc func(context.Context, interface{}) (interface{}, time.Duration, error)
+
+// ContextCache is protocol definition for caches
+// that propagate contexts of operations to their
+// loaders, hooks and backends.
+type ContextCache interface {
+	GetCtx(context.Context, interface{}) (interface{}, error)
+	SetCtx(context.Context, interface{}, interface{}) (bool, error)
+}
+
+// - MARK: Alloc/Init section.
+
+// WithLoaderCtx configures the cache in read-through
+// mode; misses are loaded from `loader`, which gets
+// the context passed to `GetCtx` ( or an empty one
+// for `Get` ) and dictates freshness of each loaded
+// entery.
+func WithLoaderCtx(loader LoaderCtx) Option {
+	return func(lru *LRU) {
+		lru.opts.loader = loader
+	}
+}
+
+// DetachContext returns a context carrying the
+// values of `ctx` but neither its cancellation nor
+// its deadline, bounded by `timeout` instead unless
+// it's non-positive. It's meant for work outliving
+// the triggering request, e.g. asynchronous
+// refreshes, that should still carry its tracing
+// and auth metadata.
+func DetachContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// - MARK: Loader section.
+
+// LoadCtx conforms to `LoaderCtx` and calls `fn`.
+func (fn LoaderCtxFunc) LoadCtx(ctx context.Context, key interface{}) (interface{}, time.Duration, error) {
+	return fn(ctx, key)
+}
+
+// - MARK: Middleware section.
+
+// GetCtx conforms to `ContextCache`.
+func (ic *interceptor) GetCtx(ctx context.Context, key interface{}) (value interface{}, err error) {
+	ic.around(ctx, OpGET, key, func(ctx context.Context) error {
+		value, err = getCtx(ctx, ic.inner, key)
+		return err
+	})
+	if ic.got != nil {
+		ic.got(value)
+	}
+	return value, err
+}
+
+// SetCtx conforms to `ContextCache`.
+func (ic *interceptor) SetCtx(ctx context.Context, key interface{}, value interface{}) (isNew bool, err error) {
+	ic.around(ctx, OpSET, key, func(ctx context.Context) error {
+		isNew, err = setCtx(ctx, ic.inner, key, value)
+		return err
+	})
+	return isNew, err
+}
+
+// GetCtx conforms to `ContextCache`. Coalesced
+// callers share the load started with the context
+// of the first one, but stop waiting once their
+// own context is done, and retry when the load
+// failed as the context of the first one was done.
+func (fc *flightCache) GetCtx(ctx context.Context, key interface{}) (interface{}, error) {
+	return fc.get(ctx, key)
+}
+
+// SetCtx conforms to `ContextCache`.
+func (fc *flightCache) SetCtx(ctx context.Context, key interface{}, value interface{}) (bool, error) {
+	return setCtx(ctx, fc.CacheInterface, key, value)
+}
+
+// GetCtx conforms to `ContextCache`.
+func (nc *namespaceCache) GetCtx(ctx context.Context, key interface{}) (interface{}, error) {
+	return getCtx(ctx, nc.inner, compositeKey{nc.ns, key})
+}
+
+// SetCtx conforms to `ContextCache`.
+func (nc *namespaceCache) SetCtx(ctx context.Context, key interface{}, value interface{}) (bool, error) {
+	return setCtx(ctx, nc.inner, compositeKey{nc.ns, key}, value)
+}
+
+// GetCtx is same as `Get` except that `ctx` is
+// passed to the inner cache.
+func (tc *TransformCache) GetCtx(ctx context.Context, key interface{}) (value interface{}, err error) {
+	if value, err = getCtx(ctx, tc.inner, key); err != nil || value == nil {
+		return value, err
+	}
+	return tc.decode(key, value)
+}
+
+// SetCtx is same as `Set` except that `ctx` is
+// passed to the inner cache.
+func (tc *TransformCache) SetCtx(ctx context.Context, key interface{}, value interface{}) (isNew bool, err error) {
+	if value, err = tc.encode(key, value); err != nil {
+		return false, err
+	}
+	return setCtx(ctx, tc.inner, key, value)
+}
+
+// getCtx fetches `key` from `c`, passing `ctx`
+// along when `c` conforms to `ContextCache`.
+func getCtx(ctx context.Context, c CacheInterface, key interface{}) (interface{}, error) {
+	if cc, ok := c.(ContextCache); ok {
+		return cc.GetCtx(ctx, key)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.Get(key)
+}
+
+// setCtx writes `key` to `c`, passing `ctx` along
+// when `c` conforms to `ContextCache`.
+func setCtx(ctx context.Context, c CacheInterface, key interface{}, value interface{}) (bool, error) {
+	if cc, ok := c.(ContextCache); ok {
+		return cc.SetCtx(ctx, key, value)
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return c.Set(key, value)
+}
@@ -0,0 +1,137 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestLRUGetCtx(t *testing.T) {
+	var (
+		release chan struct{} = make(chan struct{})
+		graced  interface{}
+		lru     *LRU = NewLRU(8, WithLoaderCtx(LoaderCtxFunc(func(ctx context.Context, key interface{}) (interface{}, time.Duration, error) {
+			if key == "slow" {
+				<-release
+			}
+			if key == "fail" {
+				return nil, 0, errors.New("boom")
+			}
+			return ctx.Value(ctxKey{}), 0, nil
+		})), WithGraceCtx(time.Hour, func(ctx context.Context, key interface{}, err error) {
+			graced = ctx.Value(ctxKey{})
+		}))
+		ctx context.Context = context.WithValue(context.Background(), ctxKey{}, "trace")
+	)
+	if value, err := lru.GetCtx(ctx, "a"); value != "trace" || err != nil {
+		t.Fatal("assertion failed, expected context to flow into loader.", value, err)
+	}
+	lru.SetWithTTL("fail", 1, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if value, _ := lru.GetCtx(ctx, "fail"); value != 1 || graced != "trace" {
+		t.Fatal("assertion failed, expected context to flow into grace hook.", value, graced)
+	}
+	go lru.Get("slow")
+	time.Sleep(5 * time.Millisecond)
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if _, err := lru.GetCtx(cctx, "slow"); err != context.DeadlineExceeded {
+		t.Fatal("assertion failed, expected waiter to give up.", err)
+	}
+	close(release)
+	cancel()
+	if _, err := lru.SetCtx(cctx, "b", 1); err == nil || lru.Read("b") != nil {
+		t.Fatal("assertion failed, expected cancelled write.", err)
+	}
+}
+
+func TestMiddlewareCtx(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithLoaderCtx(LoaderCtxFunc(func(ctx context.Context, key interface{}) (interface{}, time.Duration, error) {
+			return ctx.Value(ctxKey{}), 0, nil
+		})))
+		c CacheInterface = Wrap(lru,
+			TracingMiddlewareCtx(func(ctx context.Context, op string, key interface{}) (context.Context, func(error)) {
+				return context.WithValue(ctx, ctxKey{}, "span"), func(error) {}
+			}),
+			NamespaceMiddleware("ns"),
+			TransformMiddleware(),
+		)
+	)
+	if value, err := c.(ContextCache).GetCtx(context.Background(), "a"); value != "span" || err != nil {
+		t.Fatal("assertion failed, expected span context to reach the loader.", value, err)
+	}
+}
+
+func TestDetachContext(t *testing.T) {
+	var (
+		parent, cancel = context.WithCancel(context.WithValue(context.Background(), ctxKey{}, 1))
+	)
+	ctx, stop := DetachContext(parent, time.Hour)
+	defer stop()
+	cancel()
+	if ctx.Err() != nil || ctx.Value(ctxKey{}) != 1 {
+		t.Fatal("assertion failed, expected detached context with values.")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("assertion failed, expected own deadline.")
+	}
+}
+
+func TestSingleflightCtxCanceled(t *testing.T) {
+	var (
+		loads int32
+		lru   *LRU = NewLRU(8, WithLoaderCtx(LoaderCtxFunc(func(ctx context.Context, key interface{}) (interface{}, time.Duration, error) {
+			if atomic.AddInt32(&loads, 1) == 1 {
+				<-ctx.Done()
+				return nil, 0, ctx.Err()
+			}
+			return key, 0, nil
+		})))
+		c           = Wrap(lru, SingleflightMiddleware()).(ContextCache)
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error, 1)
+	)
+	go func() {
+		_, err := c.GetCtx(ctx, "k")
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	// the follower outlives the cancelled leader
+	if value, err := c.GetCtx(context.Background(), "k"); value != "k" || err != nil {
+		t.Fatal("assertion failed, expected follower to retry.", value, err)
+	}
+	if err := <-done; err != context.Canceled {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+}
@@ -0,0 +1,350 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"time"
+)
+
+// LoaderFunc is an adapter to allow ordinary
+// functions as `Loader`.
+type LoaderFunc func(interface{}) (interface{}, error)
+
+// LoaderWithTTLFunc is an adapter to allow ordinary
+// functions as `LoaderWithTTL`.
+type LoaderWithTTLFunc func(interface{}) (interface{}, time.Duration, error)
+
+// loadCall is the container for an in-flight
+// load that concurrent callers of the same key
+// wait on.
+type loadCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// LoadCancel selects what happens to a load when
+// the context of the caller that started it is done.
+type LoadCancel int
+
+// Load cancellation modes
+const (
+	// LoadCANCELALL passes the context of the
+	// starting caller to the loader, so that its
+	// cancellation fails the load for all callers
+	// waiting on it.
+	LoadCANCELALL LoadCancel = iota
+	// LoadDETACH runs loads on a detached context
+	// ( see `DetachContext` ); callers stop waiting
+	// once their context is done while the load
+	// continues for remaining callers and its
+	// result is cached for later ones.
+	LoadDETACH
+)
+
+// loaderAdapter adapts `Loader` to `LoaderWithTTL`
+// by returning zero ttl ( i.e. never expires ).
+type loaderAdapter struct {
+	loader Loader
+}
+
+// ctxAdapter adapts `LoaderWithTTL` to `LoaderCtx`
+// by ignoring the context.
+type ctxAdapter struct {
+	loader LoaderWithTTL
+}
+
+// - MARK: Loader section.
+
+// Load conforms to `Loader` and calls `fn`.
+func (fn LoaderFunc) Load(key interface{}) (interface{}, error) {
+	return fn(key)
+}
+
+// LoadWithTTL conforms to `LoaderWithTTL` and
+// calls `fn`.
+func (fn LoaderWithTTLFunc) LoadWithTTL(key interface{}) (interface{}, time.Duration, error) {
+	return fn(key)
+}
+
+// LoadWithTTL conforms to `LoaderWithTTL`.
+func (la loaderAdapter) LoadWithTTL(key interface{}) (interface{}, time.Duration, error) {
+	value, err := la.loader.Load(key)
+	return value, 0, err
+}
+
+// LoadCtx conforms to `LoaderCtx`.
+func (ca ctxAdapter) LoadCtx(ctx context.Context, key interface{}) (interface{}, time.Duration, error) {
+	return ca.loader.LoadWithTTL(key)
+}
+
+// - MARK: LRU section.
+
+// load reads `key` through the configured loader
+// and writes the result to the cache. Duplicate
+// loads of the same key are suppressed; concurrent
+// callers wait for the in-flight load instead. A
+// negative ttl returned by the loader prevents the
+// value from being cached. When the load fails and
+// a stale entery is within grace period, the stale
+// value is served instead. `ctx` is passed to the
+// loader; callers waiting for an in-flight load
+// give up waiting once their `ctx` is done. Load
+// limits are bypassed when `unthrottled` is true.
+// Note, this routine must be called with lock held
+// and it releases the lock before returning.
+func (lru *LRU) load(ctx context.Context, key interface{}, unthrottled bool) (value interface{}, err error) {
+	var (
+		call    *loadCall
+		ok      bool
+		cooling bool
+	)
+	if call, ok = lru.opts.loads[key]; ok {
+		lru.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.value, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if lru.opts.warmup != nil && lru.warming() {
+		switch lru.opts.warmup.Mode {
+		case WarmFAILFAST:
+			lru.mu.Unlock()
+			return nil, ELRUWARMING
+		case WarmDEFAULT:
+			lru.mu.Unlock()
+			if lru.opts.warmup.Default == nil {
+				return nil, nil
+			}
+			return lru.opts.warmup.Default(key), nil
+		}
+		unthrottled = true
+	}
+	if err = lru.cachedError(key); err != nil {
+		value, err = lru.fallbackCtx(ctx, key, err)
+		lru.mu.Unlock()
+		return value, err
+	}
+	call = &loadCall{done: make(chan struct{})}
+	lru.opts.loads[key] = call
+	cooling = !unthrottled && lru.opts.purge != nil && lru.opts.purge.cooling(lru.now())
+	lru.mu.Unlock()
+
+	if lru.opts.cancellation == LoadDETACH {
+		dctx, cancel := DetachContext(ctx, lru.opts.detachTimeout)
+		go func() {
+			defer cancel()
+			lru.run(dctx, key, call, unthrottled, cooling)
+		}()
+		select {
+		case <-call.done:
+			return call.value, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	lru.run(ctx, key, call, unthrottled, cooling)
+	return call.value, call.err
+}
+
+// run performs the load of `call` and writes its
+// result to the cache, throttled by the purge
+// cooldown when `cooling` is true. When the load
+// panics, waiters fail with `ELRUPANICKED` and the
+// panic is propagated. Note, this routine must be
+// called without lock held.
+func (lru *LRU) run(ctx context.Context, key interface{}, call *loadCall, unthrottled bool, cooling bool) {
+	var (
+		value   interface{}
+		ttl     time.Duration
+		started time.Time
+		err     error
+		invalid bool
+		locked  bool
+		cooled  func() = func() {}
+	)
+	defer func() {
+		if r := recover(); r != nil {
+			if !locked {
+				lru.mu.Lock()
+			}
+			if lru.opts.loads[key] == call {
+				delete(lru.opts.loads, key)
+			}
+			lru.mu.Unlock()
+			call.value, call.err = nil, ELRUPANICKED
+			close(call.done)
+			panic(r)
+		}
+	}()
+	if cooling {
+		if cooled, err = lru.opts.purge.limiter.acquire(ctx, key, false); err != nil {
+			cooled = func() {}
+		}
+	}
+	defer cooled()
+	if err != nil {
+		lru.mu.Lock()
+		locked = true
+		delete(lru.opts.loads, key)
+		lru.opts.stats.Rejected++
+	} else if release, lerr := lru.opts.limits.acquire(ctx, key, unthrottled); lerr != nil {
+		err = lerr
+		lru.mu.Lock()
+		locked = true
+		delete(lru.opts.loads, key)
+		lru.opts.stats.Rejected++
+	} else {
+		started = time.Now()
+		func() {
+			// released even when the load panics
+			defer release()
+			value, ttl, err = lru.opts.loader.LoadCtx(ctx, key)
+		}()
+		if err == nil {
+			if err = lru.checkType(value); err == nil && lru.opts.validator != nil {
+				err = lru.opts.validator(key, value)
+			}
+			invalid = err != nil
+		}
+
+		lru.mu.Lock()
+		locked = true
+		delete(lru.opts.loads, key)
+		lru.opts.stats.Loads++
+		if invalid {
+			lru.opts.stats.Invalid++
+		}
+		if lru.opts.errorBase > 0 {
+			lru.recordFailure(key, err)
+		}
+	}
+	if err != nil {
+		value, err = lru.fallbackCtx(ctx, key, err)
+		ttl = -1
+	}
+	if err == nil && ttl >= 0 {
+		if lru.admit(key, value, time.Since(started)) {
+			if ttl == 0 {
+				_, err = lru.setDefault(key, value)
+			} else {
+				lru.fixExpiry(key)
+				_, err = lru.set(key, value, lru.deadline(ttl))
+			}
+			switch err {
+			case ELRULEASED:
+				// the lease holder writes the entery
+				err = nil
+			case ELRUNOTADMITTED:
+				// served, but not cached
+				err = nil
+			}
+		} else {
+			lru.opts.stats.Unadmitted++
+		}
+	}
+	lru.mu.Unlock()
+	locked = false
+	if err != nil {
+		value = nil
+	}
+	call.value, call.err = value, err
+	close(call.done)
+}
+
+// admit decides whether a value loaded for `key`
+// in `latency` is worth caching. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) admit(key interface{}, value interface{}, latency time.Duration) bool {
+	if lru.opts.admitLatency != nil && !lru.opts.admitLatency(key, latency) {
+		return false
+	}
+	if lru.opts.costFn != nil && (lru.opts.maxLoadCost > 0 || lru.opts.maxLoadShare > 0) {
+		var (
+			cost int64 = lru.opts.costFn(key, value)
+		)
+		if (lru.opts.maxLoadCost > 0 && cost > lru.opts.maxLoadCost) ||
+			(lru.opts.maxLoadShare > 0 && lru.opts.maxCost > 0 && float64(cost) > lru.opts.maxLoadShare*float64(lru.opts.maxCost)) {
+			lru.opts.stats.Oversized++
+			return false
+		}
+	}
+	return true
+}
+
+// LatencyThreshold returns an admission function
+// for `WithLatencyAdmission` that admits values
+// whose load took at least `threshold`.
+func LatencyThreshold(threshold time.Duration) func(interface{}, time.Duration) bool {
+	return func(key interface{}, latency time.Duration) bool {
+		return latency >= threshold
+	}
+}
+
+// inGrace returns whether `item` may be served in
+// place of a failing load at `now`; live enteries
+// always qualify while expired ones qualify until
+// the grace period past expiry elapses. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) inGrace(item *LRUItem, now int64) bool {
+	if !item.expired(now) {
+		return true
+	}
+	return lru.opts.grace > 0 && now < item.expires+int64(lru.opts.grace)
+}
+
+// fallback returns the stale value of `key` in place
+// of load error `err` when it's within grace period;
+// otherwise it returns `err`. Note, this routine is
+// not protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) fallback(key interface{}, err error) (interface{}, error) {
+	return lru.fallbackCtx(context.Background(), key, err)
+}
+
+// fallbackCtx is same as `fallback` except that
+// `ctx` of the failed load is passed to the grace
+// hook. Note, this routine is not protected against
+// concurrent accesses; therefore not publicly exposed.
+func (lru *LRU) fallbackCtx(ctx context.Context, key interface{}, err error) (interface{}, error) {
+	var (
+		stale *list.Element
+	)
+	if lru.opts.grace <= 0 {
+		return nil, err
+	}
+	if stale = lru.lookup[key]; stale == nil || !lru.inGrace(stale.Value.(*LRUItem), lru.now()) {
+		return nil, err
+	}
+	lru.opts.stats.StaleServed++
+	if lru.opts.onGrace != nil {
+		lru.opts.onGrace(ctx, key, err)
+	}
+	return stale.Value.(*LRUItem).Value, nil
+}
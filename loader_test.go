@@ -0,0 +1,224 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRULoaderTTL(t *testing.T) {
+	var (
+		calls int32
+		lru   *LRU
+		value interface{}
+		err   error
+	)
+	lru = NewLRU(8, WithLoaderTTL(LoaderWithTTLFunc(func(key interface{}) (interface{}, time.Duration, error) {
+		atomic.AddInt32(&calls, 1)
+		switch key.(string) {
+		case "short":
+			return "short-value", time.Millisecond, nil
+		case "nostore":
+			return "nostore-value", -1, nil
+		case "fail":
+			return nil, 0, errors.New("backend failure")
+		}
+		return "long-value", time.Hour, nil
+	})))
+	value, err = lru.Get("long")
+	if value != "long-value" || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value, err)
+	}
+	if value, _ = lru.Get("long"); value != "long-value" || atomic.LoadInt32(&calls) != 1 {
+		t.Fatal("assertion failed, expected cache hit.", value, calls)
+	}
+	if value, _ = lru.Get("short"); value != "short-value" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if lru.Read("short") != nil {
+		t.Fatal("assertion failed, expected expired entery.")
+	}
+	if value, _ = lru.Get("short"); value != "short-value" || atomic.LoadInt32(&calls) != 3 {
+		t.Fatal("assertion failed, expected reload of expired entery.", value, calls)
+	}
+	if value, _ = lru.Get("nostore"); value != "nostore-value" || lru.Read("nostore") != nil {
+		t.Fatal("assertion failed, expected uncached value.", value)
+	}
+	if value, err = lru.Get("fail"); value != nil || err == nil {
+		t.Fatal("assertion failed, expected error.", value, err)
+	}
+}
+
+func TestLRULoaderDedup(t *testing.T) {
+	const workers int = 16
+	var (
+		calls   int32
+		release chan struct{} = make(chan struct{})
+		wg      sync.WaitGroup
+		lru     *LRU
+	)
+	lru = NewLRU(8, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return key, nil
+	})))
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := lru.Get("key"); value != "key" || err != nil {
+				t.Error("assertion failed, inconsistent state. expected equal.", value, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("assertion failed, expected single load - got value(%d).", calls)
+	}
+}
+
+func TestLRUGetFreshRefresh(t *testing.T) {
+	var (
+		calls int32
+		lru   *LRU
+		value interface{}
+	)
+	lru = NewLRU(8, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	})))
+	if value, _ = lru.GetFresh("key", time.Hour); value != int32(1) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if value, _ = lru.GetFresh("key", time.Millisecond); value != int32(2) {
+		t.Fatal("assertion failed, expected refresh of stale entery.", value)
+	}
+	if value, _ = lru.Get("key"); value != int32(2) {
+		t.Fatal("assertion failed, expected refreshed entery.", value)
+	}
+}
+
+func TestLRULatencyAdmission(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithLatencyAdmission(LatencyThreshold(5*time.Millisecond)), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			if key == "slow" {
+				time.Sleep(10 * time.Millisecond)
+			}
+			return key, nil
+		})))
+		value interface{}
+	)
+	if value, _ = lru.Get("fast"); value != "fast" || lru.Read("fast") != nil {
+		t.Fatal("assertion failed, expected cheap value to bypass cache.", value)
+	}
+	if value, _ = lru.Get("slow"); value != "slow" || lru.Read("slow") != "slow" {
+		t.Fatal("assertion failed, expected expensive value to be cached.", value)
+	}
+	if stats := lru.Stats(); stats.Loads != 2 || stats.Unadmitted != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Counters)
+	}
+}
+
+func TestLRUGrace(t *testing.T) {
+	var (
+		failing int32
+		reports int32
+		lru     *LRU
+		value   interface{}
+		err     error
+	)
+	lru = NewLRU(8,
+		WithGrace(time.Hour, func(key interface{}, err error) {
+			atomic.AddInt32(&reports, 1)
+		}),
+		WithLoaderTTL(LoaderWithTTLFunc(func(key interface{}) (interface{}, time.Duration, error) {
+			if atomic.LoadInt32(&failing) == 1 {
+				return nil, 0, errors.New("backend outage")
+			}
+			return "fresh", time.Millisecond, nil
+		})))
+	if value, _ = lru.Get("key"); value != "fresh" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	time.Sleep(2 * time.Millisecond)
+	atomic.StoreInt32(&failing, 1)
+	if value, err = lru.Get("key"); value != "fresh" || err != nil || reports != 1 {
+		t.Fatal("assertion failed, expected stale value during outage.", value, err, reports)
+	}
+	if value, err = lru.Get("missing"); value != nil || err == nil {
+		t.Fatal("assertion failed, expected error without stale value.", value, err)
+	}
+	if stats := lru.Stats(); stats.StaleServed != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Counters)
+	}
+	atomic.StoreInt32(&failing, 0)
+	if value, _ = lru.Get("key"); value != "fresh" || lru.Read("key") != "fresh" {
+		t.Fatal("assertion failed, expected recovered entery.", value)
+	}
+}
+
+func TestLRULoaderPanic(t *testing.T) {
+	var (
+		calls   int32
+		release chan struct{} = make(chan struct{})
+		wg      sync.WaitGroup
+		lru     *LRU
+	)
+	lru = NewLRU(8, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+			panic("load failed")
+		}
+		return key, nil
+	})))
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != "load failed" {
+				t.Error("assertion failed, expected panic to propagate.", r)
+			}
+		}()
+		lru.Get("key")
+	}()
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		defer wg.Done()
+		if value, err := lru.Get("key"); value != nil || err != ELRUPANICKED {
+			t.Error("assertion failed, inconsistent state. expected equal.", value, err)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if value, err := lru.Get("key"); value != "key" || err != nil {
+		t.Fatal("assertion failed, expected reload after panic.", value, err)
+	}
+}
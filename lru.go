@@ -24,7 +24,9 @@ package cache
 
 import (
 	"container/list"
+	"context"
 	"sync"
+	"time"
 )
 
 // Ensure interface (protocol) conformance
@@ -44,18 +46,22 @@ type LRU struct {
 	mu              *sync.RWMutex                 // 8 bytes
 	items           *list.List                    // 8 bytes
 	lookup          map[interface{}]*list.Element // 8 bytes
-	capacity, count int                           // 8 bytes
-	_               [3]uint64                     // 24 bytes
+	capacity, count int                           // 16 bytes
+	opts            *lruOptions                   // 8 bytes
+	_               [2]uint64                     // 16 bytes
 }
 
 // LRUItem is the container for
 // individual cache enteries.
 type LRUItem struct {
-	// size: 64 bytes
-	Key   interface{} // 16 bytes
-	Value interface{} // 16 bytes
-	Count int         // 8 bytes
-	_     [3]uint64   // 24 bytes
+	// size: 72 bytes
+	Key     interface{} // 16 bytes
+	Value   interface{} // 16 bytes
+	Count   int         // 8 bytes
+	expires int64       // 8 bytes
+	stamp   int64       // 8 bytes
+	version uint64      // 8 bytes
+	cost    int64       // 8 bytes
 }
 
 // - MARK: Alloc/Init section.
@@ -64,19 +70,31 @@ type LRUItem struct {
 // `LRU` struct and returns a pointer to it.
 // Note, when `capacity <= 0` holds true,
 // capacity is set to `defaultCAPACITY` (
-// by default 16 ).
-func NewLRU(capacity int) (lru *LRU) {
+// by default 16 ). Optional behaviours are
+// configured through `opts`.
+func NewLRU(capacity int, opts ...Option) (lru *LRU) {
 	lru = &LRU{
 		mu:       &sync.RWMutex{},
 		items:    list.New(),
 		lookup:   make(map[interface{}]*list.Element),
 		capacity: capacity - 1,
 		count:    0,
+		opts:     newLRUOptions(),
 	}
 	// ensure validity of capacity
 	if lru.capacity <= 0 {
 		lru.capacity = defaultCAPACITY
 	}
+	for _, opt := range opts {
+		opt(lru)
+	}
+	if lru.opts.warmup != nil {
+		lru.opts.warmup.since = lru.now()
+	}
+	// start the janitor against the fully configured cache
+	if lru.opts.janitor != nil {
+		lru.opts.janitor.start(lru)
+	}
 	return lru
 }
 
@@ -86,31 +104,113 @@ func NewLRU(capacity int) (lru *LRU) {
 // old enteries when needed. It sets `isNew` to
 // to `true` when the given k/v pair are allocated
 // ( i.e. wasn't in cache ) and an error to indicate
-// failures.
+// failures. The entery expires after the default
+// ttl when configured ( see `WithDefaultTTL` ), once
+// idle ( see `WithExpireAfterAccess` ) or after the
+// ttl of its tier ( see `WithTiers` ).
 func (lru *LRU) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	if err = lru.checkType(value); err != nil {
+		return false, err
+	}
+	lru.mu.Lock()
+	isNew, err = lru.setDefault(key, value)
+	lru.debugValidate()
+	lru.mu.Unlock()
+	return isNew, err
+}
+
+// SetWithTTL is same as `Set` except that the written
+// entry expires after `ttl` elapses. Expired enteries
+// are treated as misses and reclaimed lazily. Note,
+// when `ttl <= 0` holds true, entery never expires.
+func (lru *LRU) SetWithTTL(key interface{}, value interface{}, ttl time.Duration) (isNew bool, err error) {
+	if err = lru.checkType(value); err != nil {
+		return false, err
+	}
 	lru.mu.Lock()
-	isNew, err = lru.set(key, value)
+	lru.fixExpiry(key)
+	isNew, err = lru.set(key, value, lru.deadline(ttl))
+	lru.debugValidate()
 	lru.mu.Unlock()
 	return isNew, err
 }
 
 // Get fetches `key` from cache and return its value
 // when available along with an error in case of
-// failure.
+// failure. When a loader is configured, misses are
+// read through from the loader.
 func (lru *LRU) Get(key interface{}) (value interface{}, err error) {
+	return lru.GetCtx(context.Background(), key)
+}
+
+// GetCtx is same as `Get` except that `ctx` flows
+// into the loader ( see `WithLoaderCtx` ) and hooks
+// of read-through misses; it fails with the error of
+// `ctx` once it's done without waiting for an
+// in-flight load of `key`.
+func (lru *LRU) GetCtx(ctx context.Context, key interface{}) (value interface{}, err error) {
 	var (
 		item *LRUItem
 	)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
 	value = nil
 	lru.mu.Lock()
 	// only return value to prevent
 	// data race
 	item, err = lru.get(key)
+	if lru.opts.prefetch != nil && lru.opts.loader != nil {
+		defer lru.prefetchAfter(key, err == nil && item != nil)
+	}
 	if err == nil && item != nil {
 		value = item.Value
+		lru.mu.Unlock()
+		return value, nil
 	}
-	lru.mu.Unlock()
-	return value, err
+	if err != nil || lru.opts.loader == nil {
+		lru.mu.Unlock()
+		return nil, err
+	}
+	// load releases the lock
+	return lru.load(ctx, key, false)
+}
+
+// SetCtx is same as `Set` except that it fails with
+// the error of `ctx` without writing once it's done.
+func (lru *LRU) SetCtx(ctx context.Context, key interface{}, value interface{}) (isNew bool, err error) {
+	if err = ctx.Err(); err != nil {
+		return false, err
+	}
+	return lru.Set(key, value)
+}
+
+// GetFresh is same as `Get` except that enteries
+// written more than `maxAge` ago are treated as
+// misses. In read-through mode, such enteries are
+// refreshed from the loader; otherwise they are
+// left intact for callers with laxer requirements.
+func (lru *LRU) GetFresh(key interface{}, maxAge time.Duration) (value interface{}, err error) {
+	var (
+		item *LRUItem
+	)
+	lru.mu.Lock()
+	item = lru.read(key)
+	if item != nil && lru.now()-item.stamp <= int64(maxAge) {
+		item, err = lru.get(key)
+		if err == nil && item != nil {
+			value = item.Value
+		}
+		lru.mu.Unlock()
+		return value, err
+	}
+	lru.miss(key)
+	if lru.opts.loader == nil {
+		lru.mu.Unlock()
+		return nil, nil
+	}
+	// load releases the lock
+	return lru.load(context.Background(), key, false)
 }
 
 // Read only reads the given item with `key` without
@@ -131,18 +231,25 @@ func (lru *LRU) Read(key interface{}) (value interface{}) {
 }
 
 // Remove removes the given item with `key` from cache
-// and returns `true` when succesfull.
+// and returns `true` when succesfull. When `key` is
+// an alias, only the alias is removed.
 func (lru *LRU) Remove(key interface{}) (ok bool) {
 	lru.mu.Lock()
 	ok = lru.remove(key)
+	lru.debugValidate()
 	lru.mu.Unlock()
 	return ok
 }
 
 // Purge removes all enteries and restarts the cache.
+// Read-through loads are throttled for a while after
+// when configured with `WithPurgePolicy`.
 func (lru *LRU) Purge() {
 	lru.mu.Lock()
 	lru.reset()
+	if lru.opts.purge != nil {
+		lru.opts.purge.purged, lru.opts.purge.didPurge = lru.now(), true
+	}
 	lru.mu.Unlock()
 }
 
@@ -155,27 +262,67 @@ func (lru *LRU) Len() (l int) {
 }
 
 // set writes k/v pair in the cache and triggers
-// eviction policies when neccessary. Note, this
-// routine is not protected against concurrent
-// accesses; therefore not publicly exposed.
-func (lru *LRU) set(key interface{}, value interface{}) (isNew bool, err error) {
+// eviction policies when neccessary. The entery
+// expires at `expires` ( unix nanoseconds ) unless
+// it is zero. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) set(key interface{}, value interface{}, expires int64) (isNew bool, err error) {
 	// increment global LRU counter
 	lru.count++
 	var (
-		cnt  int = lru.items.Len()
-		item *LRUItem
-		elem *list.Element
-		ok   bool
+		cnt       int = lru.items.Len()
+		item      *LRUItem
+		elem      *list.Element
+		victim    *list.Element
+		ok        bool
+		coalesced bool
+		same      bool
 	)
+	if lru.opts.leases != nil && lru.leased(key) {
+		err = ELRULEASED
+		goto ERROR
+	}
+	if lru.opts.janitor != nil && lru.opts.janitor.due(lru) > 0 {
+		cnt = lru.items.Len()
+	}
 	elem, ok = lru.lookup[key]
 	if !ok {
+		if lru.opts.readmit != nil {
+			lru.opts.readmit.drop(key)
+		}
 		if cnt > lru.capacity {
-			lru.evict()
+			victim = lru.nextVictim()
+		}
+		if lru.opts.admitter != nil && !lru.admitted(key, victim) {
+			err = ELRUNOTADMITTED
+			goto ERROR
+		}
+		if victim != nil {
+			lru.unlink(victim, EvictCAPACITY)
 		}
 		isNew = true
-		item = &LRUItem{Count: lru.count, Key: key, Value: value}
-		elem = lru.items.PushFront(item)
-		lru.lookup[key] = elem
+		item = &LRUItem{Count: lru.count, Key: key, Value: value, expires: expires, stamp: lru.now(), version: 1}
+		if tomb, ok := lru.opts.tombstones[key]; ok {
+			// local writes supersede deletions
+			item.version = tomb.version + 1
+			delete(lru.opts.tombstones, key)
+		}
+		if lru.opts.hlc != nil {
+			item.version = uint64(lru.opts.hlc.Now())
+		}
+		if lru.opts.keyStats != nil {
+			item.Count, _ = lru.opts.keyStats.estimate(hashKey(key))
+		}
+		if lru.opts.costFn != nil {
+			item.cost = lru.opts.costFn(key, value)
+			lru.opts.totalCost += item.cost
+		}
+		lru.link(item)
+		if lru.opts.cleanup != nil {
+			lru.attachCleanup(key, value)
+		}
+		lru.publish(Event{Type: EventSET, Key: key, Value: value})
 		goto OK
 	}
 	item, ok = elem.Value.(*LRUItem)
@@ -183,14 +330,52 @@ func (lru *LRU) set(key interface{}, value interface{}) (isNew bool, err error)
 		err = ELRUINVALTYPE
 		goto ERROR
 	}
-	if cnt > lru.capacity {
-		lru.evict()
-	}
+	// updates don't grow the cache; evicting here
+	// could drop the very entery being updated
 	item.Count += 1
-	item.Value = value
+	// rewriting the cached value replaces nothing
+	same = sameValue(item.Value, value)
+	if lru.opts.cleanup != nil && !same {
+		lru.stopCleanup(key)
+	}
+	if coalesced = lru.coalesced(item, lru.now()); !coalesced && !same {
+		lru.evicted(item.Key, item.Value, EvictREPLACED)
+	} else if lru.opts.refs != nil && !same {
+		lru.finalize(item.Value)
+	}
+	if lru.opts.identity != nil {
+		lru.revUnlink(item)
+		item.Value = value
+		lru.revLink(item)
+	} else {
+		item.Value = value
+	}
+	if lru.opts.cleanup != nil && !same {
+		lru.attachCleanup(key, value)
+	}
+	if lru.opts.costFn != nil {
+		lru.opts.totalCost -= item.cost
+		item.cost = lru.opts.costFn(key, value)
+		lru.opts.totalCost += item.cost
+	}
+	item.expires = expires
+	lru.scheduleExpiry(item)
+	item.version++
+	if lru.opts.hlc != nil {
+		item.version = uint64(lru.opts.hlc.Now())
+	}
 	lru.items.MoveToFront(elem)
+	if !coalesced {
+		// coalesced bursts are stamped by their first write
+		item.stamp = lru.now()
+		lru.publish(Event{Type: EventSET, Key: key, Value: value})
+	}
 
 OK:
+	// enforce cost limit, sparing the written entery
+	for lru.opts.maxCost > 0 && lru.opts.totalCost > lru.opts.maxCost && lru.items.Len() > 1 {
+		lru.evict()
+	}
 	return isNew, nil
 ERROR:
 	return false, err
@@ -206,16 +391,42 @@ func (lru *LRU) get(key interface{}) (value *LRUItem, err error) {
 		elem *list.Element
 		ok   bool
 	)
+	if lru.opts.control != nil {
+		lru.adapt()
+	}
 	elem, ok = lru.lookup[key]
+	if !ok && lru.opts.readmit != nil {
+		elem = lru.readmit(key)
+		ok = elem != nil
+	}
 	if !ok {
 		goto ERROR
 	}
 	item = elem.Value.(*LRUItem)
+	if item.expired(lru.now()) {
+		// keep stale enteries around as fallback
+		// for failing reloads during grace period
+		if lru.opts.loader == nil || !lru.inGrace(item, lru.now()) {
+			lru.unlink(elem, lru.expiredReason(item))
+		}
+		goto ERROR
+	}
+	if lru.opts.validateGets && !lru.valid(item) {
+		goto ERROR
+	}
 	item.Count++
+	if lru.opts.tiers != nil {
+		lru.touchTier(item)
+	} else if idle, ok := lru.opts.idle[item.Key]; ok {
+		item.expires = lru.deadline(idle)
+		lru.scheduleExpiry(item)
+	}
 	lru.items.MoveToFront(elem)
+	lru.hit(key)
 
 	return item, nil
 ERROR:
+	lru.miss(key)
 	return nil, err
 }
 
@@ -244,12 +455,17 @@ func (lru *LRU) readEntery(key interface{}) *list.Element {
 func (lru *LRU) read(key interface{}) *LRUItem {
 	var (
 		elem *list.Element
+		item *LRUItem
 	)
 	elem = lru.lookup[key]
 	if elem == nil {
 		return nil
 	}
-	return elem.Value.(*LRUItem)
+	item = elem.Value.(*LRUItem)
+	if item.expired(lru.now()) {
+		return nil
+	}
+	return item
 }
 
 // reset purges all cache enteries and restarts
@@ -258,11 +474,53 @@ func (lru *LRU) read(key interface{}) *LRUItem {
 // against concurrent accesses; therefore not
 // publicly exposed.
 func (lru *LRU) reset() {
+	var (
+		hooked bool = lru.opts.onEvict != nil || len(lru.opts.listeners) > 0 || len(lru.opts.subscribers) > 0 || len(lru.opts.handoffs) > 0 || lru.opts.refs != nil
+	)
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		item := elem.Value.(*LRUItem)
+		if hooked {
+			lru.evicted(item.Key, item.Value, EvictPURGED)
+		} else {
+			lru.countEvicted(item.Key, EvictPURGED)
+		}
+	}
 	lru.items = lru.items.Init()
 	lru.count = 0
+	lru.opts.totalCost = 0
 	for k, _ := range lru.lookup {
 		delete(lru.lookup, k)
 	}
+	for ns, _ := range lru.opts.namespaces {
+		delete(lru.opts.namespaces, ns)
+	}
+	for id, _ := range lru.opts.reverse {
+		delete(lru.opts.reverse, id)
+	}
+	for key, _ := range lru.opts.aliases {
+		delete(lru.opts.aliases, key)
+	}
+	for key, _ := range lru.opts.tombstones {
+		delete(lru.opts.tombstones, key)
+	}
+	if lru.opts.readmit != nil {
+		lru.opts.readmit.reset()
+	}
+	lru.opts.pins, lru.opts.tags = nil, nil
+	for key, _ := range lru.opts.tiered {
+		delete(lru.opts.tiered, key)
+	}
+	lru.opts.idle = nil
+	if lru.opts.wheel != nil {
+		lru.opts.wheel.reset()
+	}
+	if lru.opts.expiries != nil {
+		lru.opts.expiries.reset()
+	}
+	if lru.opts.cleanup != nil {
+		// purged values stay guarded by their cleanups
+		clear(lru.opts.cleanup.handles)
+	}
 }
 
 // remove removes the entery associated to the
@@ -273,35 +531,144 @@ func (lru *LRU) reset() {
 // not publicly exposed.
 func (lru *LRU) remove(key interface{}) bool {
 	var (
-		item *list.Element = lru.readEntery(key)
+		elem *list.Element = lru.readEntery(key)
 	)
-	if item == nil {
+	if lru.opts.readmit != nil {
+		lru.opts.readmit.drop(key)
+	}
+	if elem == nil {
 		return false
 	}
-	// TODO:
-	// . remove references from
-	//   node before returning?
-	_ = lru.items.Remove(item)
+	if canonical := elem.Value.(*LRUItem).Key; canonical != key {
+		lru.unalias(key, canonical)
+		return true
+	}
+	if lru.opts.tombstoneTTL > 0 {
+		lru.bury(key, elem.Value.(*LRUItem).version)
+	}
+	lru.unlink(elem, EvictREMOVED)
 	return true
 }
 
 // evict is the policy function. It removes
 // oldest entery ( i.e. pops an item from back
 // of the list ) and removes its references.
+// Pinned victims and those vetoed by
+// `WithOnBeforeEvict` are skipped in favour
+// of the next oldest one; with `WithTiers`,
+// colder ones are preferred.
 // Note, this routine is not protected against
 // concurrent accesses; therefore not publicly
 // exposed.
 func (lru *LRU) evict() {
+	lru.unlink(lru.nextVictim(), EvictCAPACITY)
+}
+
+// nextVictim returns the entery to be evicted
+// next. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) nextVictim() *list.Element {
+	switch {
+	case lru.opts.tiers != nil:
+		return lru.tieredVictim()
+	case lru.opts.beforeEvict != nil || len(lru.opts.pins) > 0:
+		return lru.victim()
+	}
+	return lru.items.Back()
+}
+
+// link pushes `item` to front of the list and
+// registers its lookup and index references. Note,
+// this routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) link(item *LRUItem) (elem *list.Element) {
+	elem = lru.items.PushFront(item)
+	lru.lookup[item.Key] = elem
+	if ck, ok := item.Key.(compositeKey); ok {
+		lru.nsLink(ck, elem)
+	}
+	if lru.opts.identity != nil {
+		lru.revLink(item)
+	}
+	lru.scheduleExpiry(item)
+	return elem
+}
+
+// unlink removes `elem` from the list along with
+// its lookup reference, reports the eviction with
+// `reason` and clears the references held by its
+// item to help GC. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) unlink(elem *list.Element, reason EvictReason) {
 	var (
-		item *LRUItem = lru.popBack()
+		item *LRUItem = lru.items.Remove(elem).(*LRUItem)
 	)
+	lru.evicted(item.Key, item.Value, reason)
+	if reason == EvictCAPACITY && lru.opts.overflow != nil {
+		lru.overflow(item)
+	}
+	if reason == EvictCAPACITY && lru.opts.readmit != nil {
+		lru.opts.readmit.add(item, lru.now())
+	}
+	lru.opts.totalCost -= item.cost
 	delete(lru.lookup, item.Key)
+	if ck, ok := item.Key.(compositeKey); ok {
+		lru.nsUnlink(ck)
+	}
+	if lru.opts.identity != nil {
+		lru.revUnlink(item)
+	}
+	if lru.opts.pins != nil {
+		delete(lru.opts.pins, item.Key)
+	}
+	if lru.opts.tags != nil {
+		delete(lru.opts.tags, item.Key)
+	}
+	if lru.opts.tiered != nil {
+		delete(lru.opts.tiered, item.Key)
+	}
+	if lru.opts.idle != nil {
+		delete(lru.opts.idle, item.Key)
+	}
+	if lru.opts.wheel != nil {
+		lru.opts.wheel.cancel(item.Key)
+	}
+	if lru.opts.expiries != nil {
+		lru.unqueueExpiry(item.Key)
+	}
+	if aliases, ok := lru.opts.aliases[item.Key]; ok {
+		for alias, _ := range aliases {
+			delete(lru.lookup, alias)
+		}
+		delete(lru.opts.aliases, item.Key)
+	}
 	// remove references to help GC
 	item.Key = nil
 	item.Value = nil
 	item = nil
 }
 
+// deadline converts `ttl` to an absolute expiry
+// time in unix nanoseconds. It returns zero when
+// `ttl <= 0` holds true ( i.e. never expires ).
+func (lru *LRU) deadline(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return lru.now() + int64(ttl)
+}
+
+// now returns current time in unix nanoseconds
+// as read from the configured clock.
+func (lru *LRU) now() int64 {
+	if lru.opts.clock != nil {
+		return lru.opts.clock.Now().UnixNano()
+	}
+	return time.Now().UnixNano()
+}
+
 // popBack removes tail item. Note, this routine
 // is not protected agaisnt concurrent accesses;
 // therefore not publicy exopsed.
@@ -341,6 +708,12 @@ ERROR:
 
 // - MARK: LRUItem section.
 
+// expired returns whether the item is expired
+// relative to `now` ( unix nanoseconds ).
+func (lrui *LRUItem) expired(now int64) bool {
+	return lrui.expires != 0 && now >= lrui.expires
+}
+
 // K conforms to `CacheItemInterface` and returns
 // associated key.
 func (lrui *LRUItem) K() interface{} {
@@ -25,6 +25,7 @@ package cache
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/mitghi/x/structs"
 )
@@ -120,3 +121,26 @@ func TestLRU(t *testing.T) {
 		t.Fatal("assertion afiled, inconsistent state, expected equal.", lru.count, lru.items.Len(), lru.lookup)
 	}
 }
+
+func TestLRUGetFresh(t *testing.T) {
+	var (
+		lru   *LRU = NewLRU(8)
+		value interface{}
+		err   error
+	)
+	if _, err = lru.Set("key", "value"); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	value, err = lru.GetFresh("key", time.Hour)
+	if value != "value" || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value, err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	value, err = lru.GetFresh("key", time.Millisecond)
+	if value != nil || err != nil {
+		t.Fatal("assertion failed, expected miss for stale entery.", value, err)
+	}
+	if value = lru.Read("key"); value != "value" {
+		t.Fatal("assertion failed, expected stale entery to remain.", value)
+	}
+}
@@ -0,0 +1,171 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Manager is a registry of named caches that
+// can dump their snapshots and statistics on
+// demand, e.g. for postmortem analysis.
+type Manager struct {
+	mu     sync.Mutex
+	dir    string
+	caches map[string]*LRU
+}
+
+// - MARK: Alloc/Init section.
+
+// NewManager allocates and initializes a new
+// `Manager` struct dumping to `dir` and returns
+// a pointer to it.
+func NewManager(dir string) *Manager {
+	return &Manager{dir: dir, caches: make(map[string]*LRU)}
+}
+
+// - MARK: Manager section.
+
+// Register adds `lru` under `name`, replacing any
+// cache previously registered under it. When `name`
+// is empty, the name of `lru` is used ( see
+// `WithName` ).
+func (m *Manager) Register(name string, lru *LRU) {
+	if name == "" {
+		name = lru.Name()
+	}
+	m.mu.Lock()
+	m.caches[name] = lru
+	m.mu.Unlock()
+}
+
+// Unregister removes the cache registered under
+// `name`.
+func (m *Manager) Unregister(name string) {
+	m.mu.Lock()
+	delete(m.caches, name)
+	m.mu.Unlock()
+}
+
+// Names returns sorted names of registered caches.
+func (m *Manager) Names() (names []string) {
+	m.mu.Lock()
+	for name, _ := range m.caches {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+	sort.Strings(names)
+	return names
+}
+
+// TriggerSnapshot writes `<name>.snapshot` ( see
+// `LRU.Export` ) and `<name>.stats` files of every
+// registered cache to the manager's directory. It
+// continues past failing caches and returns the
+// first error encountered.
+func (m *Manager) TriggerSnapshot() (err error) {
+	var (
+		caches map[string]*LRU = make(map[string]*LRU)
+	)
+	m.mu.Lock()
+	for name, lru := range m.caches {
+		caches[name] = lru
+	}
+	m.mu.Unlock()
+	if err = os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+	for name, lru := range caches {
+		if derr := m.dump(name, lru); derr != nil && err == nil {
+			err = derr
+		}
+	}
+	return err
+}
+
+// dump writes snapshot and statistics of `lru`.
+func (m *Manager) dump(name string, lru *LRU) (err error) {
+	var (
+		base string = filepath.Join(m.dir, name)
+	)
+	if err = writeFile(base+".snapshot", func(w io.Writer) error {
+		_, err := lru.Export(w)
+		return err
+	}); err != nil {
+		return err
+	}
+	return writeFile(base+".stats", func(w io.Writer) error {
+		return writeStats(w, lru.Stats())
+	})
+}
+
+// writeFile creates `path` and fills it with `fn`.
+func writeFile(path string, fn func(io.Writer) error) (err error) {
+	var (
+		f *os.File
+	)
+	if f, err = os.Create(path); err != nil {
+		return err
+	}
+	if err = fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeStats writes `stats` to `w` in a plain text
+// format, one counter per line.
+func writeStats(w io.Writer, stats Stats) (err error) {
+	var (
+		write func(prefix string, c Counters)
+	)
+	write = func(prefix string, c Counters) {
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(w, "%shits %d\n%smisses %d\n%sloads %d\n%sunadmitted %d\n%soversized %d\n%sstale_served %d\n%srejected %d\n%sghost_hits %d\n%svetoed %d\n%soverflow_dropped %d\n%sreadmitted %d\n%sprefetches %d\n%sprefetch_dropped %d\n%scoalesced %d\n%sinvalid %d\n%sdenied %d\n%scleaned %d\n",
+			prefix, c.Hits, prefix, c.Misses, prefix, c.Loads, prefix, c.Unadmitted, prefix, c.Oversized, prefix, c.StaleServed, prefix, c.Rejected, prefix, c.GhostHits, prefix, c.Vetoed, prefix, c.OverflowDropped, prefix, c.Readmitted, prefix, c.Prefetches, prefix, c.PrefetchDropped, prefix, c.Coalesced, prefix, c.Invalid, prefix, c.Denied, prefix, c.Cleaned)
+		for reason := EvictReason(0); reason < evictREASONS && err == nil; reason++ {
+			_, err = fmt.Fprintf(w, "%sevictions_%s %d\n", prefix, reason, c.Evictions[reason])
+		}
+	}
+	if stats.Name != "" {
+		_, err = fmt.Fprintf(w, "name %s\n", stats.Name)
+	}
+	for _, k := range sortedLabels(stats.Labels) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, "label(%s) %s\n", k, stats.Labels[k])
+		}
+	}
+	write("", stats.Counters)
+	for ns, c := range stats.Namespaces {
+		write(fmt.Sprintf("namespace(%v) ", ns), c)
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLRUExportImport(t *testing.T) {
+	var (
+		src *LRU = NewLRU(8)
+		dst *LRU = NewLRU(8)
+		buf bytes.Buffer
+		n   int
+		err error
+	)
+	for i := 0; i < 4; i++ {
+		src.Set(i, i*10)
+	}
+	src.SetWithTTL("expired", 1, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	src.Get(0)
+	if n, err = src.Export(&buf); n != 4 || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n, err)
+	}
+	if n, err = dst.Import(&buf); n != 4 || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n, err)
+	}
+	if dst.Read(3) != 30 || dst.items.Front().Value.(*LRUItem).Key != 0 {
+		t.Fatal("assertion failed, expected preserved recency.")
+	}
+}
@@ -0,0 +1,71 @@
+//go:build unix
+
+/* MIT License
+*
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+*
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+*
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+ */
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestManagerTriggerSnapshot(t *testing.T) {
+	var (
+		dir string   = t.TempDir()
+		m   *Manager = NewManager(dir)
+		lru *LRU     = NewLRU(8)
+	)
+	lru.Set("key", "value")
+	lru.Get("key")
+	m.Register("users", lru)
+	if names := m.Names(); len(names) != 1 || names[0] != "users" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", names)
+	}
+	stop := m.NotifyOnSignal(func(err error) { t.Error("assertion failed, unexpected error.", err) })
+	defer stop()
+	syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	deadline := time.Now().Add(time.Second)
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "users.stats"))
+		if err == nil && strings.Contains(string(data), "hits 1\n") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("assertion failed, expected stats dump.", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	f, err := os.Open(filepath.Join(dir, "users.snapshot"))
+	if err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	defer f.Close()
+	restored := NewLRU(8)
+	if n, err := restored.Import(f); n != 1 || err != nil || restored.Read("key") != "value" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n, err)
+	}
+}
@@ -0,0 +1,150 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoOption configures `Memoize` and `MemoizeCtx`.
+type MemoOption func(*memoOptions)
+
+// memoOptions holds memoization settings.
+type memoOptions struct {
+	ttl time.Duration
+}
+
+// memoCall is an in-flight call of a memoized
+// function.
+type memoCall[V any] struct {
+	done     chan struct{}
+	value    V
+	err      error
+	panicked interface{} // recovered panic of `fn`
+}
+
+// ttlSetter is implemented by caches supporting
+// per-entery ttl, e.g. `LRU`.
+type ttlSetter interface {
+	SetWithTTL(interface{}, interface{}, time.Duration) (bool, error)
+}
+
+// - MARK: Alloc/Init section.
+
+// MemoTTL makes memoized results expire after `ttl`
+// when the cache supports `SetWithTTL`.
+func MemoTTL(ttl time.Duration) MemoOption {
+	return func(o *memoOptions) {
+		o.ttl = ttl
+	}
+}
+
+// Memoize wraps `fn` so that its results are cached
+// in `c` keyed by argument. Concurrent calls with the
+// same argument share a single execution of `fn`.
+// Errors are returned to all waiters but never
+// cached. Note, results that convert to a `nil`
+// interface are indistinguishable from misses and
+// thus recomputed.
+func Memoize[K comparable, V any](c CacheInterface, fn func(K) (V, error), opts ...MemoOption) func(K) (V, error) {
+	var (
+		call func(context.Context, K) (V, error)
+	)
+	call = MemoizeCtx(c, func(_ context.Context, key K) (V, error) { return fn(key) }, opts...)
+	return func(key K) (V, error) {
+		return call(context.Background(), key)
+	}
+}
+
+// MemoizeCtx is the context-aware variant of
+// `Memoize`. A caller whose context is done stops
+// waiting and gets the context's error, while the
+// shared execution carries on with the context of
+// the caller that started it. When `fn` panics, that
+// caller panics with the same value and others fail
+// with `ELRUPANICKED`.
+func MemoizeCtx[K comparable, V any](c CacheInterface, fn func(context.Context, K) (V, error), opts ...MemoOption) func(context.Context, K) (V, error) {
+	var (
+		o     memoOptions
+		mu    sync.Mutex
+		calls map[K]*memoCall[V] = make(map[K]*memoCall[V])
+	)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(ctx context.Context, key K) (value V, err error) {
+		var (
+			cached  interface{}
+			call    *memoCall[V]
+			ok      bool
+			started bool
+		)
+		if cached, err = c.Get(key); err == nil && cached != nil {
+			if value, ok = cached.(V); ok {
+				return value, nil
+			}
+		}
+		mu.Lock()
+		if call, ok = calls[key]; !ok {
+			call, started = &memoCall[V]{done: make(chan struct{})}, true
+			calls[key] = call
+			mu.Unlock()
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						call.err, call.panicked = ELRUPANICKED, r
+						mu.Lock()
+						delete(calls, key)
+						mu.Unlock()
+						close(call.done)
+					}
+				}()
+				call.value, call.err = fn(ctx, key)
+				if call.err == nil {
+					if ts, ok := c.(ttlSetter); ok && o.ttl > 0 {
+						ts.SetWithTTL(key, call.value, o.ttl)
+					} else {
+						c.Set(key, call.value)
+					}
+				}
+				mu.Lock()
+				delete(calls, key)
+				mu.Unlock()
+				close(call.done)
+			}()
+		} else {
+			mu.Unlock()
+		}
+		select {
+		case <-call.done:
+			if started && call.panicked != nil {
+				panic(call.panicked)
+			}
+			return call.value, call.err
+		case <-ctx.Done():
+			return value, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoize(t *testing.T) {
+	var (
+		lru     *LRU = NewLRU(8)
+		calls   int32
+		release chan struct{} = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	fn := Memoize(lru, func(n int) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		if n < 0 {
+			return "", errors.New("negative")
+		}
+		return strconv.Itoa(n), nil
+	})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := fn(7); v != "7" || err != nil {
+				t.Error("assertion failed, inconsistent state. expected equal.", v, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if v, err := fn(7); v != "7" || err != nil || atomic.LoadInt32(&calls) != 1 {
+		t.Fatal("assertion failed, expected single execution.", v, err, calls)
+	}
+	if _, err := fn(-1); err == nil || lru.Read(-1) != nil {
+		t.Fatal("assertion failed, expected uncached error.", err)
+	}
+}
+
+func TestMemoizeCtxCancel(t *testing.T) {
+	var (
+		lru     *LRU          = NewLRU(8)
+		release chan struct{} = make(chan struct{})
+	)
+	fn := MemoizeCtx(lru, func(ctx context.Context, key string) (int, error) {
+		<-release
+		return len(key), nil
+	}, MemoTTL(time.Minute))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fn(ctx, "abc"); err != context.Canceled {
+		t.Fatal("assertion failed, expected canceled.", err)
+	}
+	close(release)
+	if v, err := fn(context.Background(), "abc"); v != 3 || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+	}
+	if lru.read("abc").expires == 0 {
+		t.Fatal("assertion failed, expected ttl.")
+	}
+}
+
+func TestMemoizePanic(t *testing.T) {
+	var (
+		calls   int32
+		release chan struct{} = make(chan struct{})
+		wg      sync.WaitGroup
+		double  = Memoize(NewLRU(8), func(n int) (int, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-release
+				panic("memo failed")
+			}
+			return 2 * n, nil
+		})
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != "memo failed" {
+				t.Error("assertion failed, expected panic in the caller.", r)
+			}
+		}()
+		double(1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		defer wg.Done()
+		if _, err := double(1); err != ELRUPANICKED {
+			t.Error("assertion failed, inconsistent state. expected equal.", err)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if v, err := double(1); v != 2 || err != nil {
+		t.Fatal("assertion failed, expected call after panic.", v, err)
+	}
+}
@@ -0,0 +1,97 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// Versioned pairs a value with the HLC timestamp
+// of the write that produced it.
+type Versioned struct {
+	Value     interface{}
+	Timestamp Timestamp
+}
+
+// MergeFunc resolves concurrent writes of `key`;
+// it receives the stored and incoming versions
+// and returns the version to keep. Returning a
+// value other than either input implements
+// custom ( e.g. CRDT ) merges.
+type MergeFunc func(key interface{}, local, remote Versioned) Versioned
+
+// LWW is the last-writer-wins `MergeFunc`; the
+// version with the greater timestamp is kept and
+// ties are broken by value hash so that all peers
+// converge on the same value.
+func LWW(key interface{}, local, remote Versioned) Versioned {
+	switch {
+	case remote.Timestamp > local.Timestamp:
+		return remote
+	case remote.Timestamp < local.Timestamp:
+		return local
+	case hashValue(remote.Value) > hashValue(local.Value):
+		return remote
+	}
+	return local
+}
+
+// - MARK: LRU section.
+
+// Merge applies a write of `key` replicated from a
+// peer at `ts`. The configured clock observes `ts`
+// and, when the key is stored, the merge function
+// ( by default `LWW` ) decides the resulting value.
+// Writes older than a live tombstone are rejected.
+// It requires the cache to be constructed with
+// `WithHLC`.
+func (lru *LRU) Merge(key interface{}, value interface{}, ts Timestamp) (applied bool, err error) {
+	var (
+		item   *LRUItem
+		merged Versioned
+		merge  MergeFunc = lru.opts.merge
+	)
+	if lru.opts.hlc == nil {
+		return false, ELRUNOCLOCK
+	}
+	if err = lru.checkType(value); err != nil {
+		return false, err
+	}
+	if merge == nil {
+		merge = LWW
+	}
+	lru.opts.hlc.Update(ts)
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if item = lru.read(key); item == nil {
+		return lru.setVersion(key, value, 0, uint64(ts))
+	}
+	merged = merge(key, Versioned{item.Value, Timestamp(item.version)}, Versioned{value, ts})
+	if merged.Timestamp < Timestamp(item.version) {
+		merged.Timestamp = Timestamp(item.version)
+	}
+	if merged.Timestamp == Timestamp(item.version) && hashValue(merged.Value) == hashValue(item.Value) {
+		return false, nil
+	}
+	if _, err = lru.set(key, merged.Value, item.expires); err != nil {
+		return false, err
+	}
+	lru.read(key).version = uint64(merged.Timestamp)
+	return true, nil
+}
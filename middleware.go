@@ -0,0 +1,395 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Middleware decorates a cache with additional
+// behavior while preserving its protocol.
+type Middleware func(CacheInterface) CacheInterface
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*interceptor)(nil)
+	_ Remover        = (*interceptor)(nil)
+	_ CacheInterface = (*flightCache)(nil)
+	_ CacheInterface = (*namespaceCache)(nil)
+)
+
+// Cache operations reported by middlewares
+const (
+	OpGET    = "get"
+	OpSET    = "set"
+	OpREAD   = "read"
+	OpREMOVE = "remove"
+	OpPURGE  = "purge"
+)
+
+// Metrics are counters maintained by
+// `MetricsMiddleware`. Durations are in
+// nanoseconds.
+type Metrics struct {
+	Gets, Hits, Misses, Sets, Errors uint64
+	GetNanos, SetNanos               uint64
+}
+
+// interceptor calls `around` for each operation
+// of `inner`; `call` performs the operation with
+// the given context and returns its error. `got`,
+// when non-nil, observes values returned by gets.
+type interceptor struct {
+	inner  CacheInterface
+	around func(ctx context.Context, op string, key interface{}, call func(context.Context) error)
+	got    func(value interface{})
+}
+
+// flightCache coalesces concurrent gets of a key.
+type flightCache struct {
+	CacheInterface
+	mu      sync.Mutex
+	flights map[interface{}]*flight
+}
+
+// flight is an in-flight get.
+type flight struct {
+	done     chan struct{}
+	value    interface{}
+	err      error
+	canceled bool // failed as its context was done
+}
+
+// namespaceCache confines keys to a namespace.
+type namespaceCache struct {
+	inner CacheInterface
+	ns    interface{}
+}
+
+// - MARK: Alloc/Init section.
+
+// Wrap applies `mws` to `c`; the first middleware
+// is the outermost one.
+func Wrap(c CacheInterface, mws ...Middleware) CacheInterface {
+	for i := len(mws) - 1; i >= 0; i-- {
+		c = mws[i](c)
+	}
+	return c
+}
+
+// MetricsMiddleware counts operations, hits, misses,
+// errors and latencies into `m`. Gets returning a
+// `nil` value count as misses.
+func MetricsMiddleware(m *Metrics) Middleware {
+	return func(c CacheInterface) CacheInterface {
+		ic := countOperations(m)(c).(*interceptor)
+		ic.got = func(value interface{}) {
+			if value == nil {
+				atomic.AddUint64(&m.Misses, 1)
+			} else {
+				atomic.AddUint64(&m.Hits, 1)
+			}
+		}
+		return ic
+	}
+}
+
+// countOperations returns a middleware counting
+// operations, errors and latencies into `m`.
+func countOperations(m *Metrics) Middleware {
+	return Intercept(func(op string, key interface{}, call func() error) {
+		var (
+			start time.Time = time.Now()
+			err   error     = call()
+		)
+		if err != nil {
+			atomic.AddUint64(&m.Errors, 1)
+		}
+		switch op {
+		case OpGET:
+			atomic.AddUint64(&m.Gets, 1)
+			atomic.AddUint64(&m.GetNanos, uint64(time.Since(start)))
+		case OpSET:
+			atomic.AddUint64(&m.Sets, 1)
+			atomic.AddUint64(&m.SetNanos, uint64(time.Since(start)))
+		}
+	})
+}
+
+// LoggingMiddleware logs operations to `logger` at
+// debug level, and failed ones at error level. When
+// the wrapped cache is named ( see `WithName` ), its
+// name is logged as "cache".
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(c CacheInterface) CacheInterface {
+		if named, ok := c.(interface{ Name() string }); ok && named.Name() != "" {
+			return logOperations(logger.With("cache", named.Name()))(c)
+		}
+		return logOperations(logger)(c)
+	}
+}
+
+// logOperations returns a middleware logging
+// operations to `logger`.
+func logOperations(logger *slog.Logger) Middleware {
+	return Intercept(func(op string, key interface{}, call func() error) {
+		var (
+			start time.Time = time.Now()
+			err   error     = call()
+		)
+		if err != nil {
+			logger.Error("cache operation failed", "op", op, "key", key, "error", err)
+			return
+		}
+		logger.Debug("cache operation", "op", op, "key", key, "duration", time.Since(start))
+	})
+}
+
+// TracingMiddleware calls `start` before each
+// operation and the returned function with its
+// error afterwards, e.g. to open and end spans.
+func TracingMiddleware(start func(op string, key interface{}) (end func(err error))) Middleware {
+	return Intercept(func(op string, key interface{}, call func() error) {
+		end := start(op, key)
+		end(call())
+	})
+}
+
+// TracingMiddlewareCtx is same as `TracingMiddleware`
+// except that `start` receives the context of the
+// operation ( see `ContextCache` ) and returns the
+// context, e.g. carrying a span, that the operation
+// continues with down to the loader.
+func TracingMiddlewareCtx(start func(ctx context.Context, op string, key interface{}) (context.Context, func(err error))) Middleware {
+	return InterceptCtx(func(ctx context.Context, op string, key interface{}, call func(context.Context) error) {
+		ctx, end := start(ctx, op, key)
+		end(call(ctx))
+	})
+}
+
+// Intercept returns a middleware calling `around`
+// for each operation, which must invoke `call`
+// exactly once. `key` is `nil` for purges.
+func Intercept(around func(op string, key interface{}, call func() error)) Middleware {
+	return InterceptCtx(func(ctx context.Context, op string, key interface{}, call func(context.Context) error) {
+		around(op, key, func() error { return call(ctx) })
+	})
+}
+
+// InterceptCtx is same as `Intercept` except that
+// `around` receives the context of the operation
+// and passes a, possibly derived, context to `call`.
+// Operations without context, and those of wrapped
+// caches not conforming to `ContextCache`, get an
+// empty one.
+func InterceptCtx(around func(ctx context.Context, op string, key interface{}, call func(context.Context) error)) Middleware {
+	return func(c CacheInterface) CacheInterface {
+		return &interceptor{inner: c, around: around}
+	}
+}
+
+// SingleflightMiddleware coalesces concurrent gets of
+// the same key into a single call to the wrapped
+// cache, which is useful in front of read-through
+// caches with expensive loaders.
+func SingleflightMiddleware() Middleware {
+	return func(c CacheInterface) CacheInterface {
+		return &flightCache{CacheInterface: c, flights: make(map[interface{}]*flight)}
+	}
+}
+
+// NamespaceMiddleware confines keys to namespace `ns`
+// by writing them as composite keys ( see `Set2` ).
+// When the wrapped cache is an `LRU`, `Purge` only
+// purges the namespace and `Len` only counts it.
+func NamespaceMiddleware(ns interface{}) Middleware {
+	return func(c CacheInterface) CacheInterface {
+		return &namespaceCache{inner: c, ns: ns}
+	}
+}
+
+// TransformMiddleware applies `ts`; see `Transform`.
+func TransformMiddleware(ts ...Transformer) Middleware {
+	return func(c CacheInterface) CacheInterface {
+		return Transform(c, ts...)
+	}
+}
+
+// - MARK: interceptor section.
+
+// Set conforms to `CacheInterface`.
+func (ic *interceptor) Set(key interface{}, value interface{}) (bool, error) {
+	return ic.SetCtx(context.Background(), key, value)
+}
+
+// Get conforms to `CacheInterface`.
+func (ic *interceptor) Get(key interface{}) (interface{}, error) {
+	return ic.GetCtx(context.Background(), key)
+}
+
+// Read conforms to `CacheInterface`.
+func (ic *interceptor) Read(key interface{}) (value interface{}) {
+	ic.around(context.Background(), OpREAD, key, func(context.Context) error {
+		value = ic.inner.Read(key)
+		return nil
+	})
+	return value
+}
+
+// Remove conforms to `Remover` when the wrapped
+// cache does.
+func (ic *interceptor) Remove(key interface{}) (ok bool) {
+	ic.around(context.Background(), OpREMOVE, key, func(context.Context) error {
+		if r, isRemover := ic.inner.(Remover); isRemover {
+			ok = r.Remove(key)
+		}
+		return nil
+	})
+	return ok
+}
+
+// Purge conforms to `CacheInterface`.
+func (ic *interceptor) Purge() {
+	ic.around(context.Background(), OpPURGE, nil, func(context.Context) error {
+		ic.inner.Purge()
+		return nil
+	})
+}
+
+// Len conforms to `CacheInterface`.
+func (ic *interceptor) Len() int {
+	return ic.inner.Len()
+}
+
+// - MARK: flightCache section.
+
+// Get conforms to `CacheInterface`.
+func (fc *flightCache) Get(key interface{}) (interface{}, error) {
+	return fc.get(context.Background(), key)
+}
+
+// get fetches `key` from the wrapped cache unless
+// a get of it is in flight, in which case it waits
+// for its result until `ctx` is done. Waiters retry
+// when the get failed as its own context was done,
+// and fail with `ELRUPANICKED` when it panicked.
+func (fc *flightCache) get(ctx context.Context, key interface{}) (value interface{}, err error) {
+	var (
+		f  *flight
+		ok bool
+	)
+	for {
+		fc.mu.Lock()
+		if f, ok = fc.flights[key]; !ok {
+			break
+		}
+		fc.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !f.canceled {
+			return f.value, f.err
+		}
+	}
+	f = &flight{done: make(chan struct{})}
+	fc.flights[key] = f
+	fc.mu.Unlock()
+	defer func() {
+		r := recover()
+		if r != nil {
+			f.value, f.err = nil, ELRUPANICKED
+		}
+		fc.mu.Lock()
+		delete(fc.flights, key)
+		fc.mu.Unlock()
+		close(f.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	f.value, f.err = getCtx(ctx, fc.CacheInterface, key)
+	f.canceled = f.err != nil && ctx.Err() != nil && errors.Is(f.err, ctx.Err())
+	return f.value, f.err
+}
+
+// Remove conforms to `Remover` when the wrapped
+// cache does.
+func (fc *flightCache) Remove(key interface{}) bool {
+	if r, ok := fc.CacheInterface.(Remover); ok {
+		return r.Remove(key)
+	}
+	return false
+}
+
+// - MARK: namespaceCache section.
+
+// Set conforms to `CacheInterface`.
+func (nc *namespaceCache) Set(key interface{}, value interface{}) (bool, error) {
+	return nc.inner.Set(compositeKey{nc.ns, key}, value)
+}
+
+// Get conforms to `CacheInterface`.
+func (nc *namespaceCache) Get(key interface{}) (interface{}, error) {
+	return nc.inner.Get(compositeKey{nc.ns, key})
+}
+
+// Read conforms to `CacheInterface`.
+func (nc *namespaceCache) Read(key interface{}) interface{} {
+	return nc.inner.Read(compositeKey{nc.ns, key})
+}
+
+// Remove conforms to `Remover` when the wrapped
+// cache does.
+func (nc *namespaceCache) Remove(key interface{}) bool {
+	if r, ok := nc.inner.(Remover); ok {
+		return r.Remove(compositeKey{nc.ns, key})
+	}
+	return false
+}
+
+// Purge conforms to `CacheInterface`.
+func (nc *namespaceCache) Purge() {
+	if lru, ok := nc.inner.(*LRU); ok {
+		lru.PurgeNamespace(nc.ns)
+		return
+	}
+	nc.inner.Purge()
+}
+
+// Len conforms to `CacheInterface`.
+func (nc *namespaceCache) Len() (l int) {
+	if lru, ok := nc.inner.(*LRU); ok {
+		lru.mu.Lock()
+		l = len(lru.opts.namespaces[nc.ns])
+		lru.mu.Unlock()
+		return l
+	}
+	return nc.inner.Len()
+}
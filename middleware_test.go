@@ -0,0 +1,137 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	var (
+		lru     *LRU = NewLRU(8)
+		metrics Metrics
+		logs    bytes.Buffer
+		spans   []string
+		c       CacheInterface
+	)
+	c = Wrap(lru,
+		MetricsMiddleware(&metrics),
+		LoggingMiddleware(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		TracingMiddleware(func(op string, key interface{}) func(error) {
+			return func(err error) { spans = append(spans, op) }
+		}),
+		NamespaceMiddleware("tenant"),
+	)
+	c.Set("a", 1)
+	c.Get("a")
+	c.Get("b")
+	if lru.Read2("tenant", "a") != 1 || c.Len() != 1 {
+		t.Fatal("assertion failed, expected namespaced keys.")
+	}
+	if metrics.Gets != 2 || metrics.Hits != 1 || metrics.Misses != 1 || metrics.Sets != 1 || metrics.Errors != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", metrics)
+	}
+	if strings.Join(spans, ",") != "set,get,get" || !strings.Contains(logs.String(), "op=get key=a") {
+		t.Fatal("assertion failed, expected traced and logged operations.", spans, logs.String())
+	}
+	lru.Set("other", 1)
+	c.Purge()
+	if lru.Len() != 1 || c.(Remover).Remove("a") {
+		t.Fatal("assertion failed, expected namespace purged only.", lru.Len())
+	}
+}
+
+func TestSingleflightMiddleware(t *testing.T) {
+	var (
+		loads   int32
+		release chan struct{} = make(chan struct{})
+		lru     *LRU          = NewLRU(8, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			atomic.AddInt32(&loads, 1)
+			<-release
+			return nil, errors.New("boom")
+		})))
+		c  CacheInterface = Wrap(lru, SingleflightMiddleware())
+		wg sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get("k"); err == nil {
+				t.Error("assertion failed, expected shared error.")
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if atomic.LoadInt32(&loads) != 1 {
+		t.Fatal("assertion failed, expected coalesced gets.", loads)
+	}
+}
+
+func TestSingleflightMiddlewarePanic(t *testing.T) {
+	var (
+		loads   int32
+		release chan struct{} = make(chan struct{})
+		lru     *LRU          = NewLRU(8, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			if atomic.AddInt32(&loads, 1) == 1 {
+				<-release
+				panic("get failed")
+			}
+			return key, nil
+		})))
+		c  CacheInterface = Wrap(lru, SingleflightMiddleware())
+		wg sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != "get failed" {
+				t.Error("assertion failed, expected panic to propagate.", r)
+			}
+		}()
+		c.Get("k")
+	}()
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		defer wg.Done()
+		if _, err := c.Get("k"); err != ELRUPANICKED {
+			t.Error("assertion failed, inconsistent state. expected equal.", err)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if value, err := c.Get("k"); value != "k" || err != nil {
+		t.Fatal("assertion failed, expected get after panic.", value, err)
+	}
+}
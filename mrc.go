@@ -0,0 +1,158 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"hash/fnv"
+	"math"
+)
+
+// mrcMODULUS is the hash space of spatial sampling.
+const mrcMODULUS = 1 << 24
+
+// mrcSCALES are capacity multiples at which hit
+// ratios are estimated.
+var mrcSCALES = [...]float64{0.5, 1, 2, 4}
+
+// CurvePoint is an estimated hit ratio of the cache
+// at `Scale` times its capacity.
+type CurvePoint struct {
+	Scale    float64
+	HitRatio float64
+}
+
+// mrc estimates the miss ratio curve following
+// SHARDS: keys whose hash falls below a threshold
+// are sampled at `rate`, and each sampled access is
+// replayed in mini LRU simulations sized to the
+// sampled share of each capacity multiple.
+type mrc struct {
+	rate      float64
+	threshold uint64
+	sims      [len(mrcSCALES)]*simLRU
+}
+
+// simLRU is an LRU of key hashes without values.
+type simLRU struct {
+	size     int
+	order    *list.List
+	index    map[uint64]*list.Element
+	hits     uint64
+	accesses uint64
+}
+
+// - MARK: Alloc/Init section.
+
+// newMRC allocates and initializes a new `mrc`
+// struct sampling keys at `rate` of a cache with
+// `capacity` enteries and returns a pointer to it.
+func newMRC(rate float64, capacity int) (m *mrc) {
+	m = &mrc{rate: rate, threshold: uint64(rate * mrcMODULUS)}
+	for i, _ := range m.sims {
+		m.sims[i] = &simLRU{order: list.New(), index: make(map[uint64]*list.Element)}
+	}
+	m.resize(capacity)
+	return m
+}
+
+// - MARK: mrc section.
+
+// access records an access of `key`.
+func (m *mrc) access(key interface{}) {
+	var (
+		h uint64 = hashKey(key)
+	)
+	if h%mrcMODULUS >= m.threshold {
+		return
+	}
+	for _, sim := range m.sims {
+		sim.access(h)
+	}
+}
+
+// curve returns estimated hit ratios.
+func (m *mrc) curve() (points []CurvePoint) {
+	points = make([]CurvePoint, len(mrcSCALES))
+	for i, sim := range m.sims {
+		points[i].Scale = mrcSCALES[i]
+		if sim.accesses > 0 {
+			points[i].HitRatio = float64(sim.hits) / float64(sim.accesses)
+		}
+	}
+	return points
+}
+
+// resize scales simulations to `capacity`.
+func (m *mrc) resize(capacity int) {
+	for i, sim := range m.sims {
+		sim.size = max(1, int(math.Round(float64(capacity)*mrcSCALES[i]*m.rate)))
+		sim.trim()
+	}
+}
+
+// reset zeroes counters of simulations.
+func (m *mrc) reset() {
+	for _, sim := range m.sims {
+		sim.hits, sim.accesses = 0, 0
+	}
+}
+
+// - MARK: simLRU section.
+
+// access replays an access of key hash `h`.
+func (sim *simLRU) access(h uint64) {
+	sim.accesses++
+	if elem, ok := sim.index[h]; ok {
+		sim.hits++
+		sim.order.MoveToFront(elem)
+		return
+	}
+	sim.index[h] = sim.order.PushFront(h)
+	sim.trim()
+}
+
+// trim evicts hashes beyond size.
+func (sim *simLRU) trim() {
+	for sim.order.Len() > sim.size {
+		delete(sim.index, sim.order.Remove(sim.order.Back()).(uint64))
+	}
+}
+
+// hashKey returns a well mixed 64 bit hash of
+// `key`, avoiding formatting for common key types.
+func hashKey(key interface{}) uint64 {
+	switch k := key.(type) {
+	case string:
+		h := fnv.New64a()
+		h.Write([]byte(k))
+		return mix64(h.Sum64())
+	case int:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	}
+	return mix64(hashValue(key))
+}
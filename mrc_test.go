@@ -0,0 +1,61 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLRUMissRatioCurve(t *testing.T) {
+	var (
+		lru   *LRU       = NewLRU(1000, WithMRC(0.1))
+		rng   *rand.Rand = rand.New(rand.NewSource(1))
+		stats Stats
+	)
+	// uniform accesses over 2000 keys: a cache of n
+	// enteries hits about n/2000 of the time
+	for i := 0; i < 200000; i++ {
+		key := rng.Intn(2000)
+		if v, _ := lru.Get(key); v == nil {
+			lru.Set(key, key)
+		}
+	}
+	stats = lru.Stats()
+	if len(stats.Curve) != 4 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Curve)
+	}
+	for i, expected := range []float64{0.25, 0.5, 1, 1} {
+		if p := stats.Curve[i]; math.Abs(p.HitRatio-expected) > 0.08 {
+			t.Fatal("assertion failed, estimate too far off.", p, expected)
+		}
+	}
+	if math.Abs(stats.Curve[1].HitRatio-stats.HitRatio()) > 0.08 {
+		t.Fatal("assertion failed, expected 1x estimate near observed ratio.", stats.Curve[1], stats.HitRatio())
+	}
+	lru.ResetStats()
+	if lru.Stats().Curve[0].HitRatio != 0 {
+		t.Fatal("assertion failed, expected reset curve.")
+	}
+}
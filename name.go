@@ -0,0 +1,90 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "sort"
+
+// - MARK: Alloc/Init section.
+
+// WithName names the cache so that multi-cache
+// applications can tell instances apart; the name
+// is reported by `Stats`, events, `DebugDump`,
+// snapshots, `LoggingMiddleware` and used by
+// `Manager.Register` by default.
+func WithName(name string) Option {
+	return func(lru *LRU) {
+		lru.opts.name = name
+	}
+}
+
+// WithLabels attaches arbitrary `labels` ( e.g.
+// tier or owning service ) to the cache, which are
+// reported wherever its name is. Labels of repeated
+// options are merged.
+func WithLabels(labels map[string]string) Option {
+	return func(lru *LRU) {
+		if lru.opts.labels == nil {
+			lru.opts.labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			lru.opts.labels[k] = v
+		}
+	}
+}
+
+// - MARK: LRU section.
+
+// Name returns name of the cache as configured
+// with `WithName`.
+func (lru *LRU) Name() string {
+	return lru.opts.name
+}
+
+// Labels returns a copy of labels of the cache
+// as configured with `WithLabels`.
+func (lru *LRU) Labels() map[string]string {
+	return copyLabels(lru.opts.labels)
+}
+
+// copyLabels returns a copy of `labels`, or `nil`
+// when there are none.
+func copyLabels(labels map[string]string) (cp map[string]string) {
+	if len(labels) == 0 {
+		return nil
+	}
+	cp = make(map[string]string, len(labels))
+	for k, v := range labels {
+		cp[k] = v
+	}
+	return cp
+}
+
+// sortedLabels returns names of `labels` in
+// sorted order.
+func sortedLabels(labels map[string]string) (names []string) {
+	for k, _ := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
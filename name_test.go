@@ -0,0 +1,69 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLRUName(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(4, WithName("sessions"), WithLabels(map[string]string{"tier": "l1"}))
+		buf bytes.Buffer
+		m   *Manager = NewManager(t.TempDir())
+	)
+	events, cancel := lru.Subscribe(1)
+	defer cancel()
+	lru.Set("a", 1)
+	if ev := <-events; ev.Cache != "sessions" {
+		t.Fatal("assertion failed, expected named event.", ev)
+	}
+	if stats := lru.Stats(); stats.Name != "sessions" || stats.Labels["tier"] != "l1" {
+		t.Fatal("assertion failed, expected named stats.", stats.Name, stats.Labels)
+	}
+	lru.Labels()["tier"] = "l2"
+	if lru.Labels()["tier"] != "l1" {
+		t.Fatal("assertion failed, expected labels to be copied.")
+	}
+	lru.DebugDump(&buf)
+	if !strings.Contains(buf.String(), `name="sessions"`) || !strings.Contains(buf.String(), "label tier=l1") {
+		t.Fatal("assertion failed, expected named dump.", buf.String())
+	}
+	buf.Reset()
+	lru.Export(&buf)
+	if info, err := SnapshotInfo(&buf); err != nil || info.Name != "sessions" || info.Labels["tier"] != "l1" {
+		t.Fatal("assertion failed, expected named snapshot.", info, err)
+	}
+	m.Register("", lru)
+	if names := m.Names(); len(names) != 1 || names[0] != "sessions" {
+		t.Fatal("assertion failed, expected registration by name.", names)
+	}
+	buf.Reset()
+	Wrap(lru, LoggingMiddleware(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))).Get("a")
+	if !strings.Contains(buf.String(), "cache=sessions") {
+		t.Fatal("assertion failed, expected named log records.", buf.String())
+	}
+}
@@ -0,0 +1,123 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// compositeKey is the lookup key of enteries
+// written with a (namespace, id) pair.
+type compositeKey struct {
+	ns, id interface{}
+}
+
+// - MARK: LRU section.
+
+// Set2 writes the value associated to the
+// composite key (`ns`, `id`). It is same as
+// `Set` except that the entery is indexed
+// per namespace; see `PurgeNamespace`.
+func (lru *LRU) Set2(ns interface{}, id interface{}, value interface{}) (isNew bool, err error) {
+	return lru.Set(compositeKey{ns, id}, value)
+}
+
+// Get2 fetches the value associated to the
+// composite key (`ns`, `id`).
+func (lru *LRU) Get2(ns interface{}, id interface{}) (value interface{}, err error) {
+	return lru.Get(compositeKey{ns, id})
+}
+
+// Read2 reads the value associated to the
+// composite key (`ns`, `id`) without touching
+// cache counters.
+func (lru *LRU) Read2(ns interface{}, id interface{}) (value interface{}) {
+	return lru.Read(compositeKey{ns, id})
+}
+
+// Remove2 removes the entery associated to the
+// composite key (`ns`, `id`) and returns `true`
+// when succesfull.
+func (lru *LRU) Remove2(ns interface{}, id interface{}) (ok bool) {
+	return lru.Remove(compositeKey{ns, id})
+}
+
+// PurgeNamespace removes all enteries written
+// under `ns` and returns number of removed
+// enteries. Its cost is proportional to the
+// size of the namespace rather than the cache.
+func (lru *LRU) PurgeNamespace(ns interface{}) (n int) {
+	var (
+		ids  map[interface{}]*list.Element
+		elem *list.Element
+		ok   bool
+	)
+	lru.mu.Lock()
+	if ids, ok = lru.opts.namespaces[ns]; ok {
+		delete(lru.opts.namespaces, ns)
+		for _, elem = range ids {
+			lru.unlink(elem, EvictPURGED)
+			n++
+		}
+	}
+	if lru.opts.readmit != nil {
+		lru.opts.readmit.dropFunc(func(key, value interface{}) bool {
+			ck, ok := key.(compositeKey)
+			return ok && ck.ns == ns
+		})
+	}
+	lru.mu.Unlock()
+	return n
+}
+
+// nsLink registers `elem` in the index of its
+// namespace. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) nsLink(ck compositeKey, elem *list.Element) {
+	var (
+		ids map[interface{}]*list.Element
+		ok  bool
+	)
+	if ids, ok = lru.opts.namespaces[ck.ns]; !ok {
+		ids = make(map[interface{}]*list.Element)
+		lru.opts.namespaces[ck.ns] = ids
+	}
+	ids[ck.id] = elem
+}
+
+// nsUnlink removes `ck` from the index of its
+// namespace. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) nsUnlink(ck compositeKey) {
+	var (
+		ids map[interface{}]*list.Element
+		ok  bool
+	)
+	if ids, ok = lru.opts.namespaces[ck.ns]; !ok {
+		return
+	}
+	delete(ids, ck.id)
+	if len(ids) == 0 {
+		delete(lru.opts.namespaces, ck.ns)
+	}
+}
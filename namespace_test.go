@@ -0,0 +1,54 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestLRUNamespace(t *testing.T) {
+	var (
+		lru   *LRU = NewLRU(8)
+		value interface{}
+	)
+	for i := 0; i < 3; i++ {
+		lru.Set2("users", i, i)
+		lru.Set2("groups", i, i)
+	}
+	if value, _ = lru.Get2("users", 1); value != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	if lru.Read2("groups", 2) != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if !lru.Remove2("users", 0) || lru.Read2("users", 0) != nil {
+		t.Fatal("assertion failed, expected removed entery.")
+	}
+	if n := lru.PurgeNamespace("users"); n != 2 {
+		t.Fatalf("assertion failed, expected equal with value(2) - got value(%d).", n)
+	}
+	if lru.Len() != 3 || lru.Read2("users", 1) != nil || lru.Read2("groups", 1) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if _, ok := lru.opts.namespaces["users"]; ok {
+		t.Fatal("assertion failed, expected empty namespace index to be released.")
+	}
+}
@@ -0,0 +1,178 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*ObjectCache)(nil)
+	_ Remover        = (*ObjectCache)(nil)
+)
+
+// ErrObjectNotFound must be returned, possibly
+// wrapped, by `ObjectStore.Get` for missing
+// objects.
+var ErrObjectNotFound error = errors.New("cache(object): object not found.")
+
+// ObjectStore is protocol definition for object
+// storage such as S3 buckets. An S3 adapter maps
+// the calls to GetObject, PutObject ( with the
+// body streamed from `r` ) and DeleteObject, e.g.
+//
+//	func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+//		out, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: &s.bucket, Key: &key})
+//		var nsk *types.NoSuchKey
+//		if errors.As(err, &nsk) {
+//			return nil, cache.ErrObjectNotFound
+//		}
+//		if err != nil {
+//			return nil, err
+//		}
+//		return out.Body, nil
+//	}
+type ObjectStore interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Delete(ctx context.Context, key string) error
+}
+
+// ObjectCache is a cache level backed by an
+// `ObjectStore`, so that a `ChainCache` can spill
+// to or read through a bucket. Values are byte
+// slices; large values should be streamed with
+// `SetReader` and `GetReader` instead. Keys are
+// formatted with `fmt.Sprint` and prefixed. Note,
+// distinct keys formatting alike ( e.g. `1` and
+// `"1"` ) thus share an object; keys of a cache
+// should be of a single type.
+type ObjectCache struct {
+	store  ObjectStore
+	prefix string
+}
+
+// - MARK: Alloc/Init section.
+
+// NewObjectCache allocates and initializes a new
+// `ObjectCache` struct storing objects in `store`
+// under `prefix` and returns a pointer to it.
+func NewObjectCache(store ObjectStore, prefix string) *ObjectCache {
+	return &ObjectCache{store: store, prefix: prefix}
+}
+
+// - MARK: ObjectCache section.
+
+// Set writes `value`, which must be a `[]byte`, as
+// the object of `key`. Objects are overwritten, so
+// `isNew` is always false.
+func (oc *ObjectCache) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	return oc.SetCtx(context.Background(), key, value)
+}
+
+// SetCtx is same as `Set` except that `ctx` is
+// passed to the store.
+func (oc *ObjectCache) SetCtx(ctx context.Context, key interface{}, value interface{}) (isNew bool, err error) {
+	var (
+		data []byte
+		ok   bool
+	)
+	if data, ok = value.([]byte); !ok {
+		return false, ELRUINVALTYPE
+	}
+	return false, oc.SetReader(ctx, key, bytes.NewReader(data), int64(len(data)))
+}
+
+// Get reads the object of `key`. Missing objects
+// are reported as `nil` values.
+func (oc *ObjectCache) Get(key interface{}) (value interface{}, err error) {
+	return oc.GetCtx(context.Background(), key)
+}
+
+// GetCtx is same as `Get` except that `ctx` is
+// passed to the store.
+func (oc *ObjectCache) GetCtx(ctx context.Context, key interface{}) (value interface{}, err error) {
+	var (
+		rc   io.ReadCloser
+		data []byte
+	)
+	if rc, err = oc.GetReader(ctx, key); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer rc.Close()
+	if data, err = io.ReadAll(rc); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Read is same as `Get` except that errors are
+// reported as `nil` values.
+func (oc *ObjectCache) Read(key interface{}) (value interface{}) {
+	value, _ = oc.Get(key)
+	return value
+}
+
+// Remove deletes the object of `key`. Object
+// stores typically don't report whether the
+// object existed, so it returns true whenever
+// the deletion succeeds.
+func (oc *ObjectCache) Remove(key interface{}) bool {
+	return oc.store.Delete(context.Background(), oc.name(key)) == nil
+}
+
+// Purge is a no-op; object stores are purged
+// with their own lifecycle rules.
+func (oc *ObjectCache) Purge() {}
+
+// Len returns zero since object stores can't be
+// counted cheaply.
+func (oc *ObjectCache) Len() int {
+	return 0
+}
+
+// SetReader streams `size` bytes from `r` as the
+// object of `key`.
+func (oc *ObjectCache) SetReader(ctx context.Context, key interface{}, r io.Reader, size int64) error {
+	return oc.store.Put(ctx, oc.name(key), r, size)
+}
+
+// GetReader returns a stream of the object of
+// `key`, which the caller must close.
+func (oc *ObjectCache) GetReader(ctx context.Context, key interface{}) (io.ReadCloser, error) {
+	return oc.store.Get(ctx, oc.name(key))
+}
+
+// name returns the object name of `key`, which
+// collides for keys formatting alike.
+func (oc *ObjectCache) name(key interface{}) string {
+	return oc.prefix + fmt.Sprint(key)
+}
@@ -0,0 +1,118 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memoryStore is an in-memory `ObjectStore`.
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// Get conforms to `ObjectStore`.
+func (ms *memoryStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	data, ok := ms.objects[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Put conforms to `ObjectStore`.
+func (ms *memoryStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	ms.mu.Lock()
+	ms.objects[key] = data
+	ms.mu.Unlock()
+	return nil
+}
+
+// Delete conforms to `ObjectStore`.
+func (ms *memoryStore) Delete(ctx context.Context, key string) error {
+	ms.mu.Lock()
+	delete(ms.objects, key)
+	ms.mu.Unlock()
+	return nil
+}
+
+func TestObjectCacheChain(t *testing.T) {
+	var (
+		store *memoryStore = &memoryStore{objects: make(map[string][]byte)}
+		l1    *LRU         = NewLRU(4)
+		oc    *ObjectCache = NewObjectCache(store, "cache/")
+		cc    *ChainCache  = Chain(l1, oc)
+	)
+	if _, err := cc.Set("a", []byte("alpha")); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	if string(store.objects["cache/a"]) != "alpha" {
+		t.Fatal("assertion failed, expected spilled object.", store.objects)
+	}
+	l1.Purge()
+	if v, err := cc.Get("a"); err != nil || string(v.([]byte)) != "alpha" || l1.Read("a") == nil {
+		t.Fatal("assertion failed, expected read-through and back-fill.", v, err)
+	}
+	if v, err := cc.Get("missing"); v != nil || err != nil {
+		t.Fatal("assertion failed, expected miss.", v, err)
+	}
+	if _, err := oc.Set("b", "not bytes"); err != ELRUINVALTYPE {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+	cc.Remove("a")
+	if oc.Read("a") != nil {
+		t.Fatal("assertion failed, expected removed object.")
+	}
+}
+
+func TestObjectCacheStreaming(t *testing.T) {
+	var (
+		store *memoryStore = &memoryStore{objects: make(map[string][]byte)}
+		oc    *ObjectCache = NewObjectCache(store, "")
+		large string       = strings.Repeat("x", 1<<20)
+		ctx                = context.Background()
+	)
+	if err := oc.SetReader(ctx, 1, strings.NewReader(large), int64(len(large))); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	rc, err := oc.GetReader(ctx, 1)
+	if err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	defer rc.Close()
+	if n, _ := io.Copy(io.Discard, rc); n != int64(len(large)) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n)
+	}
+}
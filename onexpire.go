@@ -0,0 +1,209 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// expireState holds expiration listeners, see
+// `OnExpire`.
+type expireState struct {
+	global  map[*expireListener]struct{}
+	keyed   map[interface{}]map[*expireListener]struct{}
+	pending []expiredEntry
+	stopped bool // see `Stop`
+	wake    chan struct{}
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// expireListener is a listener registered by
+// `OnExpire`.
+type expireListener struct {
+	fn func(key, value interface{})
+}
+
+// expiredEntry is an expired entery pending
+// delivery to listeners.
+type expiredEntry struct {
+	key, value interface{}
+	listeners  []*expireListener
+}
+
+// - MARK: LRU section.
+
+// OnExpire registers `fn` to be called with `key`
+// and its value once the entery expires, or with
+// every expiring entery when `key` is `nil`. Per key
+// listeners fire once; global ones until cancelled.
+// Rather than waiting for the next access or sweep,
+// a goroutine reaps enteries as soon as they become
+// reapable ( see `NextExpiry` ) off the expiry queue,
+// which is enabled when missing ( see
+// `WithExpiryQueue` ). Listeners run on that
+// goroutine without the cache being locked, so they
+// may call back into the cache, but should return
+// quickly since they delay later ones. The goroutine
+// runs until `Stop` is called. It returns a function
+// that cancels the listener.
+func (lru *LRU) OnExpire(key interface{}, fn func(key, value interface{})) (cancel func()) {
+	var (
+		l  *expireListener = &expireListener{fn}
+		es *expireState
+	)
+	lru.mu.Lock()
+	if es = lru.opts.expire; es == nil {
+		es = lru.watchExpiry()
+	}
+	if key == nil {
+		es.global[l] = struct{}{}
+	} else {
+		if es.keyed[key] == nil {
+			es.keyed[key] = make(map[*expireListener]struct{})
+		}
+		es.keyed[key][l] = struct{}{}
+	}
+	lru.mu.Unlock()
+	return func() {
+		lru.mu.Lock()
+		if key == nil {
+			delete(es.global, l)
+		} else if ls, ok := es.keyed[key]; ok {
+			if delete(ls, l); len(ls) == 0 {
+				delete(es.keyed, key)
+			}
+		}
+		lru.mu.Unlock()
+	}
+}
+
+// watchExpiry enables the expiry queue when missing
+// and spawns the goroutine reaping enteries due
+// in it. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) watchExpiry() (es *expireState) {
+	es = &expireState{
+		global: make(map[*expireListener]struct{}),
+		keyed:  make(map[interface{}]map[*expireListener]struct{}),
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+	lru.opts.expire = es
+	if lru.opts.expiries == nil {
+		WithExpiryQueue()(lru)
+		for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+			lru.queueExpiry(elem.Value.(*LRUItem))
+		}
+	}
+	lru.opts.expiries.wake = es.wake
+	go lru.runExpiry(es)
+	return es
+}
+
+// runExpiry reaps due enteries and delivers them
+// to listeners until stopped.
+func (lru *LRU) runExpiry(es *expireState) {
+	var (
+		timer   *time.Timer = time.NewTimer(time.Hour)
+		expired []expiredEntry
+		wait    time.Duration
+	)
+	defer timer.Stop()
+	for {
+		lru.mu.Lock()
+		wait = lru.reapDue(lru.now())
+		expired, es.pending = es.pending, nil
+		lru.mu.Unlock()
+		for _, e := range expired {
+			for _, l := range e.listeners {
+				l.fn(e.key, e.value)
+			}
+		}
+		if wait > 0 {
+			timer.Reset(wait)
+		} else {
+			timer.Stop()
+		}
+		select {
+		case <-timer.C:
+		case <-es.wake:
+		case <-es.stop:
+			return
+		}
+	}
+}
+
+// reapDue removes enteries due in the expiry queue
+// at `now` and returns the time until the next one
+// is due, or zero when none expires. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) reapDue(now int64) time.Duration {
+	var (
+		q *expiryQueue = lru.opts.expiries
+	)
+	for len(q.heap) > 0 && q.heap[0].at <= now {
+		if elem, ok := lru.lookup[q.heap[0].key]; ok {
+			lru.unlink(elem, lru.expiredReason(elem.Value.(*LRUItem)))
+		} else {
+			q.update(q.heap[0].key, 0)
+		}
+	}
+	if len(q.heap) == 0 {
+		return 0
+	}
+	return time.Duration(q.heap[0].at - now)
+}
+
+// expired queues `key` and `value` of an expired
+// entery for delivery to its listeners. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) expired(key interface{}, value interface{}) {
+	var (
+		es        *expireState = lru.opts.expire
+		listeners []*expireListener
+	)
+	if es.stopped {
+		// nothing delivers them anymore
+		return
+	}
+	for l, _ := range es.keyed[key] {
+		listeners = append(listeners, l)
+	}
+	delete(es.keyed, key)
+	for l, _ := range es.global {
+		listeners = append(listeners, l)
+	}
+	if len(listeners) == 0 {
+		return
+	}
+	es.pending = append(es.pending, expiredEntry{key, value, listeners})
+	select {
+	case es.wake <- struct{}{}:
+	default:
+	}
+}
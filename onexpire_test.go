@@ -0,0 +1,81 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUOnExpire(t *testing.T) {
+	var (
+		lru    *LRU             = NewLRU(8)
+		keyed  chan interface{} = make(chan interface{}, 4)
+		global chan interface{} = make(chan interface{}, 4)
+	)
+	defer lru.Stop()
+	lru.SetWithTTL("a", 1, 20*time.Millisecond)
+	lru.Set("forever", 0)
+	lru.OnExpire("a", func(key, value interface{}) { keyed <- value })
+	cancel := lru.OnExpire("b", func(key, value interface{}) { keyed <- value })
+	lru.OnExpire(nil, func(key, value interface{}) { global <- key })
+	lru.SetWithTTL("b", 2, 10*time.Millisecond)
+	cancel()
+	started := time.Now()
+	select {
+	case value := <-keyed:
+		if value != 1 || time.Since(started) < 15*time.Millisecond {
+			t.Fatal("assertion failed, expected listener of the expired key.", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("assertion failed, expected listener to fire without access.")
+	}
+	// global listeners see all expiries in order
+	for _, want := range []interface{}{"b", "a"} {
+		if key := <-global; key != want {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", key, want)
+		}
+	}
+	if lru.Len() != 1 || len(keyed) != 0 {
+		t.Fatal("assertion failed, expected expired enteries to be reaped.", lru.Len())
+	}
+}
+
+func TestLRUOnExpireStopped(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(8, WithClock(clock))
+	)
+	lru.OnExpire(nil, func(key, value interface{}) {})
+	lru.Stop()
+	// expired once stopped
+	lru.SetWithTTL("a", 1, time.Second)
+	clock.now = clock.now.Add(2 * time.Second)
+	v, _ := lru.Get("a")
+	lru.mu.Lock()
+	pending := len(lru.opts.expire.pending)
+	lru.mu.Unlock()
+	if v != nil || pending != 0 {
+		t.Fatal("assertion failed, expected undelivered enteries to be dropped.", v, pending)
+	}
+}
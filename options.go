@@ -0,0 +1,423 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Option configures optional behaviours of
+// `LRU` at construction time.
+type Option func(*LRU)
+
+// lruOptions is the container for optional
+// behaviours and their associated state.
+type lruOptions struct {
+	clock      Clock
+	defaultTTL time.Duration
+	janitor    *janitor
+	tiers      []Tier
+	tiered     map[interface{}]*tierState
+	idleTTL    time.Duration
+	idle       map[interface{}]time.Duration
+	wheel      *TimingWheel
+	expiries   *expiryQueue
+	expire     *expireState
+	name       string
+	labels     map[string]string
+	// read-through
+	loader        LoaderCtx
+	loads         map[interface{}]*loadCall
+	cancellation  LoadCancel
+	detachTimeout time.Duration
+	admitLatency  func(key interface{}, latency time.Duration) bool
+	validator     Validator
+	validateGets  bool
+	valueType     reflect.Type
+	grace         time.Duration
+	onGrace       func(ctx context.Context, key interface{}, err error)
+	errorBase     time.Duration
+	errorMax      time.Duration
+	failures      map[interface{}]*loadFailure
+	limits        *loadLimiter
+	purge         *purgeState
+	warmup        *warmupState
+	prefetch      *prefetchState
+	// cost
+	costFn    func(key, value interface{}) int64
+	maxCost   int64
+	totalCost int64
+	// load guard
+	maxLoadCost  int64
+	maxLoadShare float64
+	// secondary indexes
+	namespaces map[interface{}]map[interface{}]*list.Element
+	identity   func(interface{}) interface{}
+	reverse    map[interface{}]map[interface{}]struct{}
+	aliases    map[interface{}]map[interface{}]struct{}
+	pins       map[interface{}]struct{}
+	tags       map[interface{}]map[interface{}]struct{}
+	// tombstones
+	tombstoneTTL time.Duration
+	tombstones   map[interface{}]tombstone
+	// replication
+	hlc   *HLC
+	merge MergeFunc
+	// statistics
+	stats      Counters
+	namespaced map[interface{}]*Counters
+	ghost      *ghost
+	mrc        *mrc
+	keyStats   *keyStats
+	shadows    []*shadowState
+	// sizing
+	control     *controlState
+	admitter    Admitter
+	admitterGen uint64
+	// events
+	onEvict     func(key, value interface{}, reason EvictReason)
+	cleanup     *cleanupState
+	beforeEvict func(key, value interface{}) bool
+	maxVetoes   int
+	overflow    *overflowState
+	coalesce    *coalesceState
+	readmit     *readmitBuffer
+	subscribers map[chan Event]struct{}
+	listeners   map[*evictListener]struct{}
+	handoffs    map[*handoffState]struct{}
+	// debug validation
+	validateEvery int
+	validateN     int
+	onInvalid     func(error)
+	// reference counting
+	refs map[interface{}]*valueRef
+	pool *BytePool
+	// leases
+	leases   map[interface{}]lease
+	leaseSeq uint64
+}
+
+// newLRUOptions allocates and initializes a new
+// `lruOptions` struct and returns a pointer to it.
+func newLRUOptions() *lruOptions {
+	return &lruOptions{
+		loads:      make(map[interface{}]*loadCall),
+		namespaces: make(map[interface{}]map[interface{}]*list.Element),
+		aliases:    make(map[interface{}]map[interface{}]struct{}),
+	}
+}
+
+// WithClock makes the cache read time from
+// `clock` instead of the system clock, e.g. to
+// simulate expiry or clock skew in tests.
+func WithClock(clock Clock) Option {
+	return func(lru *LRU) {
+		lru.opts.clock = clock
+	}
+}
+
+// WithLoader configures the cache in read-through
+// mode; misses are loaded from `loader` and written
+// to the cache without expiry.
+func WithLoader(loader Loader) Option {
+	return func(lru *LRU) {
+		if loader == nil {
+			lru.opts.loader = nil
+			return
+		}
+		lru.opts.loader = ctxAdapter{loaderAdapter{loader}}
+	}
+}
+
+// WithLoaderTTL configures the cache in read-through
+// mode; misses are loaded from `loader` which also
+// dictates freshness of each loaded entery.
+func WithLoaderTTL(loader LoaderWithTTL) Option {
+	return func(lru *LRU) {
+		if loader == nil {
+			lru.opts.loader = nil
+			return
+		}
+		lru.opts.loader = ctxAdapter{loader}
+	}
+}
+
+// WithIdentity maintains a reverse index from
+// value identities, as computed by `fn`, to
+// the keys holding them; see `RemoveValue` and
+// `KeysFor`. Values for which `fn` returns `nil`
+// are not indexed.
+func WithIdentity(fn func(value interface{}) interface{}) Option {
+	return func(lru *LRU) {
+		lru.opts.identity = fn
+		lru.opts.reverse = make(map[interface{}]map[interface{}]struct{})
+	}
+}
+
+// WithTombstones makes `Remove` leave a tombstone
+// behind for `ttl`, during which versioned writes
+// ( see `SetVersion` ) not newer than the removed
+// entery are rejected. This prevents late replicated
+// writes from resurrecting deleted enteries.
+func WithTombstones(ttl time.Duration) Option {
+	return func(lru *LRU) {
+		lru.opts.tombstoneTTL = ttl
+		lru.opts.tombstones = make(map[interface{}]tombstone)
+	}
+}
+
+// WithHLC stamps local writes with timestamps of
+// `clock` instead of plain counters, making entery
+// versions comparable across peers; see `Merge`.
+func WithHLC(clock *HLC) Option {
+	return func(lru *LRU) {
+		lru.opts.hlc = clock
+	}
+}
+
+// WithMerge sets the function resolving concurrent
+// replicated writes in `Merge`; defaults to `LWW`.
+func WithMerge(fn MergeFunc) Option {
+	return func(lru *LRU) {
+		lru.opts.merge = fn
+	}
+}
+
+// WithRefCounting makes values conforming to
+// `Releasable` reference counted: `Acquire`
+// increments the count and its release function
+// decrements it. Values leaving the cache are only
+// released once their count drops to zero, which
+// prevents use-after-evict of pooled buffers.
+// Releasable values must be comparable ( e.g.
+// pointers ).
+func WithRefCounting() Option {
+	return func(lru *LRU) {
+		lru.opts.refs = make(map[interface{}]*valueRef)
+	}
+}
+
+// WithBytePool makes streamed values allocate
+// their chunks from `pool` and recycle them once
+// evicted and no longer referenced. It enables
+// reference counting, since recycling buffers
+// still in use would corrupt readers.
+func WithBytePool(pool *BytePool) Option {
+	return func(lru *LRU) {
+		lru.opts.pool = pool
+		if lru.opts.refs == nil {
+			lru.opts.refs = make(map[interface{}]*valueRef)
+		}
+	}
+}
+
+// WithGhost tracks the last `size` keys evicted to
+// make room in a ghost list holding only their
+// hashes, and counts misses of such keys as
+// `GhostHits`: accesses a cache larger by `size`
+// enteries would have served. It is the signal
+// for manual and automatic sizing; see
+// `WithCapacityController`.
+func WithGhost(size int) Option {
+	return func(lru *LRU) {
+		if lru.opts.ghost != nil {
+			lru.opts.ghost.resize(size)
+			return
+		}
+		lru.opts.ghost = newGhost(size)
+	}
+}
+
+// WithMRC estimates the miss ratio curve online by
+// sampling `rate` of the key space ( e.g. 0.01 ) and
+// simulating caches at 0.5x, 1x, 2x and 4x capacity
+// over the sampled accesses. Estimates are reported
+// as `Stats.Curve`. Accuracy improves with rate at
+// the expense of memory and time per access.
+func WithMRC(rate float64) Option {
+	return func(lru *LRU) {
+		lru.opts.mrc = newMRC(math.Min(math.Max(rate, 0), 1), lru.capacity+1)
+	}
+}
+
+// WithValidation runs `Validate` after every
+// `every` writes and reports violations to `fn`.
+// It's meant for debug builds; each check walks
+// the entire cache while holding the lock.
+func WithValidation(every int, fn func(error)) Option {
+	return func(lru *LRU) {
+		lru.opts.validateEvery = every
+		lru.opts.onInvalid = fn
+	}
+}
+
+// WithOnEvict registers `fn` to be called whenever
+// an entery leaves the cache along with the reason.
+// It's called while the cache is locked and must
+// not call back into the cache.
+func WithOnEvict(fn func(key, value interface{}, reason EvictReason)) Option {
+	return func(lru *LRU) {
+		lru.opts.onEvict = fn
+	}
+}
+
+// WithOnBeforeEvict registers `fn` to be consulted
+// before an entery is evicted to make room; when it
+// returns `false` the eviction is vetoed and the next
+// least recently used entery is tried instead, e.g.
+// for enteries which are temporarily in use but can't
+// be pinned in advance. At most `maxVetoes` vetoes
+// are honored per eviction, after which the least
+// recently used entery is evicted regardless; when
+// `maxVetoes <= 0` holds true, it's set to
+// `defaultMAXVETOES`. The most recently used entery
+// is never offered as alternative victim. It's called
+// while the cache is locked and must not call back
+// into the cache.
+func WithOnBeforeEvict(fn func(key, value interface{}) bool, maxVetoes int) Option {
+	return func(lru *LRU) {
+		if maxVetoes <= 0 {
+			maxVetoes = defaultMAXVETOES
+		}
+		lru.opts.beforeEvict = fn
+		lru.opts.maxVetoes = maxVetoes
+	}
+}
+
+// WithLoadCancellation selects how read-through loads
+// react to cancellation of the caller that started them;
+// see `LoadCancel`. With `LoadDETACH`, loads are bounded
+// by `timeout` instead unless it's non-positive. By
+// default, `LoadCANCELALL` is used.
+func WithLoadCancellation(mode LoadCancel, timeout time.Duration) Option {
+	return func(lru *LRU) {
+		lru.opts.cancellation = mode
+		lru.opts.detachTimeout = timeout
+	}
+}
+
+// WithLatencyAdmission makes read-through loads
+// consult `fn` with the measured loader latency
+// before caching the result; values for which it
+// returns `false` are handed to the caller without
+// being cached, so that limited space is spent on
+// expensive to compute values. See also
+// `LatencyThreshold`.
+func WithLatencyAdmission(fn func(key interface{}, latency time.Duration) bool) Option {
+	return func(lru *LRU) {
+		lru.opts.admitLatency = fn
+	}
+}
+
+// WithCost assigns each entery a cost computed by
+// `fn` ( e.g. its size in bytes ) and evicts least
+// recently used enteries while total cost exceeds
+// `maxCost`, in addition to the entery capacity.
+// The most recently written entery is never evicted
+// to satisfy the limit. When `maxCost <= 0` holds
+// true, costs are tracked but not limited.
+func WithCost(fn func(key, value interface{}) int64, maxCost int64) Option {
+	return func(lru *LRU) {
+		lru.opts.costFn = fn
+		lru.opts.maxCost = maxCost
+	}
+}
+
+// WithLoadGuard makes read-through loads hand values
+// whose cost exceeds `maxCost`, or `share` of the
+// cache cost limit, to the caller without caching
+// them; this prevents a single huge value from
+// churning the cache. Either limit is disabled when
+// zero. It requires `WithCost`.
+func WithLoadGuard(maxCost int64, share float64) Option {
+	return func(lru *LRU) {
+		lru.opts.maxLoadCost = maxCost
+		lru.opts.maxLoadShare = share
+	}
+}
+
+// WithGrace makes read-through loads fall back to
+// the stale value of a key when reloading it fails,
+// as long as the entery expired less than `window`
+// ago ( or hasn't expired, e.g. with `GetFresh` ).
+// The load error is reported to `fn`, which may be
+// `nil`, instead of failing the `Get`.
+func WithGrace(window time.Duration, fn func(key interface{}, err error)) Option {
+	return func(lru *LRU) {
+		lru.opts.grace = window
+		lru.opts.onGrace = nil
+		if fn != nil {
+			lru.opts.onGrace = func(ctx context.Context, key interface{}, err error) {
+				fn(key, err)
+			}
+		}
+	}
+}
+
+// WithGraceCtx is same as `WithGrace` except that
+// `fn` also receives the context of the failed load
+// ( see `GetCtx` ).
+func WithGraceCtx(window time.Duration, fn func(ctx context.Context, key interface{}, err error)) Option {
+	return func(lru *LRU) {
+		lru.opts.grace = window
+		lru.opts.onGrace = fn
+	}
+}
+
+// WithErrorCaching caches loader errors per key so
+// that a persistently failing key doesn't hammer the
+// backend. After the n-th consecutive failure, loads
+// of the key fail fast for `base * 2^(n-1)`, capped
+// at `max`, with an error matching `ErrCachedError`
+// that unwraps to the original error.
+func WithErrorCaching(base, max time.Duration) Option {
+	return func(lru *LRU) {
+		lru.opts.errorBase = base
+		lru.opts.errorMax = max
+		lru.opts.failures = make(map[interface{}]*loadFailure)
+	}
+}
+
+// WithLoadLimits bounds the number of loader calls
+// running simultaneously as configured by `limits`,
+// protecting backends from miss storms ( e.g. after
+// a cold start ).
+func WithLoadLimits(limits LoadLimits) Option {
+	return func(lru *LRU) {
+		lru.opts.limits = newLoadLimiter(limits)
+	}
+}
+
+// WithWarmup starts the cache in `StateWARMING`,
+// during which read-through misses are handled as
+// configured by `w`, until it becomes ready; see
+// `State` and `MarkReady`.
+func WithWarmup(w Warmup) Option {
+	return func(lru *LRU) {
+		lru.opts.warmup = &warmupState{Warmup: w}
+	}
+}
@@ -0,0 +1,108 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// Entry is a detached copy of a cache entery
+// along with its policy state, e.g. as restored
+// from a snapshot or transferred to a standby.
+type Entry struct {
+	Key     interface{}
+	Value   interface{}
+	Count   int       // access counter
+	Expires time.Time // zero when entery never expires
+	Version uint64    // zero to let the cache assign one
+}
+
+// - MARK: LRU section.
+
+// LoadOrdered writes `entries`, ordered from least to
+// most recently used ( as written by `Export` ), so that
+// they end up in the given recency order with their
+// counters and versions preserved, instead of the state
+// naive `Set` calls would leave behind. Loaded enteries
+// become more recent than existing ones. Enteries that
+// have expired, or the least recent ones that would not
+// fit into the cache anyway, are skipped without being
+// written. It returns the number of loaded enteries.
+func (lru *LRU) LoadOrdered(entries []Entry) (n int, err error) {
+	lru.mu.Lock()
+	n, err = lru.loadOrdered(entries)
+	lru.debugValidate()
+	lru.mu.Unlock()
+	return n, err
+}
+
+// loadOrdered is the unprotected variant of
+// `LoadOrdered`. Note, this routine is not
+// protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) loadOrdered(entries []Entry) (n int, err error) {
+	var (
+		now   int64 = lru.now()
+		first int   = len(entries)
+		live  int
+		item  *LRUItem
+	)
+	// find the least recent entery that still fits
+	for first > 0 && live <= lru.capacity {
+		if first--; !entries[first].expired(now) {
+			live++
+		}
+	}
+	for _, e := range entries[first:] {
+		if e.expired(now) {
+			continue
+		}
+		if err = lru.checkType(e.Value); err != nil {
+			return n, err
+		}
+		if _, err = lru.set(e.Key, e.Value, e.expires()); err != nil {
+			return n, err
+		}
+		item = lru.read(e.Key)
+		item.Count = e.Count
+		if e.Version != 0 {
+			item.version = e.Version
+		}
+		n++
+	}
+	return n, nil
+}
+
+// - MARK: Entry section.
+
+// expires returns expiry of `e` in unix nanoseconds,
+// or zero when it never expires.
+func (e *Entry) expires() int64 {
+	if e.Expires.IsZero() {
+		return 0
+	}
+	return e.Expires.UnixNano()
+}
+
+// expired returns whether `e` has expired at `now`.
+func (e *Entry) expired(now int64) bool {
+	return !e.Expires.IsZero() && e.Expires.UnixNano() <= now
+}
@@ -0,0 +1,61 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRULoadOrdered(t *testing.T) {
+	var (
+		clock   *manualClock = &manualClock{time.Unix(100, 0)}
+		evicted int
+		lru     *LRU = NewLRU(3, WithClock(clock), WithOnEvict(func(key, value interface{}, reason EvictReason) {
+			evicted++
+		}))
+		entries []Entry = []Entry{
+			{Key: "cold", Value: 0},
+			{Key: "stale", Value: 1, Expires: time.Unix(50, 0)},
+			{Key: "a", Value: 2, Count: 7},
+			{Key: "b", Value: 3, Count: 9, Version: 42},
+			{Key: "c", Value: 4, Expires: time.Unix(200, 0)},
+		}
+		keys []interface{}
+	)
+	if n, err := lru.LoadOrdered(entries); n != 3 || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n, err)
+	}
+	if evicted != 0 || lru.Read("cold") != nil {
+		t.Fatal("assertion failed, expected skipped enteries not to be written.", evicted)
+	}
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Value.(*LRUItem).Key)
+	}
+	if len(keys) != 3 || keys[0] != "c" || keys[1] != "b" || keys[2] != "a" {
+		t.Fatal("assertion failed, expected preserved recency order.", keys)
+	}
+	if item := lru.read("b"); item.Count != 9 || item.version != 42 || lru.read("c").expires != time.Unix(200, 0).UnixNano() {
+		t.Fatal("assertion failed, expected preserved policy state.", item.Count, item.version)
+	}
+}
@@ -0,0 +1,128 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// Overflow describes an entery evicted to make
+// room, as handed to the sink configured with
+// `WithOverflow`.
+type Overflow struct {
+	Key     interface{}
+	Value   interface{}
+	Count   int       // accesses while cached
+	Expires time.Time // zero when entery never expires
+	Version uint64
+}
+
+// overflowState is the container for the sink
+// and the queue of asynchronous hand-offs.
+type overflowState struct {
+	sink     func(Overflow)
+	async    int
+	queue    []Overflow
+	draining bool
+}
+
+// - MARK: Alloc/Init section.
+
+// WithOverflow hands enteries evicted to make room
+// to `sink` instead of dropping them, turning eviction
+// into demotion ( e.g. to a slower L2 or to disk ).
+// When `async <= 0` holds true, `sink` is called
+// while the cache is locked and must not call back
+// into the cache. Otherwise, enteries are queued and
+// handed off by a background goroutine, which only
+// runs while the queue is non-empty; at most `async`
+// enteries are queued and further ones are dropped
+// and counted as `OverflowDropped`. See `OverflowTo`.
+func WithOverflow(sink func(Overflow), async int) Option {
+	return func(lru *LRU) {
+		lru.opts.overflow = &overflowState{sink: sink, async: async}
+	}
+}
+
+// OverflowTo returns a sink writing overflowing
+// enteries to `c`, preserving their expiry when it
+// supports ttls.
+func OverflowTo(c CacheInterface) func(Overflow) {
+	return func(o Overflow) {
+		if ts, ok := c.(ttlSetter); ok && !o.Expires.IsZero() {
+			if ttl := time.Until(o.Expires); ttl > 0 {
+				ts.SetWithTTL(o.Key, o.Value, ttl)
+			}
+			return
+		}
+		c.Set(o.Key, o.Value)
+	}
+}
+
+// - MARK: LRU section.
+
+// overflow hands `item` to the overflow sink. Note,
+// this routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) overflow(item *LRUItem) {
+	var (
+		of *overflowState = lru.opts.overflow
+		o  Overflow       = Overflow{Key: item.Key, Value: item.Value, Count: item.Count, Version: item.version}
+	)
+	if item.expires != 0 {
+		o.Expires = time.Unix(0, item.expires)
+	}
+	if of.async <= 0 {
+		of.sink(o)
+		return
+	}
+	if len(of.queue) >= of.async {
+		lru.opts.stats.OverflowDropped++
+		return
+	}
+	of.queue = append(of.queue, o)
+	if !of.draining {
+		of.draining = true
+		go lru.drainOverflow()
+	}
+}
+
+// drainOverflow hands queued enteries to the sink
+// until the queue is empty.
+func (lru *LRU) drainOverflow() {
+	var (
+		of    *overflowState = lru.opts.overflow
+		batch []Overflow
+	)
+	for {
+		lru.mu.Lock()
+		if batch = of.queue; len(batch) == 0 {
+			of.draining = false
+			lru.mu.Unlock()
+			return
+		}
+		of.queue = nil
+		lru.mu.Unlock()
+		for _, o := range batch {
+			of.sink(o)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUOverflow(t *testing.T) {
+	var (
+		l2  *LRU = NewLRU(8)
+		lru *LRU = NewLRU(2, WithOverflow(OverflowTo(l2), 0))
+	)
+	lru.Set("a", 1)
+	lru.SetWithTTL("b", 2, time.Hour)
+	lru.Get("a")
+	lru.Set("c", 3)
+	lru.Remove("a")
+	if l2.Len() != 1 || l2.Read("b") != 2 {
+		t.Fatal("assertion failed, expected demoted entery.", l2.Len())
+	}
+	if l2.read("b").expires == 0 {
+		t.Fatal("assertion failed, expected expiry to be preserved.")
+	}
+}
+
+func TestLRUOverflowAsync(t *testing.T) {
+	var (
+		got chan Overflow = make(chan Overflow, 8)
+		lru *LRU          = NewLRU(2, WithOverflow(func(o Overflow) { got <- o }, 1))
+	)
+	lru.mu.Lock()
+	lru.opts.overflow.draining = true
+	lru.mu.Unlock()
+	for i := 0; i < 4; i++ {
+		lru.Set(i, i)
+	}
+	if stats := lru.Stats(); stats.OverflowDropped != 1 {
+		t.Fatalf("assertion failed, expected equal with value(1) - got value(%d).", stats.OverflowDropped)
+	}
+	go lru.drainOverflow()
+	select {
+	case o := <-got:
+		if o.Key != 0 || o.Value != 0 || o.Count == 0 {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", o)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("assertion failed, expected asynchronous hand-off.")
+	}
+}
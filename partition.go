@@ -0,0 +1,137 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// PartitionedCache stores double-keyed enteries,
+// i.e. a resource key scoped by a partition key
+// ( e.g. the top-level site or tenant of a request ),
+// mirroring browser-style cache partitioning. The
+// same resource cached under different partitions
+// is stored separately; partitions never observe
+// each other's enteries. It shares the underlying
+// `LRU` and its capacity with other users, and its
+// partitions never collide with namespaces written
+// through `Set2`.
+type PartitionedCache struct {
+	lru *LRU
+}
+
+// partition is the namespace of enteries written
+// through `PartitionedCache`.
+type partition struct {
+	key interface{}
+}
+
+// - MARK: Alloc/Init section.
+
+// NewPartitionedCache allocates and initializes a
+// new `PartitionedCache` backed by `lru` and returns
+// a pointer to it.
+func NewPartitionedCache(lru *LRU) *PartitionedCache {
+	return &PartitionedCache{lru: lru}
+}
+
+// - MARK: PartitionedCache section.
+
+// Set writes `value` associated to `key`
+// within `part`.
+func (pc *PartitionedCache) Set(part interface{}, key interface{}, value interface{}) (isNew bool, err error) {
+	return pc.lru.Set2(partition{part}, key, value)
+}
+
+// Get fetches the value associated to `key`
+// within `part`.
+func (pc *PartitionedCache) Get(part interface{}, key interface{}) (value interface{}, err error) {
+	return pc.lru.Get2(partition{part}, key)
+}
+
+// Read reads the value associated to `key`
+// within `part` without touching cache counters.
+func (pc *PartitionedCache) Read(part interface{}, key interface{}) (value interface{}) {
+	return pc.lru.Read2(partition{part}, key)
+}
+
+// Remove removes the entery associated to `key`
+// within `part` and returns `true` when succesfull.
+func (pc *PartitionedCache) Remove(part interface{}, key interface{}) (ok bool) {
+	return pc.lru.Remove2(partition{part}, key)
+}
+
+// PurgePartition removes all enteries of `part`
+// and returns number of removed enteries.
+func (pc *PartitionedCache) PurgePartition(part interface{}) (n int) {
+	return pc.lru.PurgeNamespace(partition{part})
+}
+
+// PurgeResource removes enteries associated to
+// `key` in all partitions and returns number of
+// removed enteries, e.g. when a resource changes
+// at its origin.
+func (pc *PartitionedCache) PurgeResource(key interface{}) (n int) {
+	var (
+		elem *list.Element
+		ok   bool
+	)
+	pc.lru.mu.Lock()
+	for ns, ids := range pc.lru.opts.namespaces {
+		if _, isPart := ns.(partition); !isPart {
+			continue
+		}
+		if elem, ok = ids[key]; ok {
+			pc.lru.unlink(elem, EvictREMOVED)
+			n++
+		}
+	}
+	pc.lru.mu.Unlock()
+	return n
+}
+
+// Partitions returns partition keys holding
+// at least one entery.
+func (pc *PartitionedCache) Partitions() (parts []interface{}) {
+	pc.lru.mu.Lock()
+	for ns, _ := range pc.lru.opts.namespaces {
+		if p, ok := ns.(partition); ok {
+			parts = append(parts, p.key)
+		}
+	}
+	pc.lru.mu.Unlock()
+	return parts
+}
+
+// Len returns number of enteries within `part`.
+func (pc *PartitionedCache) Len(part interface{}) (l int) {
+	pc.lru.mu.Lock()
+	l = len(pc.lru.opts.namespaces[partition{part}])
+	pc.lru.mu.Unlock()
+	return l
+}
+
+// View returns a `CacheInterface` confined to
+// `part`, e.g. to hand a per-origin cache to code
+// unaware of partitioning.
+func (pc *PartitionedCache) View(part interface{}) CacheInterface {
+	return NamespaceMiddleware(partition{part})(pc.lru)
+}
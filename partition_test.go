@@ -0,0 +1,53 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestPartitionedCache(t *testing.T) {
+	var (
+		lru *LRU              = NewLRU(16)
+		pc  *PartitionedCache = NewPartitionedCache(lru)
+	)
+	pc.Set("a.example", "/logo.png", 1)
+	pc.Set("b.example", "/logo.png", 2)
+	pc.Set("a.example", "/app.js", 3)
+	lru.Set2("a.example", "/logo.png", 4)
+	if v, _ := pc.Get("a.example", "/logo.png"); v != 1 || pc.Read("b.example", "/logo.png") != 2 {
+		t.Fatal("assertion failed, expected isolated partitions.", v)
+	}
+	if pc.Len("a.example") != 2 || len(pc.Partitions()) != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", pc.Partitions())
+	}
+	if n := pc.PurgeResource("/logo.png"); n != 2 || lru.Read2("a.example", "/logo.png") != 4 {
+		t.Fatalf("assertion failed, expected equal with value(2) - got value(%d).", n)
+	}
+	if n := pc.PurgePartition("a.example"); n != 1 || lru.Len() != 1 {
+		t.Fatalf("assertion failed, expected equal with value(1) - got value(%d).", n)
+	}
+	view := pc.View("c.example")
+	view.Set("/x", 5)
+	if pc.Read("c.example", "/x") != 5 || view.Len() != 1 {
+		t.Fatal("assertion failed, expected view confined to partition.")
+	}
+}
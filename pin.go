@@ -0,0 +1,182 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// PurgeOption selects enteries spared by `PurgeWith`.
+type PurgeOption func(*purgeFilter)
+
+// purgeFilter is the container for conditions
+// of `PurgeWith`.
+type purgeFilter struct {
+	pinned bool
+	tags   map[interface{}]struct{}
+}
+
+// PreservePinned spares pinned enteries ( see `Pin` ).
+var PreservePinned PurgeOption = func(f *purgeFilter) {
+	f.pinned = true
+}
+
+// - MARK: Alloc/Init section.
+
+// PreserveTags spares enteries tagged with any of
+// `tags` ( see `Tag` ).
+func PreserveTags(tags ...interface{}) PurgeOption {
+	return func(f *purgeFilter) {
+		if f.tags == nil {
+			f.tags = make(map[interface{}]struct{}, len(tags))
+		}
+		for _, tag := range tags {
+			f.tags[tag] = struct{}{}
+		}
+	}
+}
+
+// - MARK: LRU section.
+
+// Pin exempts the entery associated to `key` from
+// eviction to make room and returns `true` when the
+// entery exists. A pin lasts until `Unpin` or until
+// the entery leaves the cache otherwise ( e.g. it's
+// removed or expires ). When all enteries are pinned,
+// the least recently used one is evicted regardless.
+func (lru *LRU) Pin(key interface{}) (ok bool) {
+	lru.mu.Lock()
+	if ok = lru.read(key) != nil; ok {
+		if lru.opts.pins == nil {
+			lru.opts.pins = make(map[interface{}]struct{})
+		}
+		lru.opts.pins[lru.canonical(key)] = struct{}{}
+	}
+	lru.mu.Unlock()
+	return ok
+}
+
+// Unpin removes the pin of `key` and returns `true`
+// when it was pinned.
+func (lru *LRU) Unpin(key interface{}) (ok bool) {
+	lru.mu.Lock()
+	key = lru.canonical(key)
+	if _, ok = lru.opts.pins[key]; ok {
+		delete(lru.opts.pins, key)
+	}
+	lru.mu.Unlock()
+	return ok
+}
+
+// Tag attaches `tags` to the entery associated to
+// `key`, e.g. to spare it from `PurgeExcept`, and
+// returns `true` when the entery exists. Tags are
+// dropped along with the entery.
+func (lru *LRU) Tag(key interface{}, tags ...interface{}) (ok bool) {
+	var (
+		set map[interface{}]struct{}
+	)
+	lru.mu.Lock()
+	if ok = lru.read(key) != nil; ok {
+		key = lru.canonical(key)
+		if lru.opts.tags == nil {
+			lru.opts.tags = make(map[interface{}]map[interface{}]struct{})
+		}
+		if set = lru.opts.tags[key]; set == nil {
+			set = make(map[interface{}]struct{}, len(tags))
+			lru.opts.tags[key] = set
+		}
+		for _, tag := range tags {
+			set[tag] = struct{}{}
+		}
+	}
+	lru.mu.Unlock()
+	return ok
+}
+
+// PurgeExcept removes all enteries except those
+// tagged with any of `tags` and returns the number
+// of removed enteries; see `PurgeWith`.
+func (lru *LRU) PurgeExcept(tags ...interface{}) int {
+	return lru.PurgeWith(PreserveTags(tags...))
+}
+
+// PurgeWith removes all enteries except those spared
+// by `opts` ( e.g. `PreservePinned` ) and returns the
+// number of removed enteries, so that operational
+// clears don't wipe must-keep enteries like feature
+// flags or keys. Unlike `Purge`, statistics and other
+// state of spared enteries are kept. Read-through
+// loads are throttled afterwards as with `Purge`.
+func (lru *LRU) PurgeWith(opts ...PurgeOption) (n int) {
+	var (
+		f    purgeFilter
+		next *list.Element
+		item *LRUItem
+	)
+	for _, opt := range opts {
+		opt(&f)
+	}
+	lru.mu.Lock()
+	for elem := lru.items.Front(); elem != nil; elem = next {
+		next = elem.Next()
+		if item = elem.Value.(*LRUItem); !lru.preserved(item.Key, &f) {
+			lru.unlink(elem, EvictPURGED)
+			n++
+		}
+	}
+	if lru.opts.readmit != nil {
+		lru.opts.readmit.reset()
+	}
+	if lru.opts.purge != nil {
+		lru.opts.purge.purged, lru.opts.purge.didPurge = lru.now(), true
+	}
+	lru.debugValidate()
+	lru.mu.Unlock()
+	return n
+}
+
+// preserved returns whether `key` is spared by `f`.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) preserved(key interface{}, f *purgeFilter) bool {
+	if _, ok := lru.opts.pins[key]; ok && f.pinned {
+		return true
+	}
+	for tag, _ := range lru.opts.tags[key] {
+		if _, ok := f.tags[tag]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// canonical returns the canonical key of `key`,
+// which may be an alias. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) canonical(key interface{}) interface{} {
+	if elem := lru.lookup[key]; elem != nil {
+		return elem.Value.(*LRUItem).Key
+	}
+	return key
+}
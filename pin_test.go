@@ -0,0 +1,73 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestLRUPin(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(3)
+	)
+	for i := 0; i < 3; i++ {
+		lru.Set(i, i)
+	}
+	if !lru.Pin(0) || lru.Pin("missing") {
+		t.Fatal("assertion failed, inconsistent pin.")
+	}
+	lru.Set(3, 3)
+	if lru.Read(0) != 0 || lru.Read(1) != nil {
+		t.Fatal("assertion failed, expected pinned entery to be spared.")
+	}
+	if !lru.Unpin(0) || lru.Unpin(0) {
+		t.Fatal("assertion failed, inconsistent unpin.")
+	}
+	lru.Set(4, 4)
+	if lru.Read(0) != nil {
+		t.Fatal("assertion failed, expected unpinned entery to be evicted.")
+	}
+}
+
+func TestLRUPurgeWith(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8)
+	)
+	for i := 0; i < 6; i++ {
+		lru.Set(i, i)
+	}
+	lru.Pin(0)
+	lru.Tag(1, "flags")
+	lru.Tag(2, "keys", "flags")
+	if n := lru.PurgeWith(PreservePinned, PreserveTags("keys")); n != 4 {
+		t.Fatalf("assertion failed, expected equal with value(4) - got value(%d).", n)
+	}
+	if lru.Read(0) != 0 || lru.Read(2) != 2 || lru.Read(1) != nil {
+		t.Fatal("assertion failed, expected preserved enteries.")
+	}
+	if n := lru.PurgeExcept("flags"); n != 1 || lru.Len() != 1 || lru.Read(2) != 2 {
+		t.Fatalf("assertion failed, expected equal with value(1) - got value(%d).", n)
+	}
+	lru.Remove(2)
+	if len(lru.opts.tags) != 0 || len(lru.opts.pins) != 0 {
+		t.Fatal("assertion failed, expected pins and tags to be released.")
+	}
+}
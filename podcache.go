@@ -0,0 +1,263 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"errors"
+	"math/bits"
+	"reflect"
+	"sync"
+)
+
+// Error messages
+var (
+	ELRUNOTPOD error = errors.New("cache(pod): value type contains pointers.")
+)
+
+// Slot flags of `PODCache`
+const (
+	podUSED uint8 = 1 << iota
+	podREF
+)
+
+// PODCache is a CLOCK cache ( see `ClockCache` )
+// mapping `uint64` keys to small fixed size values
+// without pointers ( e.g. `uint64` or `[16]byte` ).
+// Enteries are stored inline in a single open
+// addressed slot array, so that there are neither
+// per entery allocations nor pointers for the
+// garbage collector to scan, which suits ID to ID
+// mappings of hundreds of millions of enteries.
+// A slot costs the size of the key and value plus
+// a byte of flags ( and padding ); slots are sized
+// for a load factor of at most 3/4.
+type PODCache[V any] struct {
+	mu       sync.Mutex
+	slots    []podSlot[V]
+	mask     uint64
+	capacity int
+	count    int
+	hand     int
+}
+
+// podSlot is an entery of `PODCache`.
+type podSlot[V any] struct {
+	key   uint64
+	value V
+	flags uint8
+}
+
+// - MARK: Alloc/Init section.
+
+// NewPODCache allocates and initializes a new
+// `PODCache` holding up to `capacity` enteries and
+// returns a pointer to it. It fails with `ELRUNOTPOD`
+// unless `V` is free of pointers; strings, slices,
+// maps and interfaces are pointers in disguise.
+// Note, when `capacity <= 0` holds true, capacity
+// is set to `defaultCAPACITY`.
+func NewPODCache[V any](capacity int) (*PODCache[V], error) {
+	var (
+		size int
+	)
+	if !pointerFree(reflect.TypeFor[V]()) {
+		return nil, ELRUNOTPOD
+	}
+	if capacity <= 0 {
+		capacity = defaultCAPACITY
+	}
+	size = 1 << bits.Len(uint(capacity+capacity/3))
+	return &PODCache[V]{
+		slots:    make([]podSlot[V], size),
+		mask:     uint64(size - 1),
+		capacity: capacity,
+	}, nil
+}
+
+// - MARK: PODCache section.
+
+// Set writes k/v pair in the cache and evicts
+// an entery when needed. It sets `isNew` to `true`
+// when the given k/v pair are allocated.
+func (pc *PODCache[V]) Set(key uint64, value V) (isNew bool) {
+	var (
+		idx int
+		ok  bool
+	)
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if idx, ok = pc.find(key); ok {
+		pc.slots[idx].value = value
+		pc.slots[idx].flags |= podREF
+		return false
+	}
+	if pc.count >= pc.capacity {
+		pc.evict()
+		idx, _ = pc.find(key)
+	}
+	pc.slots[idx] = podSlot[V]{key: key, value: value, flags: podUSED}
+	pc.count++
+	return true
+}
+
+// Get fetches `key` from cache and returns its
+// value along with a boolean indicating whether
+// it was found.
+func (pc *PODCache[V]) Get(key uint64) (value V, ok bool) {
+	var (
+		idx int
+	)
+	pc.mu.Lock()
+	if idx, ok = pc.find(key); ok {
+		pc.slots[idx].flags |= podREF
+		value = pc.slots[idx].value
+	}
+	pc.mu.Unlock()
+	return value, ok
+}
+
+// Read only reads the given item with `key`
+// without marking it as referenced.
+func (pc *PODCache[V]) Read(key uint64) (value V, ok bool) {
+	var (
+		idx int
+	)
+	pc.mu.Lock()
+	if idx, ok = pc.find(key); ok {
+		value = pc.slots[idx].value
+	}
+	pc.mu.Unlock()
+	return value, ok
+}
+
+// Remove removes the given item with `key` from
+// cache and returns `true` when succesfull.
+func (pc *PODCache[V]) Remove(key uint64) (ok bool) {
+	var (
+		idx int
+	)
+	pc.mu.Lock()
+	if idx, ok = pc.find(key); ok {
+		pc.delete(idx)
+	}
+	pc.mu.Unlock()
+	return ok
+}
+
+// Purge removes all enteries.
+func (pc *PODCache[V]) Purge() {
+	pc.mu.Lock()
+	clear(pc.slots)
+	pc.count, pc.hand = 0, 0
+	pc.mu.Unlock()
+}
+
+// Len returns number of items in cache.
+func (pc *PODCache[V]) Len() (l int) {
+	pc.mu.Lock()
+	l = pc.count
+	pc.mu.Unlock()
+	return l
+}
+
+// find returns the slot holding `key` along with
+// `true`, or the empty slot `key` would be written
+// to along with `false`. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (pc *PODCache[V]) find(key uint64) (idx int, ok bool) {
+	for i := mix64(key) & pc.mask; ; i = (i + 1) & pc.mask {
+		if pc.slots[i].flags&podUSED == 0 {
+			return int(i), false
+		}
+		if pc.slots[i].key == key {
+			return int(i), true
+		}
+	}
+}
+
+// evict sweeps the hand over slots, clearing
+// reference bits, and evicts the first entery
+// not referenced since its last sweep. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (pc *PODCache[V]) evict() {
+	var (
+		slot *podSlot[V]
+	)
+	for ; ; pc.hand = (pc.hand + 1) & int(pc.mask) {
+		slot = &pc.slots[pc.hand]
+		if slot.flags&podUSED == 0 {
+			continue
+		}
+		if slot.flags&podREF != 0 {
+			slot.flags &^= podREF
+			continue
+		}
+		pc.delete(pc.hand)
+		return
+	}
+}
+
+// delete empties slot `idx`, shifting later slots
+// of the probe sequence back so that lookups
+// need no tombstones. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (pc *PODCache[V]) delete(idx int) {
+	var (
+		i    uint64 = uint64(idx)
+		home uint64
+	)
+	for j := (i + 1) & pc.mask; pc.slots[j].flags&podUSED != 0; j = (j + 1) & pc.mask {
+		home = mix64(pc.slots[j].key) & pc.mask
+		// move `j` unless its home lies cyclically in (i, j]
+		if (j-home)&pc.mask >= (j-i)&pc.mask {
+			pc.slots[i] = pc.slots[j]
+			i = j
+		}
+	}
+	pc.slots[i] = podSlot[V]{}
+	pc.count--
+}
+
+// pointerFree returns whether values of `t` hold
+// no pointers.
+func pointerFree(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return t.Len() == 0 || pointerFree(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !pointerFree(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPODCache(t *testing.T) {
+	var (
+		pc    *PODCache[[16]byte]
+		err   error
+		model map[uint64][16]byte = make(map[uint64][16]byte)
+		rnd   *rand.Rand          = rand.New(rand.NewSource(1))
+	)
+	if _, err = NewPODCache[string](8); err != ELRUNOTPOD {
+		t.Fatal("assertion failed, expected pointers to be rejected.", err)
+	}
+	if pc, err = NewPODCache[[16]byte](64); err != nil {
+		t.Fatal(err)
+	}
+	if !pc.Set(0, [16]byte{1}) || pc.Set(0, [16]byte{2}) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if value, ok := pc.Get(0); !ok || value[0] != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	// cached values must match the last write
+	// despite evictions and backward shifts
+	for i := 0; i < 10000; i++ {
+		key := uint64(rnd.Intn(256))
+		switch rnd.Intn(3) {
+		case 0:
+			value := [16]byte{byte(i), byte(i >> 8)}
+			pc.Set(key, value)
+			model[key] = value
+		case 1:
+			pc.Remove(key)
+			delete(model, key)
+		default:
+			if value, ok := pc.Get(key); ok && value != model[key] {
+				t.Fatalf("assertion failed, inconsistent value of key(%d).", key)
+			}
+		}
+		if pc.Len() > 64 {
+			t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 64, pc.Len())
+		}
+	}
+	n := 0
+	for key, value := range model {
+		if v, ok := pc.Read(key); ok {
+			if v != value {
+				t.Fatalf("assertion failed, inconsistent value of key(%d).", key)
+			}
+			n++
+		}
+	}
+	if n != pc.Len() {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", pc.Len(), n)
+	}
+	pc.Purge()
+	if _, ok := pc.Get(0); ok || pc.Len() != 0 {
+		t.Fatal("assertion failed, expected empty cache.")
+	}
+}
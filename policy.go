@@ -0,0 +1,88 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// Defaults
+const (
+	defaultMAXREPLAY = 15
+)
+
+// - MARK: LRU section.
+
+// SwitchAdmitter replaces the admission policy of
+// the live cache ( see `WithAdmitter` ) without
+// flushing it, e.g. to switch from plain LRU to
+// TinyLFU during an experiment; `nil` switches
+// back to plain LRU right away. `a` is warmed up in
+// the background with the cached keys ( and their
+// frequencies, see `WithKeyStats` ) so that cached
+// entries aren't displaced by the first new keys,
+// and swapped in once warm; accesses in the
+// meantime are only seen by the current policy.
+// Replayed frequencies are capped at
+// `defaultMAXREPLAY` to keep warming up cheap.
+// The returned channel is closed once the switch
+// took effect, or was superseded by a later one.
+func (lru *LRU) SwitchAdmitter(a Admitter) (done <-chan struct{}) {
+	var (
+		ch     chan struct{} = make(chan struct{})
+		hashes []uint64
+		counts []int
+		gen    uint64
+		item   *LRUItem
+	)
+	lru.mu.Lock()
+	lru.opts.admitterGen++
+	gen = lru.opts.admitterGen
+	if a == nil {
+		lru.opts.admitter = nil
+		lru.mu.Unlock()
+		close(ch)
+		return ch
+	}
+	hashes, counts = make([]uint64, 0, lru.items.Len()), make([]int, 0, lru.items.Len())
+	for elem := lru.items.Back(); elem != nil; elem = elem.Prev() {
+		item = elem.Value.(*LRUItem)
+		h, n := hashKey(item.Key), 1
+		if lru.opts.keyStats != nil {
+			n, _ = lru.opts.keyStats.estimate(h)
+			n = min(max(n, 1), defaultMAXREPLAY)
+		}
+		hashes, counts = append(hashes, h), append(counts, n)
+	}
+	lru.mu.Unlock()
+	go func() {
+		for i, h := range hashes {
+			for j := 0; j < counts[i]; j++ {
+				a.Record(h)
+			}
+		}
+		lru.mu.Lock()
+		if lru.opts.admitterGen == gen {
+			lru.opts.admitter = a
+		}
+		lru.mu.Unlock()
+		close(ch)
+	}()
+	return ch
+}
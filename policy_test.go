@@ -0,0 +1,69 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestLRUSwitchAdmitter(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(4)
+	)
+	for i := 0; i < 4; i++ {
+		lru.Set(i, i)
+	}
+	<-lru.SwitchAdmitter(NewTinyLFU(64))
+	if _, err := lru.Set("new", 1); err != ELRUNOTADMITTED || lru.Len() != 4 {
+		t.Fatal("assertion failed, expected warmed up admitter to protect cached enteries.", err)
+	}
+	// superseded switches don't take effect
+	first := lru.SwitchAdmitter(NewTinyLFU(64))
+	<-lru.SwitchAdmitter(nil)
+	<-first
+	if _, err := lru.Set("new", 1); err != nil || lru.Read("new") != 1 {
+		t.Fatal("assertion failed, expected plain LRU to admit new keys.", err)
+	}
+}
+
+// countingAdmitter admits everything and counts
+// recorded hashes.
+type countingAdmitter struct {
+	records map[uint64]int
+}
+
+func (ca *countingAdmitter) Record(hash uint64)                  { ca.records[hash]++ }
+func (ca *countingAdmitter) Admit(candidate, victim uint64) bool { return true }
+
+func TestLRUSwitchAdmitterReplay(t *testing.T) {
+	var (
+		lru *LRU              = NewLRU(4, WithKeyStats(0))
+		ca  *countingAdmitter = &countingAdmitter{records: make(map[uint64]int)}
+	)
+	lru.Set("hot", 1)
+	for i := 0; i < 100; i++ {
+		lru.Get("hot")
+	}
+	<-lru.SwitchAdmitter(ca)
+	if n := ca.records[hashKey("hot")]; n != defaultMAXREPLAY {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", defaultMAXREPLAY, n)
+	}
+}
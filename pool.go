@@ -0,0 +1,129 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// Size classes of `BytePool`
+const (
+	poolMINSHIFT = 9  // 512 bytes
+	poolMAXSHIFT = 20 // 1 MiB
+)
+
+// BytePool is a size-classed pool of byte slices
+// with power of two capacities from 512 bytes to
+// 1 MiB. It is safe for concurrent use.
+type BytePool struct {
+	classes [poolMAXSHIFT - poolMINSHIFT + 1]sync.Pool
+	gets    uint64
+	hits    uint64
+	puts    uint64
+	drops   uint64
+}
+
+// PoolStats are counters of a `BytePool`. `Drops`
+// counts returned slices that didn't belong to a
+// size class and were left to the GC.
+type PoolStats struct {
+	Gets, Hits, Puts, Drops uint64
+}
+
+// - MARK: Alloc/Init section.
+
+// NewBytePool allocates and initializes a new
+// `BytePool` struct and returns a pointer to it.
+func NewBytePool() *BytePool {
+	return &BytePool{}
+}
+
+// - MARK: BytePool section.
+
+// Get returns a slice of length `n`, recycled when
+// possible. Slices larger than the biggest class
+// are allocated and never pooled.
+func (bp *BytePool) Get(n int) []byte {
+	var (
+		class int = poolClass(n)
+	)
+	atomic.AddUint64(&bp.gets, 1)
+	if class < 0 {
+		return make([]byte, n)
+	}
+	if b, ok := bp.classes[class].Get().([]byte); ok {
+		atomic.AddUint64(&bp.hits, 1)
+		return b[:n]
+	}
+	return make([]byte, n, 1<<(class+poolMINSHIFT))
+}
+
+// Put recycles `b`. The caller must not use `b`
+// afterwards.
+func (bp *BytePool) Put(b []byte) {
+	var (
+		class int = poolClass(cap(b))
+	)
+	if class < 0 || cap(b) != 1<<(class+poolMINSHIFT) {
+		atomic.AddUint64(&bp.drops, 1)
+		return
+	}
+	atomic.AddUint64(&bp.puts, 1)
+	bp.classes[class].Put(b[:0])
+}
+
+// Stats returns a snapshot of pool counters.
+func (bp *BytePool) Stats() PoolStats {
+	return PoolStats{
+		Gets:  atomic.LoadUint64(&bp.gets),
+		Hits:  atomic.LoadUint64(&bp.hits),
+		Puts:  atomic.LoadUint64(&bp.puts),
+		Drops: atomic.LoadUint64(&bp.drops),
+	}
+}
+
+// HitRatio returns the share of gets served by
+// recycled slices.
+func (ps PoolStats) HitRatio() float64 {
+	if ps.Gets == 0 {
+		return 0
+	}
+	return float64(ps.Hits) / float64(ps.Gets)
+}
+
+// poolClass returns the index of the smallest class
+// holding `n` bytes, or -1 when there is none.
+func poolClass(n int) int {
+	var (
+		shift int = poolMINSHIFT
+	)
+	if n > 1<<poolMAXSHIFT {
+		return -1
+	}
+	if n > 1<<poolMINSHIFT {
+		shift = bits.Len(uint(n - 1))
+	}
+	return shift - poolMINSHIFT
+}
@@ -0,0 +1,89 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestBytePool(t *testing.T) {
+	var (
+		bp *BytePool = NewBytePool()
+		b  []byte
+	)
+	if b = bp.Get(600); len(b) != 600 || cap(b) != 1024 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", len(b), cap(b))
+	}
+	bp.Put(b)
+	bp.Put(make([]byte, 700))
+	if b = bp.Get(1024); len(b) != 1024 || cap(b) != 1024 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", len(b), cap(b))
+	}
+	if b = bp.Get(2 << 20); len(b) != 2<<20 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", len(b))
+	}
+	stats := bp.Stats()
+	if stats.Gets != 3 || stats.Puts != 1 || stats.Drops != 1 || stats.Hits > 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats)
+	}
+	for i, c := range map[int]int{0: 0, 1: 0, 512: 0, 513: 1, 1 << 20: 11, 1<<20 + 1: -1} {
+		if poolClass(i) != c {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", i, poolClass(i), c)
+		}
+	}
+}
+
+func TestLRUBytePoolRecycling(t *testing.T) {
+	var (
+		bp      *BytePool = NewBytePool()
+		lru     *LRU      = NewLRU(2, WithBytePool(bp))
+		payload []byte    = bytes.Repeat([]byte("x"), streamCHUNK+100)
+	)
+	lru.SetReader("a", bytes.NewReader(payload), -1)
+	cv := lru.Read("a").(*ChunkedValue)
+	if len(cv.chunks) != 2 || cap(cv.chunks[1]) != 512 {
+		t.Fatal("assertion failed, expected pooled tail.", len(cv.chunks))
+	}
+	rc, err := lru.GetReader("a")
+	if err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	// eviction while reading defers recycling
+	lru.Remove("a")
+	if got, err := io.ReadAll(rc); err != nil || !bytes.Equal(got, payload) {
+		t.Fatal("assertion failed, expected intact payload.", len(got), err)
+	}
+	if bp.Stats().Puts != 1 {
+		t.Fatal("assertion failed, expected only the oversized tail chunk recycled.", bp.Stats())
+	}
+	rc.Close()
+	if bp.Stats().Puts != 3 {
+		t.Fatal("assertion failed, expected chunks recycled on close.", bp.Stats())
+	}
+	// safety valve against reads of recycled memory
+	if _, err := cv.NewReader().Read(make([]byte, 1)); err != ELRURELEASED {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+}
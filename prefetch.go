@@ -0,0 +1,223 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Defaults
+const (
+	defaultPREFETCHES = 4
+	defaultFANOUT     = 8
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ Prefetcher = (PrefetcherFunc)(nil)
+	_ Prefetcher = (*MarkovPrefetcher)(nil)
+)
+
+// Prefetcher is protocol definition for access
+// pattern based prefetchers ( see `WithPrefetcher` ).
+type Prefetcher interface {
+	// Observe receives every key looked up by
+	// `Get` along with whether it was a hit, and
+	// returns keys predicted to be looked up next.
+	Observe(key interface{}, hit bool) (next []interface{})
+}
+
+// PrefetcherFunc is an adapter to allow ordinary
+// functions to be used as `Prefetcher`.
+type PrefetcherFunc func(key interface{}, hit bool) []interface{}
+
+// MarkovPrefetcher is a first order Markov chain
+// `Prefetcher` predicting the successors most
+// frequently observed after a key.
+type MarkovPrefetcher struct {
+	mu      sync.Mutex
+	prev    interface{}
+	primed  bool
+	next    map[interface{}]map[interface{}]int
+	maxKeys int
+	fanout  int
+	predict int
+}
+
+// prefetchState is the container for state of
+// `WithPrefetcher`.
+type prefetchState struct {
+	mu  sync.Mutex // serializes `Observe`
+	p   Prefetcher
+	sem chan struct{}
+}
+
+// - MARK: Alloc/Init section.
+
+// WithPrefetcher feeds the access stream of `Get`
+// into `p` and prefetches the keys it predicts
+// through the loader in background, running at
+// most `workers` prefetches at a time; further
+// predictions are dropped while all workers are
+// busy. Prefetches are subject to load limits and
+// counted as `Prefetches`. `Observe` is never called
+// concurrently. Note, prefetching requires
+// read-through mode ( see `WithLoader` ); when
+// `workers <= 0` holds true, it's set to
+// `defaultPREFETCHES` ( by default 4 ).
+func WithPrefetcher(p Prefetcher, workers int) Option {
+	return func(lru *LRU) {
+		if workers <= 0 {
+			workers = defaultPREFETCHES
+		}
+		lru.opts.prefetch = &prefetchState{p: p, sem: make(chan struct{}, workers)}
+	}
+}
+
+// NewMarkovPrefetcher allocates and initializes a
+// new `MarkovPrefetcher` predicting up to `predict`
+// successors per key and returns a pointer to it.
+// It tracks successors of at most `maxKeys` keys;
+// further keys aren't learned.
+func NewMarkovPrefetcher(maxKeys int, predict int) *MarkovPrefetcher {
+	return &MarkovPrefetcher{
+		next:    make(map[interface{}]map[interface{}]int),
+		maxKeys: maxKeys,
+		fanout:  defaultFANOUT,
+		predict: predict,
+	}
+}
+
+// - MARK: PrefetcherFunc section.
+
+// Observe conforms to `Prefetcher` and calls `fn`.
+func (fn PrefetcherFunc) Observe(key interface{}, hit bool) []interface{} {
+	return fn(key, hit)
+}
+
+// - MARK: MarkovPrefetcher section.
+
+// Observe conforms to `Prefetcher`. It records the
+// transition from the previous key to `key` and
+// returns the most frequent successors of `key`.
+func (mp *MarkovPrefetcher) Observe(key interface{}, hit bool) (next []interface{}) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	if mp.primed && mp.prev != key {
+		mp.learn(mp.prev, key)
+	}
+	mp.prev, mp.primed = key, true
+	return mp.successors(key)
+}
+
+// learn counts the transition `from` -> `to`. When
+// `from` has `fanout` successors already, the least
+// frequent one is replaced.
+func (mp *MarkovPrefetcher) learn(from, to interface{}) {
+	var (
+		succ  map[interface{}]int
+		ok    bool
+		least interface{}
+		min   int
+	)
+	if succ, ok = mp.next[from]; !ok {
+		if len(mp.next) >= mp.maxKeys {
+			return
+		}
+		succ = make(map[interface{}]int)
+		mp.next[from] = succ
+	}
+	if _, ok = succ[to]; !ok && len(succ) >= mp.fanout {
+		for k, n := range succ {
+			if least == nil || n < min {
+				least, min = k, n
+			}
+		}
+		delete(succ, least)
+	}
+	succ[to]++
+}
+
+// successors returns up to `predict` most frequent
+// successors of `key`.
+func (mp *MarkovPrefetcher) successors(key interface{}) (next []interface{}) {
+	var (
+		succ map[interface{}]int = mp.next[key]
+	)
+	if len(succ) == 0 || mp.predict <= 0 {
+		return nil
+	}
+	next = make([]interface{}, 0, len(succ))
+	for k, _ := range succ {
+		next = append(next, k)
+	}
+	sort.SliceStable(next, func(i, j int) bool { return succ[next[i]] > succ[next[j]] })
+	if len(next) > mp.predict {
+		next = next[:mp.predict]
+	}
+	return next
+}
+
+// - MARK: LRU section.
+
+// prefetchAfter hands the lookup of `key` to the
+// configured prefetcher and schedules prefetches of
+// the predicted keys. Note, this routine must be
+// called without lock held.
+func (lru *LRU) prefetchAfter(key interface{}, hit bool) {
+	var (
+		ps   *prefetchState = lru.opts.prefetch
+		next []interface{}
+	)
+	ps.mu.Lock()
+	next = ps.p.Observe(key, hit)
+	ps.mu.Unlock()
+	for _, k := range next {
+		select {
+		case ps.sem <- struct{}{}:
+			go func(k interface{}) {
+				defer func() { <-ps.sem }()
+				lru.prefetch(k)
+			}(k)
+		default:
+			lru.mu.Lock()
+			lru.opts.stats.PrefetchDropped++
+			lru.mu.Unlock()
+		}
+	}
+}
+
+// prefetch loads `key` unless it's cached already
+// or being loaded.
+func (lru *LRU) prefetch(key interface{}) {
+	lru.mu.Lock()
+	if _, loading := lru.opts.loads[key]; loading || lru.read(key) != nil {
+		lru.mu.Unlock()
+		return
+	}
+	lru.opts.stats.Prefetches++
+	// load releases the lock
+	lru.load(context.Background(), key, false)
+}
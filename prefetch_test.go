@@ -0,0 +1,78 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarkovPrefetcher(t *testing.T) {
+	var (
+		mp *MarkovPrefetcher = NewMarkovPrefetcher(16, 1)
+	)
+	for _, key := range []string{"a", "b", "a", "c", "a", "b"} {
+		mp.Observe(key, false)
+	}
+	if next := mp.Observe("a", false); len(next) != 1 || next[0] != "b" {
+		t.Fatal("assertion failed, expected equal with value(b).", next)
+	}
+	if next := mp.Observe("z", false); len(next) != 0 {
+		t.Fatal("assertion failed, expected no predictions for unknown key.", next)
+	}
+}
+
+func TestLRUPrefetcher(t *testing.T) {
+	var (
+		loads  = make(chan interface{}, 16)
+		loader = LoaderFunc(func(key interface{}) (interface{}, error) {
+			loads <- key
+			return key, nil
+		})
+		lru *LRU = NewLRU(8, WithLoader(loader), WithPrefetcher(PrefetcherFunc(func(key interface{}, hit bool) []interface{} {
+			if key == "a" {
+				return []interface{}{"b"}
+			}
+			return nil
+		}), 1))
+	)
+	if v, err := lru.Get("a"); v != "a" || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+	}
+	for deadline := time.Now().Add(time.Second); lru.Read("b") == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("assertion failed, expected predicted key to be prefetched.")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if <-loads != "a" || <-loads != "b" || lru.Stats().Prefetches != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	// cached predictions aren't loaded again
+	lru.Get("a")
+	lru.Get("b")
+	time.Sleep(5 * time.Millisecond)
+	if len(loads) != 0 || lru.Stats().Prefetches != 1 {
+		t.Fatal("assertion failed, expected no further loads.", len(loads))
+	}
+}
@@ -0,0 +1,146 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults
+const (
+	defaultREBUILDLOADS = 4
+)
+
+// PurgePolicy configures `WithPurgePolicy`.
+type PurgePolicy struct {
+	Cooldown time.Duration // throttling window after a purge
+	Loads    int           // concurrent read-through loads while cooling down ( default 1 )
+	Reject   bool          // fail excess loads with `ELRULOADLIMIT` instead of queuing
+	Rebuild  int           // concurrent loads of `PurgeAndRebuild` ( default 4 )
+}
+
+// purgeState tracks the cooldown after purges.
+type purgeState struct {
+	PurgePolicy
+	purged   int64 // time of the last purge
+	didPurge bool
+	limiter  *loadLimiter
+}
+
+// - MARK: Alloc/Init section.
+
+// WithPurgePolicy makes the cache enter a "just
+// purged" state for `p.Cooldown` after each `Purge`,
+// during which read-through loads are throttled as
+// configured by `p`, so that a purge doesn't stampede
+// the backend with reloads of the whole working set.
+func WithPurgePolicy(p PurgePolicy) Option {
+	return func(lru *LRU) {
+		if p.Loads <= 0 {
+			p.Loads = 1
+		}
+		if p.Rebuild <= 0 {
+			p.Rebuild = defaultREBUILDLOADS
+		}
+		lru.opts.purge = &purgeState{
+			PurgePolicy: p,
+			limiter:     newLoadLimiter(LoadLimits{Global: p.Loads, Reject: p.Reject}),
+		}
+	}
+}
+
+// - MARK: purgeState section.
+
+// cooling returns whether a purge happened less
+// than the cooldown before `now`.
+func (ps *purgeState) cooling(now int64) bool {
+	return ps.didPurge && now-ps.purged < int64(ps.Cooldown)
+}
+
+// - MARK: LRU section.
+
+// PurgeAndRebuild purges the cache and immediately
+// starts reloading `keys` through the loader in the
+// background, with the concurrency configured by
+// `WithPurgePolicy` ( 4 by default ). Rebuild loads
+// bypass the purge cooldown and load limits, while
+// other misses are throttled as usual. Keys written
+// in the meantime aren't reloaded. It returns a
+// function waiting for the rebuild to finish and
+// returning the number of loaded keys along with the
+// first load error; without loader nothing is loaded.
+func (lru *LRU) PurgeAndRebuild(keys []interface{}) (wait func() (int, error)) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		sem      chan struct{}
+		loaded   int
+		firstErr error
+		workers  int = defaultREBUILDLOADS
+	)
+	lru.Purge()
+	lru.mu.Lock()
+	if lru.opts.purge != nil {
+		workers = lru.opts.purge.Rebuild
+	}
+	if lru.opts.loader == nil {
+		keys = nil
+	}
+	lru.mu.Unlock()
+	sem = make(chan struct{}, workers)
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key interface{}) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			value, err := lru.rebuild(key)
+			mu.Lock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			} else if err == nil && value != nil {
+				loaded++
+			}
+			mu.Unlock()
+		}(key)
+	}
+	return func() (int, error) {
+		wg.Wait()
+		mu.Lock()
+		defer mu.Unlock()
+		return loaded, firstErr
+	}
+}
+
+// rebuild loads `key` unless it's cached already.
+func (lru *LRU) rebuild(key interface{}) (value interface{}, err error) {
+	lru.mu.Lock()
+	if lru.read(key) != nil {
+		lru.mu.Unlock()
+		return nil, nil
+	}
+	// load releases the lock
+	return lru.load(context.Background(), key, true)
+}
@@ -0,0 +1,84 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUPurgeAndRebuild(t *testing.T) {
+	var (
+		active, peak int32
+		lru          *LRU = NewLRU(16, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			n := atomic.AddInt32(&active, 1)
+			for p := atomic.LoadInt32(&peak); n > p && !atomic.CompareAndSwapInt32(&peak, p, n); p = atomic.LoadInt32(&peak) {
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			return key, nil
+		})), WithPurgePolicy(PurgePolicy{Rebuild: 2}))
+		keys []interface{}
+	)
+	for i := 0; i < 8; i++ {
+		lru.Set(i, -1)
+		keys = append(keys, i)
+	}
+	n, err := lru.PurgeAndRebuild(keys)()
+	if n != 8 || err != nil || lru.Read(3) != 3 {
+		t.Fatal("assertion failed, expected rebuilt enteries.", n, err)
+	}
+	if peak > 2 {
+		t.Fatalf("assertion failed, expected bounded concurrency - got value(%d).", peak)
+	}
+}
+
+func TestLRUPurgeCooldown(t *testing.T) {
+	var (
+		clock        *manualClock = &manualClock{time.Unix(0, 0)}
+		active, peak int32
+		lru          *LRU = NewLRU(16, WithClock(clock), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			n := atomic.AddInt32(&active, 1)
+			for p := atomic.LoadInt32(&peak); n > p && !atomic.CompareAndSwapInt32(&peak, p, n); p = atomic.LoadInt32(&peak) {
+			}
+			time.Sleep(2 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			return key, nil
+		})), WithPurgePolicy(PurgePolicy{Cooldown: time.Minute, Loads: 1}))
+		wg sync.WaitGroup
+	)
+	lru.Purge()
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lru.Get(i)
+		}(i)
+	}
+	wg.Wait()
+	if peak != 1 || lru.Len() != 6 {
+		t.Fatalf("assertion failed, expected throttled loads - got value(%d).", peak)
+	}
+}
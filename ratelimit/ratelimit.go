@@ -0,0 +1,173 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+// Package ratelimit implements per-key token
+// bucket rate limiting on top of `cache.LRU`,
+// which bounds the memory held by limiter state.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mitghi/cache"
+)
+
+// Option configures a `Limiter`.
+type Option func(*Limiter)
+
+// Limiter keeps one token bucket per key. Buckets
+// refill at `rate` tokens per second up to `burst`
+// tokens; idle buckets expire once they would be
+// full again, so a fresh bucket is equivalent.
+type Limiter struct {
+	mu    sync.Mutex
+	lru   *cache.LRU
+	rate  float64
+	burst float64
+	clock cache.Clock
+}
+
+// Reservation tells how long to wait before acting
+// on a reserved token. `OK` is false when the
+// limiter can never grant a token.
+type Reservation struct {
+	OK    bool
+	Delay time.Duration
+}
+
+// bucket is the cached state of a token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// Now conforms to `cache.Clock`.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// - MARK: Alloc/Init section.
+
+// WithClock sets the time source of the limiter.
+// It should match the clock of the backing cache.
+func WithClock(clock cache.Clock) Option {
+	return func(l *Limiter) {
+		l.clock = clock
+	}
+}
+
+// New allocates and initializes a new `Limiter`
+// struct keeping buckets in `lru` and returns a
+// pointer to it.
+func New(lru *cache.LRU, rate float64, burst int, opts ...Option) (l *Limiter) {
+	l = &Limiter{lru: lru, rate: rate, burst: float64(burst), clock: systemClock{}}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// - MARK: Limiter section.
+
+// Allow takes a token of `key` and reports whether
+// one was available.
+func (l *Limiter) Allow(key interface{}) (ok bool) {
+	var (
+		b   bucket
+		now time.Time
+	)
+	l.mu.Lock()
+	now = l.clock.Now()
+	if b = l.bucket(key, now); b.tokens >= 1 {
+		b.tokens--
+		ok = true
+	}
+	l.store(key, b)
+	l.mu.Unlock()
+	return ok
+}
+
+// Reserve takes a token of `key`, possibly going
+// into debt, and returns how long the caller must
+// wait before acting on it.
+func (l *Limiter) Reserve(key interface{}) (r Reservation) {
+	var (
+		b   bucket
+		now time.Time
+	)
+	if l.burst < 1 || l.rate <= 0 {
+		return Reservation{}
+	}
+	l.mu.Lock()
+	now = l.clock.Now()
+	b = l.bucket(key, now)
+	b.tokens--
+	if b.tokens < 0 {
+		r.Delay = time.Duration(-b.tokens / l.rate * float64(time.Second))
+	}
+	r.OK = true
+	l.store(key, b)
+	l.mu.Unlock()
+	return r
+}
+
+// bucket returns the bucket of `key` refilled up
+// to `now`. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (l *Limiter) bucket(key interface{}, now time.Time) (b bucket) {
+	var (
+		cached interface{}
+		ok     bool
+	)
+	if cached, _ = l.lru.Get(key); cached != nil {
+		b, ok = cached.(bucket)
+	}
+	if !ok {
+		return bucket{tokens: l.burst, last: now}
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(l.burst, b.tokens+elapsed.Seconds()*l.rate)
+		b.last = now
+	}
+	return b
+}
+
+// store writes `b` with a ttl of the time it
+// takes to refill. Buckets of limiters that
+// never refill don't expire. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (l *Limiter) store(key interface{}, b bucket) {
+	var (
+		ttl time.Duration
+	)
+	if l.rate > 0 {
+		ttl = time.Duration((l.burst-b.tokens)/l.rate*float64(time.Second)) + time.Nanosecond
+	}
+	l.lru.SetWithTTL(key, b, ttl)
+}
@@ -0,0 +1,75 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mitghi/cache"
+	"github.com/mitghi/cache/cachetest"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	var (
+		clock *cachetest.FakeClock = cachetest.NewFakeClock(time.Unix(0, 0))
+		lru   *cache.LRU           = cache.NewLRU(8, cache.WithClock(clock))
+		l     *Limiter             = New(lru, 2, 3, WithClock(clock))
+	)
+	for i := 0; i < 3; i++ {
+		if !l.Allow("a") {
+			t.Fatal("assertion failed, expected burst allowed.", i)
+		}
+	}
+	if l.Allow("a") || !l.Allow("b") {
+		t.Fatal("assertion failed, expected per-key buckets.")
+	}
+	clock.Advance(500 * time.Millisecond)
+	if !l.Allow("a") || l.Allow("a") {
+		t.Fatal("assertion failed, expected single refilled token.")
+	}
+	// idle buckets expire once full again
+	clock.Advance(2 * time.Second)
+	if lru.Read("a") != nil {
+		t.Fatal("assertion failed, expected idle bucket expired.")
+	}
+}
+
+func TestLimiterReserve(t *testing.T) {
+	var (
+		clock *cachetest.FakeClock = cachetest.NewFakeClock(time.Unix(0, 0))
+		l     *Limiter             = New(cache.NewLRU(8, cache.WithClock(clock)), 10, 1, WithClock(clock))
+	)
+	if r := l.Reserve("a"); !r.OK || r.Delay != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", r)
+	}
+	if r := l.Reserve("a"); !r.OK || r.Delay != 100*time.Millisecond {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", r)
+	}
+	if r := l.Reserve("a"); r.Delay != 200*time.Millisecond {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", r)
+	}
+	if r := New(cache.NewLRU(8), 10, 0).Reserve("a"); r.OK {
+		t.Fatal("assertion failed, expected unsatisfiable reservation.")
+	}
+}
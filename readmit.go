@@ -0,0 +1,155 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+// readmitBuffer keeps enteries recently evicted to
+// make room for a short grace period, so that they
+// can be restored without reloading them when they
+// are accessed again right after eviction.
+type readmitBuffer struct {
+	size  int
+	grace int64
+	order *list.List                    // front=most recently evicted
+	index map[interface{}]*list.Element // key -> element
+}
+
+// evictedItem is an entery held by `readmitBuffer`.
+type evictedItem struct {
+	item    LRUItem
+	evicted int64
+}
+
+// - MARK: Alloc/Init section.
+
+// WithReadmission keeps up to `size` enteries evicted
+// to make room for `grace`, restoring them on the next
+// `Get` instead of treating it as a miss ( and calling
+// the loader ), which smooths thrashing of working
+// sets slightly larger than the cache. Restored
+// enteries are counted as `Readmitted`. Buffered
+// values are retained until they fall out of the
+// buffer, are restored, or their key is written or
+// removed ( including by `RemoveFunc` and
+// `PurgeNamespace` ).
+func WithReadmission(size int, grace time.Duration) Option {
+	return func(lru *LRU) {
+		lru.opts.readmit = &readmitBuffer{
+			size:  size,
+			grace: int64(grace),
+			order: list.New(),
+			index: make(map[interface{}]*list.Element, size),
+		}
+	}
+}
+
+// - MARK: readmitBuffer section.
+
+// add buffers a copy of `item` evicted at `now`.
+func (rb *readmitBuffer) add(item *LRUItem, now int64) {
+	rb.drop(item.Key)
+	rb.index[item.Key] = rb.order.PushFront(&evictedItem{*item, now})
+	for rb.order.Len() > rb.size {
+		delete(rb.index, rb.order.Remove(rb.order.Back()).(*evictedItem).item.Key)
+	}
+}
+
+// take removes and returns the buffered entery of
+// `key` when it is still within grace period and
+// unexpired at `now`.
+func (rb *readmitBuffer) take(key interface{}, now int64) *LRUItem {
+	var (
+		elem *list.Element
+		ok   bool
+		ev   *evictedItem
+	)
+	if elem, ok = rb.index[key]; !ok {
+		return nil
+	}
+	ev = rb.order.Remove(elem).(*evictedItem)
+	delete(rb.index, key)
+	if now-ev.evicted > rb.grace || ev.item.expired(now) {
+		return nil
+	}
+	return &ev.item
+}
+
+// drop forgets the buffered entery of `key`.
+func (rb *readmitBuffer) drop(key interface{}) {
+	if elem, ok := rb.index[key]; ok {
+		rb.order.Remove(elem)
+		delete(rb.index, key)
+	}
+}
+
+// dropFunc forgets buffered enteries for which
+// `pred` returns true.
+func (rb *readmitBuffer) dropFunc(pred func(key, value interface{}) bool) {
+	var (
+		next *list.Element
+		ev   *evictedItem
+	)
+	for elem := rb.order.Front(); elem != nil; elem = next {
+		next = elem.Next()
+		if ev = elem.Value.(*evictedItem); pred(ev.item.Key, ev.item.Value) {
+			rb.order.Remove(elem)
+			delete(rb.index, ev.item.Key)
+		}
+	}
+}
+
+// reset forgets all buffered enteries.
+func (rb *readmitBuffer) reset() {
+	rb.order.Init()
+	for key, _ := range rb.index {
+		delete(rb.index, key)
+	}
+}
+
+// - MARK: LRU section.
+
+// readmit restores the buffered entery of `key`
+// and returns its element, or `nil` when there is
+// none. Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) readmit(key interface{}) *list.Element {
+	var (
+		item *LRUItem = lru.opts.readmit.take(key, lru.now())
+	)
+	if item == nil {
+		return nil
+	}
+	if lru.items.Len() > lru.capacity {
+		lru.evict()
+	}
+	if lru.opts.costFn != nil {
+		lru.opts.totalCost += item.cost
+	}
+	lru.opts.stats.Readmitted++
+	return lru.link(item)
+}
@@ -0,0 +1,75 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUReadmission(t *testing.T) {
+	var (
+		clock  *manualClock = &manualClock{time.Unix(0, 0)}
+		loads  int
+		loader Loader = LoaderFunc(func(key interface{}) (interface{}, error) {
+			loads++
+			return key, nil
+		})
+		lru   *LRU = NewLRU(2, WithClock(clock), WithLoader(loader), WithReadmission(4, time.Second))
+		value interface{}
+	)
+	lru.Set("a", "A")
+	lru.Set("b", "B")
+	lru.Set("c", "C")
+	if lru.Read("a") != nil {
+		t.Fatal("assertion failed, expected evicted entery.")
+	}
+	if value, _ = lru.Get("a"); value != "A" || loads != 0 {
+		t.Fatal("assertion failed, expected readmitted entery.", value, loads)
+	}
+	if lru.Len() != 2 || lru.Read("b") != nil || lru.Stats().Readmitted != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", lru.Len())
+	}
+	clock.now = clock.now.Add(2 * time.Second)
+	if value, _ = lru.Get("b"); value != "b" || loads != 1 {
+		t.Fatal("assertion failed, expected load after grace period.", value, loads)
+	}
+	lru.Remove("a")
+	lru.Remove("c")
+	if value, _ = lru.Get("a"); value != "a" {
+		t.Fatal("assertion failed, expected removed entery dropped from buffer.", value)
+	}
+}
+
+func TestLRUReadmissionNamespace(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(2, WithReadmission(4, time.Hour))
+	)
+	lru.Set2("ns", 1, 1)
+	lru.Set(2, 2)
+	lru.Set(3, 3)
+	lru.PurgeNamespace("ns")
+	if value, _ := lru.Get2("ns", 1); value != nil {
+		t.Fatal("assertion failed, expected purged namespace dropped from buffer.", value)
+	}
+}
@@ -0,0 +1,199 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*ReadMostly)(nil)
+	_ Remover        = (*ReadMostly)(nil)
+)
+
+// ReadMostly is a cache optimized for one writer
+// and many readers, e.g. configuration that changes
+// rarely. Readers load an immutable snapshot through
+// an atomic pointer without locking; writes are
+// batched and published as a new snapshot once per
+// interval. Therefore reads, including reads by the
+// writer, observe writes only after they have been
+// published; see `Flush`. It has no capacity and
+// never evicts.
+type ReadMostly struct {
+	view     atomic.Pointer[map[interface{}]interface{}]
+	mu       sync.Mutex
+	pending  map[interface{}]pendingWrite
+	purged   bool
+	interval time.Duration
+	timer    *time.Timer
+}
+
+// pendingWrite is a write awaiting publication.
+type pendingWrite struct {
+	value   interface{}
+	deleted bool
+}
+
+// - MARK: Alloc/Init section.
+
+// NewReadMostly allocates and initializes a new
+// `ReadMostly` publishing writes every `interval`
+// and returns a pointer to it. Note, when
+// `interval <= 0` holds true, each write is
+// published immediately.
+func NewReadMostly(interval time.Duration) *ReadMostly {
+	var (
+		rm   *ReadMostly                 = &ReadMostly{interval: interval}
+		view map[interface{}]interface{} = make(map[interface{}]interface{})
+	)
+	rm.pending = make(map[interface{}]pendingWrite)
+	rm.view.Store(&view)
+	return rm
+}
+
+// - MARK: ReadMostly section.
+
+// Set writes k/v pair; it becomes visible once
+// published. `isNew` reflects the published
+// snapshot and pending writes.
+func (rm *ReadMostly) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	rm.mu.Lock()
+	isNew = !rm.has(key)
+	rm.pending[key] = pendingWrite{value: value}
+	rm.schedule()
+	rm.mu.Unlock()
+	return isNew, nil
+}
+
+// Get conforms to `CacheInterface`. It never
+// blocks on writers.
+func (rm *ReadMostly) Get(key interface{}) (interface{}, error) {
+	return (*rm.view.Load())[key], nil
+}
+
+// Read conforms to `CacheInterface`.
+func (rm *ReadMostly) Read(key interface{}) interface{} {
+	return (*rm.view.Load())[key]
+}
+
+// Remove removes the entery associated to `key`
+// once published and returns `true` when it was
+// present.
+func (rm *ReadMostly) Remove(key interface{}) (ok bool) {
+	rm.mu.Lock()
+	if ok = rm.has(key); ok {
+		rm.pending[key] = pendingWrite{deleted: true}
+		rm.schedule()
+	}
+	rm.mu.Unlock()
+	return ok
+}
+
+// Purge removes all enteries once published.
+func (rm *ReadMostly) Purge() {
+	rm.mu.Lock()
+	rm.purged = true
+	rm.pending = make(map[interface{}]pendingWrite)
+	rm.schedule()
+	rm.mu.Unlock()
+}
+
+// Len returns number of published enteries.
+func (rm *ReadMostly) Len() int {
+	return len(*rm.view.Load())
+}
+
+// Flush publishes pending writes immediately.
+func (rm *ReadMostly) Flush() {
+	rm.mu.Lock()
+	rm.publish()
+	rm.mu.Unlock()
+}
+
+// has returns whether `key` is present taking
+// pending writes into account. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (rm *ReadMostly) has(key interface{}) bool {
+	if w, ok := rm.pending[key]; ok {
+		return !w.deleted
+	}
+	if rm.purged {
+		return false
+	}
+	_, ok := (*rm.view.Load())[key]
+	return ok
+}
+
+// schedule publishes pending writes now or arms
+// the publication timer. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (rm *ReadMostly) schedule() {
+	if rm.interval <= 0 {
+		rm.publish()
+		return
+	}
+	if rm.timer == nil {
+		rm.timer = time.AfterFunc(rm.interval, rm.Flush)
+	}
+}
+
+// publish copies the current snapshot, applies
+// pending writes and swaps it in. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (rm *ReadMostly) publish() {
+	var (
+		old  map[interface{}]interface{} = *rm.view.Load()
+		view map[interface{}]interface{}
+	)
+	if rm.timer != nil {
+		rm.timer.Stop()
+		rm.timer = nil
+	}
+	if len(rm.pending) == 0 && !rm.purged {
+		return
+	}
+	if rm.purged {
+		old = nil
+	}
+	view = make(map[interface{}]interface{}, len(old)+len(rm.pending))
+	for k, v := range old {
+		view[k] = v
+	}
+	for k, w := range rm.pending {
+		if w.deleted {
+			delete(view, k)
+		} else {
+			view[k] = w.value
+		}
+	}
+	rm.pending = make(map[interface{}]pendingWrite)
+	rm.purged = false
+	rm.view.Store(&view)
+}
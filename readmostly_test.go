@@ -0,0 +1,88 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadMostlyBatching(t *testing.T) {
+	var (
+		rm *ReadMostly = NewReadMostly(time.Hour)
+	)
+	if isNew, _ := rm.Set("a", 1); !isNew {
+		t.Fatal("assertion failed, expected new entery.")
+	}
+	if isNew, _ := rm.Set("a", 2); isNew {
+		t.Fatal("assertion failed, expected pending entery.")
+	}
+	if rm.Read("a") != nil || rm.Len() != 0 {
+		t.Fatal("assertion failed, expected unpublished write.")
+	}
+	rm.Flush()
+	if v, _ := rm.Get("a"); v != 2 || rm.Len() != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v)
+	}
+	rm.Set("b", 1)
+	if !rm.Remove("a") || rm.Remove("c") {
+		t.Fatal("assertion failed, inconsistent removal.")
+	}
+	rm.Flush()
+	if rm.Read("a") != nil || rm.Read("b") != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	rm.Purge()
+	rm.Set("c", 1)
+	rm.Flush()
+	if rm.Len() != 1 || rm.Read("c") != 1 {
+		t.Fatal("assertion failed, expected purged snapshot.", rm.Len())
+	}
+}
+
+func TestReadMostlyInterval(t *testing.T) {
+	var (
+		rm *ReadMostly = NewReadMostly(time.Millisecond)
+		wg sync.WaitGroup
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				rm.Read(j % 10)
+			}
+		}()
+	}
+	for j := 0; j < 10; j++ {
+		rm.Set(j, j)
+	}
+	wg.Wait()
+	for deadline := time.Now().Add(time.Second); rm.Len() != 10; {
+		if time.Now().After(deadline) {
+			t.Fatal("assertion failed, expected published writes.", rm.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
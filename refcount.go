@@ -0,0 +1,148 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"reflect"
+	"sync"
+)
+
+// valueRef is the reference count of a value
+// handed out by `Acquire`.
+type valueRef struct {
+	n       int
+	evicted bool // value left the cache
+}
+
+// - MARK: LRU section.
+
+// Acquire fetches `key` like `Get` and, when
+// reference counting is enabled and the value
+// conforms to `Releasable`, holds a reference
+// until `release` is called. `release` is never
+// `nil` and is safe to call more than once.
+func (lru *LRU) Acquire(key interface{}) (value interface{}, release func(), err error) {
+	var (
+		item *LRUItem
+		elem *list.Element
+	)
+	release = func() {}
+	lru.mu.Lock()
+	if item, err = lru.get(key); err == nil && item != nil {
+		value = item.Value
+		release = lru.retain(value)
+		lru.mu.Unlock()
+		return value, release, nil
+	}
+	if err != nil || lru.opts.loader == nil {
+		lru.mu.Unlock()
+		return nil, release, err
+	}
+	// load releases the lock
+	if value, err = lru.load(context.Background(), key, false); err != nil || value == nil {
+		return value, release, err
+	}
+	lru.mu.Lock()
+	// loaded values that weren't cached, or already
+	// left the cache, are owned by the caller
+	if _, ok := value.(Releasable); ok && lru.opts.refs != nil {
+		if elem = lru.readEntery(key); elem != nil && elem.Value.(*LRUItem).Value == value {
+			release = lru.retain(value)
+		}
+	}
+	lru.mu.Unlock()
+	return value, release, nil
+}
+
+// retain holds a reference of cached `value` and
+// returns the function dropping it. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) retain(value interface{}) func() {
+	var (
+		r    *valueRef
+		once sync.Once
+		ok   bool
+	)
+	if _, ok = value.(Releasable); !ok || lru.opts.refs == nil {
+		return func() {}
+	}
+	if r, ok = lru.opts.refs[value]; !ok {
+		r = &valueRef{}
+		lru.opts.refs[value] = r
+	}
+	r.n++
+	return func() { once.Do(func() { lru.release(value) }) }
+}
+
+// release drops a reference of `value` and
+// releases it when it was the last reference
+// to a value that left the cache.
+func (lru *LRU) release(value interface{}) {
+	var (
+		r    *valueRef
+		done bool
+	)
+	lru.mu.Lock()
+	r = lru.opts.refs[value]
+	if r.n--; r.n == 0 {
+		delete(lru.opts.refs, value)
+		done = r.evicted
+	}
+	lru.mu.Unlock()
+	if done {
+		value.(Releasable).Release()
+	}
+}
+
+// finalize releases `value` that left the cache
+// unless it is still referenced, in which case
+// the last reference releases it. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) finalize(value interface{}) {
+	var (
+		rel Releasable
+		ok  bool
+	)
+	if rel, ok = value.(Releasable); !ok {
+		return
+	}
+	if r, ok := lru.opts.refs[value]; ok {
+		r.evicted = true
+		return
+	}
+	rel.Release()
+}
+
+// sameValue returns whether `a` and `b` are the
+// same value; values of incomparable types never
+// are.
+func sameValue(a, b interface{}) bool {
+	var (
+		t reflect.Type = reflect.TypeOf(a)
+	)
+	return t != nil && t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
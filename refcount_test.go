@@ -0,0 +1,93 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+// pooledBuffer counts its releases.
+type pooledBuffer struct {
+	data     []byte
+	released int
+}
+
+// Release conforms to `Releasable`.
+func (pb *pooledBuffer) Release() {
+	pb.released++
+}
+
+func TestLRURefCounting(t *testing.T) {
+	var (
+		lru *LRU          = NewLRU(2, WithRefCounting())
+		a   *pooledBuffer = &pooledBuffer{data: []byte("a")}
+		b   *pooledBuffer = &pooledBuffer{data: []byte("b")}
+	)
+	lru.Set("a", a)
+	v, release, err := lru.Acquire("a")
+	if v != a || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+	}
+	_, release2, _ := lru.Acquire("a")
+	// replacing the value evicts it while referenced
+	lru.Set("a", b)
+	if a.released != 0 {
+		t.Fatal("assertion failed, expected referenced value kept.")
+	}
+	release()
+	release()
+	if a.released != 0 {
+		t.Fatal("assertion failed, expected double release ignored.")
+	}
+	release2()
+	if a.released != 1 {
+		t.Fatal("assertion failed, expected release on last reference.", a.released)
+	}
+	// unreferenced values are released on eviction
+	lru.Remove("a")
+	if b.released != 1 || len(lru.opts.refs) != 0 {
+		t.Fatal("assertion failed, expected unreferenced value released.", b.released)
+	}
+	if _, release, err = lru.Acquire("missing"); err != nil || release == nil {
+		t.Fatal("assertion failed, expected no-op release.", err)
+	}
+	release()
+}
+
+func TestLRURefCountingSameValue(t *testing.T) {
+	var (
+		lru *LRU          = NewLRU(2, WithRefCounting())
+		a   *pooledBuffer = &pooledBuffer{data: []byte("a")}
+	)
+	lru.Set("a", a)
+	// rewriting the cached value must not release it
+	lru.Set("a", a)
+	if v, _ := lru.Get("a"); v != a || a.released != 0 {
+		t.Fatal("assertion failed, expected cached value kept.", a.released)
+	}
+	if stats := lru.Stats(); stats.Evicted(EvictREPLACED) != 0 {
+		t.Fatal("assertion failed, expected nothing replaced.")
+	}
+	lru.Remove("a")
+	if a.released != 1 {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 1, a.released)
+	}
+}
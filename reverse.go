@@ -0,0 +1,107 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// - MARK: LRU section.
+
+// RemoveValue removes all enteries whose value
+// shares identity with `value` and returns number
+// of removed enteries. It requires the cache to be
+// constructed with `WithIdentity`.
+func (lru *LRU) RemoveValue(value interface{}) (n int) {
+	if lru.opts.identity == nil {
+		return 0
+	}
+	var (
+		id interface{} = lru.opts.identity(value)
+	)
+	lru.mu.Lock()
+	for key, _ := range lru.opts.reverse[id] {
+		if lru.remove(key) {
+			n++
+		}
+	}
+	lru.mu.Unlock()
+	return n
+}
+
+// KeysFor returns keys of all enteries whose value
+// shares identity with `value`. It requires the cache
+// to be constructed with `WithIdentity`.
+func (lru *LRU) KeysFor(value interface{}) (keys []interface{}) {
+	if lru.opts.identity == nil {
+		return nil
+	}
+	var (
+		id interface{} = lru.opts.identity(value)
+	)
+	lru.mu.Lock()
+	keys = make([]interface{}, 0, len(lru.opts.reverse[id]))
+	for key, _ := range lru.opts.reverse[id] {
+		keys = append(keys, key)
+	}
+	lru.mu.Unlock()
+	return keys
+}
+
+// revLink registers `item` in the reverse index.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) revLink(item *LRUItem) {
+	var (
+		id   interface{} = lru.opts.identity(item.Value)
+		keys map[interface{}]struct{}
+		ok   bool
+	)
+	if id == nil {
+		return
+	}
+	if keys, ok = lru.opts.reverse[id]; !ok {
+		keys = make(map[interface{}]struct{})
+		lru.opts.reverse[id] = keys
+	}
+	keys[item.Key] = struct{}{}
+}
+
+// revUnlink removes `item` from the reverse index.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) revUnlink(item *LRUItem) {
+	var (
+		id   interface{} = lru.opts.identity(item.Value)
+		keys map[interface{}]struct{}
+		ok   bool
+	)
+	if id == nil {
+		return
+	}
+	if keys, ok = lru.opts.reverse[id]; !ok {
+		return
+	}
+	delete(keys, item.Key)
+	if len(keys) == 0 {
+		delete(lru.opts.reverse, id)
+	}
+}
@@ -0,0 +1,60 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestLRUReverseIndex(t *testing.T) {
+	type entity struct {
+		ID   int
+		Name string
+	}
+	var (
+		lru *LRU = NewLRU(8, WithIdentity(func(value interface{}) interface{} {
+			if e, ok := value.(*entity); ok {
+				return e.ID
+			}
+			return nil
+		}))
+		alice *entity = &entity{1, "alice"}
+		bob   *entity = &entity{2, "bob"}
+	)
+	lru.Set("q:1", alice)
+	lru.Set("q:2", alice)
+	lru.Set("q:3", bob)
+	lru.Set("plain", "value")
+	if keys := lru.KeysFor(alice); len(keys) != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", keys)
+	}
+	// rewriting a key moves it to the new identity
+	lru.Set("q:2", bob)
+	if len(lru.KeysFor(alice)) != 1 || len(lru.KeysFor(bob)) != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if n := lru.RemoveValue(&entity{ID: 2}); n != 2 {
+		t.Fatalf("assertion failed, expected equal with value(2) - got value(%d).", n)
+	}
+	if lru.Len() != 2 || lru.Read("q:3") != nil || len(lru.opts.reverse) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+}
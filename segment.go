@@ -0,0 +1,56 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// Segment is a lightweight snapshot of the keys
+// of one policy segment, ordered from most to
+// least recently used.
+type Segment struct {
+	Name string
+	Keys []interface{}
+}
+
+const (
+	// SegmentLRU names the single recency list of
+	// `LRU`.
+	SegmentLRU = "lru"
+)
+
+// - MARK: LRU section.
+
+// Segments returns ordered key lists of every
+// policy segment of the cache, e.g. for
+// visualizing cache dynamics while tuning. The
+// returned slices are not shared with the cache.
+func (lru *LRU) Segments() []Segment {
+	var (
+		keys []interface{}
+	)
+	lru.mu.Lock()
+	keys = make([]interface{}, 0, lru.items.Len())
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Value.(*LRUItem).Key)
+	}
+	lru.mu.Unlock()
+	return []Segment{{Name: SegmentLRU, Keys: keys}}
+}
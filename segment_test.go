@@ -0,0 +1,50 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLRUSegments(t *testing.T) {
+	var (
+		lru      *LRU = NewLRU(4)
+		segments []Segment
+	)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.Set("c", 3)
+	lru.Get("a")
+	segments = lru.Segments()
+	if len(segments) != 1 || segments[0].Name != SegmentLRU {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", segments)
+	}
+	if !reflect.DeepEqual(segments[0].Keys, []interface{}{"a", "c", "b"}) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", segments[0].Keys)
+	}
+	segments[0].Keys[0] = "mutated"
+	if lru.Segments()[0].Keys[0] != "a" {
+		t.Fatal("assertion failed, expected detached snapshot.")
+	}
+}
@@ -0,0 +1,173 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+)
+
+// sessionIDBYTES is number of random bytes in
+// session ids.
+const sessionIDBYTES = 32
+
+// SessionStore keeps sessions in an `LRU` with a
+// sliding idle timeout and an absolute lifetime.
+// Sessions are namespaced per store, so several
+// stores may share one cache.
+type SessionStore struct {
+	lru      *LRU
+	idle     time.Duration
+	absolute time.Duration
+}
+
+// session is the cached form of a session.
+type session struct {
+	value   interface{}
+	created int64
+}
+
+// - MARK: Alloc/Init section.
+
+// NewSessionStore allocates and initializes a new
+// `SessionStore` struct keeping sessions in `lru`
+// and returns a pointer to it. Sessions expire
+// after `idle` without access and `absolute` after
+// creation; non-positive durations disable the
+// respective timeout.
+func NewSessionStore(lru *LRU, idle, absolute time.Duration) *SessionStore {
+	return &SessionStore{lru: lru, idle: idle, absolute: absolute}
+}
+
+// - MARK: SessionStore section.
+
+// Create starts a session holding `value` and
+// returns its id: 256 random bits, base64url
+// encoded.
+func (ss *SessionStore) Create(value interface{}) (id string, err error) {
+	var (
+		raw [sessionIDBYTES]byte
+		now int64
+	)
+	if _, err = rand.Read(raw[:]); err != nil {
+		return "", err
+	}
+	id = base64.RawURLEncoding.EncodeToString(raw[:])
+	ss.lru.mu.Lock()
+	now = ss.lru.now()
+	_, err = ss.lru.set(compositeKey{ss, id}, &session{value: value, created: now}, ss.expiry(now, now))
+	ss.lru.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Get returns the value of session `id` and
+// extends its idle timeout.
+func (ss *SessionStore) Get(id string) (value interface{}, ok bool) {
+	var (
+		item *LRUItem
+	)
+	ss.lru.mu.Lock()
+	if item = ss.touch(id); item != nil {
+		value, ok = item.Value.(*session).value, true
+	}
+	ss.lru.mu.Unlock()
+	return value, ok
+}
+
+// Update replaces the value of session `id`,
+// extending its idle timeout, and returns `false`
+// when the session doesn't exist.
+func (ss *SessionStore) Update(id string, value interface{}) (ok bool) {
+	var (
+		item *LRUItem
+	)
+	ss.lru.mu.Lock()
+	if item = ss.touch(id); item != nil {
+		_, err := ss.lru.set(item.Key, &session{value: value, created: item.Value.(*session).created}, item.expires)
+		ok = err == nil
+	}
+	ss.lru.mu.Unlock()
+	return ok
+}
+
+// Destroy ends session `id`.
+func (ss *SessionStore) Destroy(id string) bool {
+	return ss.lru.Remove(compositeKey{ss, id})
+}
+
+// Active returns ids of live sessions.
+func (ss *SessionStore) Active() (ids []string) {
+	var (
+		now int64
+	)
+	ss.lru.mu.Lock()
+	now = ss.lru.now()
+	for id, elem := range ss.lru.opts.namespaces[ss] {
+		if !elem.Value.(*LRUItem).expired(now) {
+			ids = append(ids, id.(string))
+		}
+	}
+	ss.lru.mu.Unlock()
+	return ids
+}
+
+// touch returns the live item of session `id`
+// after extending its idle timeout, or `nil`.
+// Expired sessions are reclaimed. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (ss *SessionStore) touch(id string) (item *LRUItem) {
+	var (
+		elem *list.Element = ss.lru.readEntery(compositeKey{ss, id})
+		now  int64
+	)
+	if elem == nil {
+		return nil
+	}
+	now = ss.lru.now()
+	if item = elem.Value.(*LRUItem); item.expired(now) {
+		ss.lru.unlink(elem, EvictEXPIRED)
+		return nil
+	}
+	item.expires = ss.expiry(item.Value.(*session).created, now)
+	ss.lru.scheduleExpiry(item)
+	ss.lru.items.MoveToFront(elem)
+	return item
+}
+
+// expiry returns the deadline of a session created
+// at `created` and last accessed at `now`.
+func (ss *SessionStore) expiry(created, now int64) (expires int64) {
+	if ss.idle > 0 {
+		expires = now + int64(ss.idle)
+	}
+	if ss.absolute > 0 && (expires == 0 || created+int64(ss.absolute) < expires) {
+		expires = created + int64(ss.absolute)
+	}
+	return expires
+}
@@ -0,0 +1,72 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionStore(t *testing.T) {
+	var (
+		clock *manualClock  = &manualClock{time.Unix(0, 0)}
+		lru   *LRU          = NewLRU(16, WithClock(clock))
+		ss    *SessionStore = NewSessionStore(lru, time.Minute, 5*time.Minute)
+		other *SessionStore = NewSessionStore(lru, time.Minute, 0)
+	)
+	id, err := ss.Create("alice")
+	if err != nil || len(id) != 43 {
+		t.Fatal("assertion failed, unexpected id.", id, err)
+	}
+	if id2, _ := ss.Create("bob"); id2 == id {
+		t.Fatal("assertion failed, expected unique ids.")
+	}
+	other.Create("carol")
+	if n := len(ss.Active()); n != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n)
+	}
+	// sliding idle expiry keeps accessed sessions alive
+	for i := 0; i < 4; i++ {
+		clock.now = clock.now.Add(50 * time.Second)
+		if v, ok := ss.Get(id); !ok || v != "alice" {
+			t.Fatal("assertion failed, expected live session.", i, v)
+		}
+	}
+	if n := len(ss.Active()); n != 1 {
+		t.Fatal("assertion failed, expected idle session expired.", n)
+	}
+	if !ss.Update(id, "alice2") {
+		t.Fatal("assertion failed, expected updated session.")
+	}
+	// absolute timeout bounds sliding extension
+	for i := 0; i < 3; i++ {
+		clock.now = clock.now.Add(50 * time.Second)
+		ss.Get(id)
+	}
+	if _, ok := ss.Get(id); ok {
+		t.Fatal("assertion failed, expected absolute timeout.")
+	}
+	if ss.Update(id, "x") || ss.Destroy(id) {
+		t.Fatal("assertion failed, expected missing session.")
+	}
+}
@@ -0,0 +1,165 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// ShadowPolicy is protocol definition for eviction
+// policies simulated alongside the cache. They see
+// hashes of looked up keys, never values, and report
+// whether they would have served each lookup.
+type ShadowPolicy interface {
+	Name() string
+	Access(hash uint64) (hit bool)
+}
+
+// ShadowStats are counters of a shadow policy.
+type ShadowStats struct {
+	Name     string
+	Hits     uint64
+	Accesses uint64
+}
+
+// shadowState is an attached shadow policy.
+type shadowState struct {
+	policy ShadowPolicy
+	stats  ShadowStats
+}
+
+// shadowLRU is an LRU shadow policy.
+type shadowLRU struct {
+	sim *simLRU
+}
+
+// shadowTinyLFU is an LRU shadow policy admitting
+// new keys only when they are estimated to be
+// accessed more often than the eviction victim.
+type shadowTinyLFU struct {
+	sim    *simLRU
+	sketch *countMin
+}
+
+// - MARK: Alloc/Init section.
+
+// NewShadowLRU returns a shadow LRU policy holding
+// `size` keys.
+func NewShadowLRU(size int) ShadowPolicy {
+	return &shadowLRU{&simLRU{size: size, order: list.New(), index: make(map[uint64]*list.Element)}}
+}
+
+// NewShadowTinyLFU returns a shadow TinyLFU policy
+// holding `size` keys.
+func NewShadowTinyLFU(size int) ShadowPolicy {
+	return &shadowTinyLFU{
+		sim:    &simLRU{size: size, order: list.New(), index: make(map[uint64]*list.Element)},
+		sketch: newCountMin(size),
+	}
+}
+
+// WithShadow attaches shadow `policies` observing
+// lookups ( `Get` and variants ) so that hit ratios
+// of alternative policies can be compared to the
+// cache's on live traffic; see `Stats.Shadows`.
+// Shadows cost time per lookup and memory per key
+// hash, but never hold values.
+func WithShadow(policies ...ShadowPolicy) Option {
+	return func(lru *LRU) {
+		for _, p := range policies {
+			lru.opts.shadows = append(lru.opts.shadows, &shadowState{policy: p, stats: ShadowStats{Name: p.Name()}})
+		}
+	}
+}
+
+// - MARK: ShadowStats section.
+
+// HitRatio returns ratio of hits to accesses or
+// zero when there were no accesses.
+func (ss ShadowStats) HitRatio() float64 {
+	if ss.Accesses == 0 {
+		return 0
+	}
+	return float64(ss.Hits) / float64(ss.Accesses)
+}
+
+// - MARK: shadowLRU section.
+
+// Name conforms to `ShadowPolicy`.
+func (s *shadowLRU) Name() string {
+	return "lru"
+}
+
+// Access conforms to `ShadowPolicy`.
+func (s *shadowLRU) Access(hash uint64) bool {
+	var (
+		hits uint64 = s.sim.hits
+	)
+	s.sim.access(hash)
+	return s.sim.hits != hits
+}
+
+// - MARK: shadowTinyLFU section.
+
+// Name conforms to `ShadowPolicy`.
+func (s *shadowTinyLFU) Name() string {
+	return "tinylfu"
+}
+
+// Access conforms to `ShadowPolicy`.
+func (s *shadowTinyLFU) Access(hash uint64) bool {
+	var (
+		elem *list.Element
+		ok   bool
+	)
+	s.sketch.add(hash)
+	if elem, ok = s.sim.index[hash]; ok {
+		s.sim.order.MoveToFront(elem)
+		return true
+	}
+	if s.sim.order.Len() >= s.sim.size {
+		victim := s.sim.order.Back()
+		if s.sketch.estimate(hash) <= s.sketch.estimate(victim.Value.(uint64)) {
+			return false
+		}
+		delete(s.sim.index, s.sim.order.Remove(victim).(uint64))
+	}
+	s.sim.index[hash] = s.sim.order.PushFront(hash)
+	return false
+}
+
+// - MARK: LRU section.
+
+// shadow replays a lookup of `key` in shadow
+// policies. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) shadow(key interface{}) {
+	var (
+		h uint64 = hashKey(key)
+	)
+	for _, s := range lru.opts.shadows {
+		s.stats.Accesses++
+		if s.policy.Access(h) {
+			s.stats.Hits++
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLRUShadowPolicies(t *testing.T) {
+	var (
+		lru   *LRU       = NewLRU(100, WithShadow(NewShadowLRU(100), NewShadowTinyLFU(100)))
+		rng   *rand.Rand = rand.New(rand.NewSource(1))
+		stats Stats
+	)
+	// a hot set of 50 keys polluted by one-off scans
+	for i := 0; i < 50000; i++ {
+		key := rng.Intn(50)
+		if i%2 == 0 {
+			key = 1000 + i
+		}
+		if v, _ := lru.Get(key); v == nil {
+			lru.Set(key, key)
+		}
+	}
+	stats = lru.Stats()
+	if len(stats.Shadows) != 2 || stats.Shadows[0].Name != "lru" || stats.Shadows[1].Name != "tinylfu" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Shadows)
+	}
+	if stats.Shadows[0].Hits != stats.Hits || stats.Shadows[0].Accesses != stats.Hits+stats.Misses {
+		t.Fatal("assertion failed, expected shadow LRU to mirror the cache.", stats.Shadows[0], stats.Counters)
+	}
+	if stats.Shadows[1].HitRatio() <= stats.Shadows[0].HitRatio() {
+		t.Fatal("assertion failed, expected TinyLFU to resist scans.", stats.Shadows)
+	}
+	lru.ResetStats()
+	if lru.Stats().Shadows[1].Accesses != 0 {
+		t.Fatal("assertion failed, expected reset shadows.")
+	}
+}
+
+func TestCountMin(t *testing.T) {
+	var (
+		cm *countMin = newCountMin(64)
+	)
+	for i := 0; i < 20; i++ {
+		cm.add(1)
+	}
+	cm.add(2)
+	if cm.estimate(1) < 20 || cm.estimate(2) < 1 || cm.estimate(3) > 1 {
+		t.Fatal("assertion failed, inconsistent state.", cm.estimate(1), cm.estimate(2), cm.estimate(3))
+	}
+	cm.age()
+	if cm.estimate(1) != 10 {
+		t.Fatal("assertion failed, expected halved counters.", cm.estimate(1))
+	}
+}
@@ -0,0 +1,151 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "hash/maphash"
+
+// Defaults
+const (
+	defaultSHARDS = 16
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*ShardedLRU)(nil)
+	_ Remover        = (*ShardedLRU)(nil)
+)
+
+// ShardedLRU partitions keys across independently
+// locked `LRU` shards so that goroutines accessing
+// distinct shards don't contend on one mutex. Each
+// shard evicts on its own; therefore recency is only
+// approximated across shards.
+type ShardedLRU struct {
+	shards []*LRU
+	hash   func(key interface{}) uint64
+}
+
+// - MARK: Alloc/Init section.
+
+// NewShardedLRU allocates and initializes a new
+// `ShardedLRU` splitting `capacity` evenly across
+// `shards` shards configured with `opts`, keys
+// being assigned to shards by `hash`, and returns
+// a pointer to it. Note, when `shards <= 0` holds
+// true, it's set to `defaultSHARDS` ( by default
+// 16 ); when `hash` is nil, keys are hashed with
+// `maphash.Comparable` and a random seed.
+func NewShardedLRU(shards int, capacity int, hash func(key interface{}) uint64, opts ...Option) *ShardedLRU {
+	var (
+		sl *ShardedLRU
+	)
+	if shards <= 0 {
+		shards = defaultSHARDS
+	}
+	if hash == nil {
+		seed := maphash.MakeSeed()
+		hash = func(key interface{}) uint64 {
+			return maphash.Comparable(seed, key)
+		}
+	}
+	sl = &ShardedLRU{shards: make([]*LRU, shards), hash: hash}
+	for i := range sl.shards {
+		sl.shards[i] = NewLRU((capacity+shards-1)/shards, opts...)
+	}
+	return sl
+}
+
+// - MARK: ShardedLRU section.
+
+// Set conforms to `CacheInterface`.
+func (sl *ShardedLRU) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	return sl.Shard(key).Set(key, value)
+}
+
+// Get conforms to `CacheInterface`.
+func (sl *ShardedLRU) Get(key interface{}) (value interface{}, err error) {
+	return sl.Shard(key).Get(key)
+}
+
+// Read conforms to `CacheInterface`.
+func (sl *ShardedLRU) Read(key interface{}) (value interface{}) {
+	return sl.Shard(key).Read(key)
+}
+
+// Remove conforms to `Remover`.
+func (sl *ShardedLRU) Remove(key interface{}) (ok bool) {
+	return sl.Shard(key).Remove(key)
+}
+
+// Purge conforms to `CacheInterface` and purges
+// all shards.
+func (sl *ShardedLRU) Purge() {
+	for _, shard := range sl.shards {
+		shard.Purge()
+	}
+}
+
+// Len conforms to `CacheInterface` and returns
+// number of items in all shards.
+func (sl *ShardedLRU) Len() (n int) {
+	for _, shard := range sl.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// Stats returns the sum of statistics of all
+// shards. Each shard is read separately; hence
+// the sum isn't an atomic snapshot.
+func (sl *ShardedLRU) Stats() (stats Stats) {
+	stats.Namespaces = make(map[interface{}]Counters)
+	for _, shard := range sl.shards {
+		s := shard.Stats()
+		stats.Counters.add(&s.Counters)
+		stats.Name, stats.Labels = s.Name, s.Labels
+		for ns, c := range s.Namespaces {
+			sum := stats.Namespaces[ns]
+			sum.add(&c)
+			stats.Namespaces[ns] = sum
+		}
+	}
+	return stats
+}
+
+// Stop stops janitor goroutines of all shards,
+// if any ( see `WithJanitor` ).
+func (sl *ShardedLRU) Stop() {
+	for _, shard := range sl.shards {
+		shard.Stop()
+	}
+}
+
+// Shard returns the shard owning `key`.
+func (sl *ShardedLRU) Shard(key interface{}) *LRU {
+	return sl.shards[sl.hash(key)%uint64(len(sl.shards))]
+}
+
+// Shards returns all shards.
+func (sl *ShardedLRU) Shards() []*LRU {
+	return append([]*LRU(nil), sl.shards...)
+}
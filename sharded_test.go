@@ -0,0 +1,89 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestShardedLRU(t *testing.T) {
+	var (
+		sl *ShardedLRU = NewShardedLRU(4, 256, nil)
+		wg sync.WaitGroup
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 8; i++ {
+				sl.Set(g*8+i, i)
+				sl.Get(g*8 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if sl.Len() != 64 || sl.Read(9) != 1 {
+		t.Fatalf("assertion failed, expected equal with value(64) - got value(%d).", sl.Len())
+	}
+	if stats := sl.Stats(); stats.Hits != 64 {
+		t.Fatalf("assertion failed, expected equal with value(64) - got value(%d).", stats.Hits)
+	}
+	if !sl.Remove(9) || sl.Shard(9).Read(9) != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	sl.Purge()
+	if sl.Len() != 0 {
+		t.Fatal("assertion failed, expected empty cache.")
+	}
+}
+
+func TestShardedLRUHash(t *testing.T) {
+	var (
+		sl *ShardedLRU = NewShardedLRU(2, 16, func(key interface{}) uint64 { return uint64(key.(int)) })
+	)
+	sl.Set(1, 1)
+	sl.Set(2, 2)
+	if sl.Shards()[1].Read(1) != 1 || sl.Shards()[0].Read(2) != 2 {
+		t.Fatal("assertion failed, expected keys in shards picked by hash.")
+	}
+}
+
+func TestCountersAdd(t *testing.T) {
+	var (
+		c, o Counters
+		v    reflect.Value = reflect.ValueOf(&o).Elem()
+	)
+	// every counter must be summed
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Uint64 {
+			f.SetUint(1)
+		}
+	}
+	o.Evictions[EvictEXPIRED] = 1
+	c.add(&o)
+	if !reflect.DeepEqual(c, o) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", c, o)
+	}
+}
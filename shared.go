@@ -0,0 +1,389 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+)
+
+// Error messages
+var (
+	ELRUSHMLAYOUT      error = errors.New("cache(shm): incompatible shared memory layout.")
+	ELRUSHMSIZE        error = errors.New("cache(shm): entery exceeds slot size.")
+	ELRUSHMUNSUPPORTED error = errors.New("cache(shm): shared memory is not supported on this platform.")
+)
+
+// Defaults
+const (
+	defaultSHMPROBE = 16
+)
+
+// Shared memory layout; all fields are little
+// endian. The header is followed by `slots`
+// slots of `slotSize` bytes each.
+const (
+	shmHEADER = 32 // magic, slots, slot size, clock, count
+	shmSLOT   = 32 // state, key and value length, hash, stamp, expiry
+)
+
+// Slot states
+const (
+	shmEMPTY = iota
+	shmUSED
+	shmDELETED
+)
+
+// shmMAGIC identifies shared memory caches.
+var shmMAGIC = []byte("LRUSHM\x00\x01")
+
+// SharedCache is an experimental cache living in
+// a memory mapped file, so that processes on the
+// same host mapping the same file share enteries
+// without a network hop. Enteries are stored in
+// fixed size slots addressed by key hash; when
+// all slots a key may occupy are taken, the least
+// recently used of them is overwritten. Accesses
+// are serialized across processes by locking the
+// file. Keys and values are byte strings since
+// pointers can't be shared between processes.
+type SharedCache struct {
+	mu       sync.Mutex
+	file     *os.File
+	data     []byte
+	slots    int
+	slotSize int
+}
+
+// - MARK: Alloc/Init section.
+
+// OpenShared maps the shared cache at `path`,
+// creating it with `slots` slots of `slotSize`
+// bytes when it doesn't exist, and returns a
+// pointer to it. A slot holds a key and value
+// of up to `slotSize - 32` bytes in total. All
+// processes must open the cache with the same
+// layout; otherwise it fails with `ELRUSHMLAYOUT`.
+// It fails with `ELRUSHMUNSUPPORTED` on platforms
+// without memory mapped files.
+func OpenShared(path string, slots int, slotSize int) (sc *SharedCache, err error) {
+	var (
+		f    *os.File
+		info os.FileInfo
+		size int = shmHEADER + slots*slotSize
+	)
+	if slots <= 0 || slotSize <= shmSLOT {
+		return nil, ELRUSHMLAYOUT
+	}
+	if f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600); err != nil {
+		return nil, err
+	}
+	if err = lockShared(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	defer unlockShared(f)
+	if info, err = f.Stat(); err == nil && info.Size() == 0 {
+		err = f.Truncate(int64(size))
+	} else if err == nil && info.Size() != int64(size) {
+		err = ELRUSHMLAYOUT
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	sc = &SharedCache{file: f, slots: slots, slotSize: slotSize}
+	if sc.data, err = mapShared(f, size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !bytes.Equal(sc.data[:8], shmMAGIC) {
+		copy(sc.data, shmMAGIC)
+		binary.LittleEndian.PutUint32(sc.data[8:], uint32(slots))
+		binary.LittleEndian.PutUint32(sc.data[12:], uint32(slotSize))
+	} else if binary.LittleEndian.Uint32(sc.data[8:]) != uint32(slots) || binary.LittleEndian.Uint32(sc.data[12:]) != uint32(slotSize) {
+		sc.close()
+		return nil, ELRUSHMLAYOUT
+	}
+	return sc, nil
+}
+
+// - MARK: SharedCache section.
+
+// Set writes k/v pair to the cache.
+func (sc *SharedCache) Set(key []byte, value []byte) (isNew bool, err error) {
+	return sc.SetWithTTL(key, value, 0)
+}
+
+// SetWithTTL writes k/v pair to the cache which
+// expires after `ttl`, or never when `ttl <= 0`
+// holds true.
+func (sc *SharedCache) SetWithTTL(key []byte, value []byte, ttl time.Duration) (isNew bool, err error) {
+	var (
+		h       uint64 = shmHash(key)
+		now     int64  = time.Now().UnixNano()
+		expires int64
+		target  int = -1
+		free    int = -1
+		oldest  int = -1
+		slot    []byte
+	)
+	if shmSLOT+len(key)+len(value) > sc.slotSize {
+		return false, ELRUSHMSIZE
+	}
+	if ttl > 0 {
+		expires = now + int64(ttl)
+	}
+	if err = sc.lock(); err != nil {
+		return false, err
+	}
+	defer sc.unlock()
+	for i := 0; i < min(sc.slots, defaultSHMPROBE); i++ {
+		idx := int((h + uint64(i)) % uint64(sc.slots))
+		slot = sc.slot(idx)
+		state := slot[0]
+		if state == shmUSED && sc.matches(slot, h, key) {
+			target, isNew = idx, shmExpired(slot, now)
+			break
+		}
+		if state != shmUSED || shmExpired(slot, now) {
+			if free < 0 {
+				free = idx
+			}
+			if state == shmEMPTY {
+				break
+			}
+			continue
+		}
+		if oldest < 0 || shmStamp(slot) < shmStamp(sc.slot(oldest)) {
+			oldest = idx
+		}
+	}
+	switch {
+	case target >= 0:
+	case free >= 0:
+		target, isNew = free, true
+	default:
+		target, isNew = oldest, true
+	}
+	slot = sc.slot(target)
+	if isNew && slot[0] != shmUSED {
+		sc.addCount(1)
+	}
+	slot[0] = shmUSED
+	binary.LittleEndian.PutUint16(slot[2:], uint16(len(key)))
+	binary.LittleEndian.PutUint32(slot[4:], uint32(len(value)))
+	binary.LittleEndian.PutUint64(slot[8:], h)
+	binary.LittleEndian.PutUint64(slot[16:], sc.tick())
+	binary.LittleEndian.PutUint64(slot[24:], uint64(expires))
+	copy(slot[shmSLOT:], key)
+	copy(slot[shmSLOT+len(key):], value)
+	return isNew, nil
+}
+
+// Get fetches a copy of the value associated to
+// `key` and `false` when `key` isn't cached.
+func (sc *SharedCache) Get(key []byte) (value []byte, ok bool) {
+	var (
+		slot []byte
+		idx  int
+	)
+	if sc.lock() != nil {
+		return nil, false
+	}
+	defer sc.unlock()
+	if idx = sc.find(key); idx < 0 {
+		return nil, false
+	}
+	slot = sc.slot(idx)
+	binary.LittleEndian.PutUint64(slot[16:], sc.tick())
+	kl, vl := int(binary.LittleEndian.Uint16(slot[2:])), int(binary.LittleEndian.Uint32(slot[4:]))
+	value = make([]byte, vl)
+	copy(value, slot[shmSLOT+kl:])
+	return value, true
+}
+
+// Remove removes the entery associated to `key`
+// and returns `true` when succesfull.
+func (sc *SharedCache) Remove(key []byte) (ok bool) {
+	var (
+		idx int
+	)
+	if sc.lock() != nil {
+		return false
+	}
+	defer sc.unlock()
+	if idx = sc.find(key); idx < 0 {
+		return false
+	}
+	sc.slot(idx)[0] = shmDELETED
+	sc.addCount(-1)
+	return true
+}
+
+// Purge removes all enteries.
+func (sc *SharedCache) Purge() {
+	if sc.lock() != nil {
+		return
+	}
+	clear(sc.data[shmHEADER:])
+	binary.LittleEndian.PutUint32(sc.data[28:], 0)
+	sc.unlock()
+}
+
+// Len returns number of slots in use, including
+// expired enteries that weren't reclaimed yet.
+func (sc *SharedCache) Len() (n int) {
+	if sc.lock() != nil {
+		return 0
+	}
+	n = int(binary.LittleEndian.Uint32(sc.data[28:]))
+	sc.unlock()
+	return n
+}
+
+// Close unmaps the cache; enteries remain in the
+// file for other processes.
+func (sc *SharedCache) Close() (err error) {
+	sc.mu.Lock()
+	err = sc.close()
+	sc.mu.Unlock()
+	return err
+}
+
+// close unmaps the cache and closes its file.
+func (sc *SharedCache) close() (err error) {
+	if sc.data == nil {
+		return nil
+	}
+	err = unmapShared(sc.data)
+	sc.data = nil
+	if cerr := sc.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// lock locks the cache against goroutines of this
+// and other processes.
+func (sc *SharedCache) lock() (err error) {
+	sc.mu.Lock()
+	if sc.data == nil {
+		err = os.ErrClosed
+	} else {
+		err = lockShared(sc.file)
+	}
+	if err != nil {
+		sc.mu.Unlock()
+	}
+	return err
+}
+
+// unlock unlocks the cache.
+func (sc *SharedCache) unlock() {
+	unlockShared(sc.file)
+	sc.mu.Unlock()
+}
+
+// find returns the slot holding live `key` or -1.
+// Expired enteries found on the way are reclaimed.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (sc *SharedCache) find(key []byte) int {
+	var (
+		h   uint64 = shmHash(key)
+		now int64  = time.Now().UnixNano()
+	)
+	for i := 0; i < min(sc.slots, defaultSHMPROBE); i++ {
+		idx := int((h + uint64(i)) % uint64(sc.slots))
+		slot := sc.slot(idx)
+		switch {
+		case slot[0] == shmEMPTY:
+			return -1
+		case slot[0] != shmUSED || !sc.matches(slot, h, key):
+			continue
+		case shmExpired(slot, now):
+			slot[0] = shmDELETED
+			sc.addCount(-1)
+			return -1
+		}
+		return idx
+	}
+	return -1
+}
+
+// slot returns the bytes of slot `idx`.
+func (sc *SharedCache) slot(idx int) []byte {
+	var (
+		offset int = shmHEADER + idx*sc.slotSize
+	)
+	return sc.data[offset : offset+sc.slotSize]
+}
+
+// matches returns whether `slot` holds `key`.
+func (sc *SharedCache) matches(slot []byte, h uint64, key []byte) bool {
+	return binary.LittleEndian.Uint64(slot[8:]) == h &&
+		int(binary.LittleEndian.Uint16(slot[2:])) == len(key) &&
+		bytes.Equal(slot[shmSLOT:shmSLOT+len(key)], key)
+}
+
+// tick advances and returns the shared access
+// clock.
+func (sc *SharedCache) tick() (t uint64) {
+	t = binary.LittleEndian.Uint64(sc.data[16:]) + 1
+	binary.LittleEndian.PutUint64(sc.data[16:], t)
+	return t
+}
+
+// addCount adds `n` to the number of used slots.
+func (sc *SharedCache) addCount(n int) {
+	binary.LittleEndian.PutUint32(sc.data[28:], uint32(int(binary.LittleEndian.Uint32(sc.data[28:]))+n))
+}
+
+// shmHash returns the 64 bit FNV-1a hash of `key`,
+// which is stable across processes.
+func shmHash(key []byte) uint64 {
+	var (
+		h = fnv.New64a()
+	)
+	h.Write(key)
+	return h.Sum64()
+}
+
+// shmStamp returns the last access of `slot`.
+func shmStamp(slot []byte) uint64 {
+	return binary.LittleEndian.Uint64(slot[16:])
+}
+
+// shmExpired returns whether `slot` has expired
+// at `now`.
+func shmExpired(slot []byte, now int64) bool {
+	expires := int64(binary.LittleEndian.Uint64(slot[24:]))
+	return expires != 0 && expires <= now
+}
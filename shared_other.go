@@ -0,0 +1,51 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || illumos)
+
+/* MIT License
+*
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+*
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+*
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+ */
+
+package cache
+
+import "os"
+
+// mapShared fails with `ELRUSHMUNSUPPORTED` on
+// platforms without memory mapped files.
+func mapShared(f *os.File, size int) ([]byte, error) {
+	return nil, ELRUSHMUNSUPPORTED
+}
+
+// unmapShared is a no-op on platforms without
+// memory mapped files.
+func unmapShared(data []byte) error {
+	return nil
+}
+
+// lockShared is a no-op on platforms without
+// memory mapped files.
+func lockShared(f *os.File) error {
+	return nil
+}
+
+// unlockShared is a no-op on platforms without
+// memory mapped files.
+func unlockShared(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd || illumos
+
+/* MIT License
+*
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+*
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+*
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+ */
+
+package cache
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSharedCache(t *testing.T) {
+	var (
+		path string = filepath.Join(t.TempDir(), "cache.shm")
+	)
+	a, err := OpenShared(path, 4, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	// a second mapping stands in for another process
+	b, err := OpenShared(path, 4, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if _, err = OpenShared(path, 8, 32+32); err != ELRUSHMLAYOUT {
+		t.Fatal("assertion failed, expected layout mismatch.", err)
+	}
+	if isNew, err := a.Set([]byte("a"), []byte("1")); !isNew || err != nil {
+		t.Fatal("assertion failed, expected new entery.", err)
+	}
+	if value, ok := b.Get([]byte("a")); !ok || string(value) != "1" {
+		t.Fatal("assertion failed, expected entery to be shared.", value)
+	}
+	if isNew, _ := b.Set([]byte("a"), []byte("2")); isNew {
+		t.Fatal("assertion failed, expected update.")
+	}
+	if value, _ := a.Get([]byte("a")); string(value) != "2" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	if _, err = a.Set([]byte("big"), make([]byte, 64)); err != ELRUSHMSIZE {
+		t.Fatal("assertion failed, expected oversized entery to fail.", err)
+	}
+	// overflowing the slots replaces the least recently used
+	for i := 0; i < 4; i++ {
+		a.Get([]byte("a"))
+		a.Set([]byte(fmt.Sprint(i)), []byte("v"))
+	}
+	if _, ok := b.Get([]byte("a")); !ok || b.Len() != 4 {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 4, b.Len())
+	}
+	a.SetWithTTL([]byte("a"), []byte("3"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := b.Get([]byte("a")); ok || !b.Remove([]byte("3")) || b.Len() != 2 {
+		t.Fatal("assertion failed, expected expired and removed enteries to be reclaimed.", b.Len())
+	}
+	a.Purge()
+	if b.Len() != 0 {
+		t.Fatal("assertion failed, expected empty cache.")
+	}
+}
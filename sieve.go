@@ -0,0 +1,197 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*SieveCache)(nil)
+	_ Remover        = (*SieveCache)(nil)
+)
+
+// SieveCache implements SIEVE caching policy. New
+// enteries are queued in insertion order and hits
+// only set a visited bit; enteries never move.
+// On eviction, a hand sweeps from the oldest
+// towards the newest entery clearing visited
+// bits and evicts the first one not visited since
+// its last sweep; the hand stays where it stopped.
+// Since hits don't reorder the queue, `Get` only
+// takes a read lock and lookups don't contend with
+// each other.
+type SieveCache struct {
+	mu       sync.RWMutex
+	queue    *list.List // newest at front
+	lookup   map[interface{}]*list.Element
+	hand     *list.Element
+	capacity int
+}
+
+// sieveItem is an entery of `SieveCache`.
+type sieveItem struct {
+	key     interface{}
+	value   interface{}
+	visited atomic.Bool
+}
+
+// - MARK: Alloc/Init section.
+
+// NewSieveCache allocates and initializes a new
+// `SieveCache` holding up to `capacity` enteries
+// and returns a pointer to it. Note, when
+// `capacity <= 0` holds true, capacity is set to
+// `defaultCAPACITY`.
+func NewSieveCache(capacity int) *SieveCache {
+	if capacity <= 0 {
+		capacity = defaultCAPACITY
+	}
+	return &SieveCache{
+		queue:    list.New(),
+		lookup:   make(map[interface{}]*list.Element, capacity),
+		capacity: capacity,
+	}
+}
+
+// - MARK: SieveCache section.
+
+// Set conforms to `CacheInterface`. It writes k/v
+// pair, evicting an entery when the cache is full,
+// and sets `isNew` to `true` when `key` wasn't in
+// cache.
+func (sc *SieveCache) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	var (
+		elem *list.Element
+		ok   bool
+	)
+	sc.mu.Lock()
+	if elem, ok = sc.lookup[key]; ok {
+		item := elem.Value.(*sieveItem)
+		item.value = value
+		item.visited.Store(true)
+		sc.mu.Unlock()
+		return false, nil
+	}
+	if sc.queue.Len() >= sc.capacity {
+		sc.evict()
+	}
+	sc.lookup[key] = sc.queue.PushFront(&sieveItem{key: key, value: value})
+	sc.mu.Unlock()
+	return true, nil
+}
+
+// Get conforms to `CacheInterface`. It returns
+// nil when `key` isn't in cache.
+func (sc *SieveCache) Get(key interface{}) (value interface{}, err error) {
+	sc.mu.RLock()
+	if elem, ok := sc.lookup[key]; ok {
+		item := elem.Value.(*sieveItem)
+		if !item.visited.Load() {
+			item.visited.Store(true)
+		}
+		value = item.value
+	}
+	sc.mu.RUnlock()
+	return value, nil
+}
+
+// Read conforms to `CacheInterface`. Unlike `Get`,
+// it doesn't mark the entery as visited.
+func (sc *SieveCache) Read(key interface{}) (value interface{}) {
+	sc.mu.RLock()
+	if elem, ok := sc.lookup[key]; ok {
+		value = elem.Value.(*sieveItem).value
+	}
+	sc.mu.RUnlock()
+	return value
+}
+
+// Remove conforms to `Remover`.
+func (sc *SieveCache) Remove(key interface{}) (ok bool) {
+	var (
+		elem *list.Element
+	)
+	sc.mu.Lock()
+	if elem, ok = sc.lookup[key]; ok {
+		sc.unlink(elem)
+	}
+	sc.mu.Unlock()
+	return ok
+}
+
+// Purge conforms to `CacheInterface`.
+func (sc *SieveCache) Purge() {
+	sc.mu.Lock()
+	sc.queue.Init()
+	for key, _ := range sc.lookup {
+		delete(sc.lookup, key)
+	}
+	sc.hand = nil
+	sc.mu.Unlock()
+}
+
+// Len conforms to `CacheInterface`.
+func (sc *SieveCache) Len() (n int) {
+	sc.mu.RLock()
+	n = sc.queue.Len()
+	sc.mu.RUnlock()
+	return n
+}
+
+// evict sweeps the hand to the first entery not
+// visited since the last sweep and evicts it.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (sc *SieveCache) evict() {
+	var (
+		elem *list.Element = sc.hand
+	)
+	if elem == nil {
+		elem = sc.queue.Back()
+	}
+	for item := elem.Value.(*sieveItem); item.visited.Load(); item = elem.Value.(*sieveItem) {
+		item.visited.Store(false)
+		if elem = elem.Prev(); elem == nil {
+			elem = sc.queue.Back()
+		}
+	}
+	sc.hand = elem
+	sc.unlink(elem)
+}
+
+// unlink removes `elem` from the queue along with
+// its lookup reference, moving the hand past it.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (sc *SieveCache) unlink(elem *list.Element) {
+	if sc.hand == elem {
+		sc.hand = elem.Prev()
+	}
+	delete(sc.lookup, sc.queue.Remove(elem).(*sieveItem).key)
+}
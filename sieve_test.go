@@ -0,0 +1,85 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSieveCache(t *testing.T) {
+	var (
+		sc *SieveCache = NewSieveCache(3)
+	)
+	for i := 0; i < 3; i++ {
+		if isNew, _ := sc.Set(i, i); !isNew {
+			t.Fatal("assertion failed, expected new entery.", i)
+		}
+	}
+	// visited enteries survive the sweep
+	sc.Get(0)
+	sc.Set(3, 3)
+	if sc.Read(0) != 0 || sc.Read(1) != nil || sc.Len() != 3 {
+		t.Fatal("assertion failed, expected unvisited entery to be evicted.")
+	}
+	// the hand resumes where it stopped rather than
+	// at the oldest entery, which lost its bit
+	sc.Set(4, 4)
+	if sc.Read(0) != 0 || sc.Read(2) != nil {
+		t.Fatal("assertion failed, expected hand to resume.")
+	}
+	if isNew, _ := sc.Set(0, 10); isNew || sc.Read(0) != 10 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if !sc.Remove(3) || sc.Remove(3) || sc.Len() != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	sc.Purge()
+	if sc.Len() != 0 || sc.Read(0) != nil {
+		t.Fatal("assertion failed, expected empty cache.")
+	}
+}
+
+func TestSieveCacheConcurrent(t *testing.T) {
+	var (
+		sc *SieveCache = NewSieveCache(16)
+		wg sync.WaitGroup
+	)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				sc.Set((w*i)%64, i)
+				sc.Get(i % 64)
+				if i%7 == 0 {
+					sc.Remove(i % 64)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if sc.Len() > 16 {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", 16, sc.Len())
+	}
+}
@@ -0,0 +1,93 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "math/bits"
+
+// sketchDEPTH is number of rows of `countMin`.
+const sketchDEPTH = 4
+
+// countMin is a count-min sketch estimating access
+// frequencies of key hashes with 8 bit counters.
+// Counters are halved once `limit` increments were
+// recorded so that estimates favor recent history.
+type countMin struct {
+	rows  [sketchDEPTH][]uint8
+	mask  uint64
+	adds  int
+	limit int
+}
+
+// - MARK: Alloc/Init section.
+
+// newCountMin allocates and initializes a new
+// `countMin` struct sized for about `n` distinct
+// keys and returns a pointer to it.
+func newCountMin(n int) (cm *countMin) {
+	var (
+		width int = 1 << bits.Len(uint(max(n, 16)-1))
+	)
+	cm = &countMin{mask: uint64(width - 1), limit: 10 * width}
+	for i, _ := range cm.rows {
+		cm.rows[i] = make([]uint8, width)
+	}
+	return cm
+}
+
+// - MARK: countMin section.
+
+// add records an occurrence of hash `h`.
+func (cm *countMin) add(h uint64) {
+	for i, _ := range cm.rows {
+		if c := &cm.rows[i][cm.index(h, i)]; *c < 255 {
+			*c++
+		}
+	}
+	if cm.adds++; cm.adds >= cm.limit {
+		cm.age()
+	}
+}
+
+// estimate returns the estimated frequency of `h`.
+func (cm *countMin) estimate(h uint64) (n uint8) {
+	n = 255
+	for i, _ := range cm.rows {
+		n = min(n, cm.rows[i][cm.index(h, i)])
+	}
+	return n
+}
+
+// age halves all counters.
+func (cm *countMin) age() {
+	for i, _ := range cm.rows {
+		for j, _ := range cm.rows[i] {
+			cm.rows[i][j] >>= 1
+		}
+	}
+	cm.adds /= 2
+}
+
+// index returns the counter of `h` in row `row`.
+func (cm *countMin) index(h uint64, row int) uint64 {
+	return mix64(h+uint64(row)*0x9e3779b97f4a7c15) & cm.mask
+}
@@ -0,0 +1,238 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Defaults
+const (
+	defaultPROTECTED = 0.8
+)
+
+// Segment names of `SLRU`.
+const (
+	SegmentPROBATION = "probation"
+	SegmentPROTECTED = "protected"
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*SLRU)(nil)
+	_ Remover        = (*SLRU)(nil)
+)
+
+// SLRU implements Segmented Least Recently Used
+// caching policy. New enteries enter a probationary
+// segment and are promoted to a protected segment
+// on their second hit; enteries demoted from the
+// full protected segment get another chance in
+// the probationary one. Hence hot enteries survive
+// bursts of one-hit wonders, which only churn the
+// probationary segment.
+type SLRU struct {
+	mu        sync.Mutex
+	probation *list.List
+	protected *list.List
+	lookup    map[interface{}]*list.Element
+	capacity  int
+	maxProt   int
+}
+
+// slruItem is an entery of `SLRU`.
+type slruItem struct {
+	key       interface{}
+	value     interface{}
+	protected bool
+}
+
+// - MARK: Alloc/Init section.
+
+// NewSLRU allocates and initializes a new `SLRU`
+// holding up to `capacity` enteries, of which a
+// `protected` share may be protected, and returns
+// a pointer to it. Note, when `capacity <= 0` holds
+// true, capacity is set to `defaultCAPACITY`; when
+// `protected` isn't within (0, 1), it's set to
+// `defaultPROTECTED` ( by default 0.8 ).
+func NewSLRU(capacity int, protected float64) *SLRU {
+	if capacity <= 0 {
+		capacity = defaultCAPACITY
+	}
+	if protected <= 0 || protected >= 1 {
+		protected = defaultPROTECTED
+	}
+	return &SLRU{
+		probation: list.New(),
+		protected: list.New(),
+		lookup:    make(map[interface{}]*list.Element),
+		capacity:  capacity,
+		maxProt:   max(int(float64(capacity)*protected), 1),
+	}
+}
+
+// - MARK: SLRU section.
+
+// Set conforms to `CacheInterface`. New enteries
+// enter the probationary segment; updates refresh
+// the recency of enteries within their segment.
+func (sl *SLRU) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	var (
+		elem *list.Element
+		ok   bool
+	)
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if elem, ok = sl.lookup[key]; ok {
+		item := elem.Value.(*slruItem)
+		item.value = value
+		sl.segment(item).MoveToFront(elem)
+		return false, nil
+	}
+	if len(sl.lookup) >= sl.capacity {
+		sl.evict()
+	}
+	sl.lookup[key] = sl.probation.PushFront(&slruItem{key: key, value: value})
+	return true, nil
+}
+
+// Get conforms to `CacheInterface`. A hit
+// promotes probationary enteries.
+func (sl *SLRU) Get(key interface{}) (value interface{}, err error) {
+	var (
+		elem *list.Element
+		item *slruItem
+		ok   bool
+	)
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if elem, ok = sl.lookup[key]; !ok {
+		return nil, nil
+	}
+	if item = elem.Value.(*slruItem); item.protected {
+		sl.protected.MoveToFront(elem)
+		return item.value, nil
+	}
+	sl.probation.Remove(elem)
+	item.protected = true
+	sl.lookup[key] = sl.protected.PushFront(item)
+	if sl.protected.Len() > sl.maxProt {
+		// demote the least recently used protected one
+		demoted := sl.protected.Remove(sl.protected.Back()).(*slruItem)
+		demoted.protected = false
+		sl.lookup[demoted.key] = sl.probation.PushFront(demoted)
+	}
+	return item.value, nil
+}
+
+// Read conforms to `CacheInterface`.
+func (sl *SLRU) Read(key interface{}) (value interface{}) {
+	sl.mu.Lock()
+	if elem, ok := sl.lookup[key]; ok {
+		value = elem.Value.(*slruItem).value
+	}
+	sl.mu.Unlock()
+	return value
+}
+
+// Remove conforms to `Remover`.
+func (sl *SLRU) Remove(key interface{}) (ok bool) {
+	var (
+		elem *list.Element
+	)
+	sl.mu.Lock()
+	if elem, ok = sl.lookup[key]; ok {
+		sl.segment(elem.Value.(*slruItem)).Remove(elem)
+		delete(sl.lookup, key)
+	}
+	sl.mu.Unlock()
+	return ok
+}
+
+// Purge conforms to `CacheInterface`.
+func (sl *SLRU) Purge() {
+	sl.mu.Lock()
+	sl.probation.Init()
+	sl.protected.Init()
+	for key, _ := range sl.lookup {
+		delete(sl.lookup, key)
+	}
+	sl.mu.Unlock()
+}
+
+// Len conforms to `CacheInterface`.
+func (sl *SLRU) Len() (n int) {
+	sl.mu.Lock()
+	n = len(sl.lookup)
+	sl.mu.Unlock()
+	return n
+}
+
+// Segments returns ordered key lists of the
+// probationary and protected segments, see
+// `LRU.Segments`.
+func (sl *SLRU) Segments() []Segment {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	return []Segment{
+		{Name: SegmentPROBATION, Keys: slruKeys(sl.probation)},
+		{Name: SegmentPROTECTED, Keys: slruKeys(sl.protected)},
+	}
+}
+
+// segment returns the list holding `item`.
+func (sl *SLRU) segment(item *slruItem) *list.List {
+	if item.protected {
+		return sl.protected
+	}
+	return sl.probation
+}
+
+// evict removes the least recently used
+// probationary entery, or the least recently
+// used protected one when none is probationary.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (sl *SLRU) evict() {
+	var (
+		victim *list.Element = sl.probation.Back()
+	)
+	if victim == nil {
+		victim = sl.protected.Back()
+	}
+	item := victim.Value.(*slruItem)
+	sl.segment(item).Remove(victim)
+	delete(sl.lookup, item.key)
+}
+
+// slruKeys returns keys of `l` from front to back.
+func slruKeys(l *list.List) (keys []interface{}) {
+	keys = make([]interface{}, 0, l.Len())
+	for elem := l.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Value.(*slruItem).key)
+	}
+	return keys
+}
@@ -0,0 +1,58 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSLRU(t *testing.T) {
+	var (
+		sl *SLRU = NewSLRU(4, 0.5)
+	)
+	sl.Set("a", 1)
+	sl.Set("b", 2)
+	sl.Get("a")
+	sl.Get("b")
+	// one-hit wonders only churn probation
+	for i := 0; i < 8; i++ {
+		sl.Set(i, i)
+	}
+	if sl.Read("a") != 1 || sl.Read("b") != 2 || sl.Len() != 4 {
+		t.Fatal("assertion failed, expected protected enteries to survive.")
+	}
+	// promoting a third entery demotes the oldest protected one
+	sl.Get(7)
+	want := []Segment{{SegmentPROBATION, []interface{}{"a", 6}}, {SegmentPROTECTED, []interface{}{7, "b"}}}
+	if segments := sl.Segments(); !reflect.DeepEqual(segments, want) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", segments)
+	}
+	if !sl.Remove("a") || sl.Remove("a") || sl.Len() != 3 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	sl.Purge()
+	if sl.Len() != 0 {
+		t.Fatal("assertion failed, expected empty cache.")
+	}
+}
@@ -0,0 +1,236 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"io"
+	"time"
+)
+
+// Snapshot format versions
+const (
+	// SnapshotV1 is the unversioned format of early
+	// releases: entery count followed by enteries.
+	SnapshotV1 = 1
+	// SnapshotV2 prefixes enteries with a magic and
+	// a header describing the snapshot.
+	SnapshotV2 = 2
+	// snapshotVERSION is the format written by `Export`.
+	snapshotVERSION = SnapshotV2
+	// snapshotPREALLOC caps enteries preallocated from
+	// the untrusted count of a snapshot.
+	snapshotPREALLOC = 1024
+)
+
+// snapshotMAGIC identifies versioned snapshots.
+var snapshotMAGIC = []byte("LRUSNAP\x00")
+
+// SnapshotMeta describes a snapshot written by
+// `Export`; see `SnapshotInfo`.
+type SnapshotMeta struct {
+	Version  int
+	Name     string            // see `WithName`
+	Labels   map[string]string // see `WithLabels`
+	Entries  int
+	Created  time.Time // zero for `SnapshotV1` snapshots
+	Capacity int       // capacity of the exporting cache; zero for `SnapshotV1`
+}
+
+// snapshotHeader is the persisted form of
+// `SnapshotMeta` for versioned snapshots.
+type snapshotHeader struct {
+	Version  int
+	Name     string
+	Labels   map[string]string
+	Count    int
+	Created  int64
+	Capacity int
+}
+
+// snapshotMigrations decode enteries of older
+// snapshot versions into the current form; a
+// format change must register a migration of
+// the version it supersedes here.
+var snapshotMigrations = map[int]func(dec *gob.Decoder, info SnapshotMeta) ([]snapshotEntry, error){
+	SnapshotV1: decodeSnapshotEntries,
+	SnapshotV2: decodeSnapshotEntries,
+}
+
+// snapshotEntry is the persisted form of an
+// entery. Keys and values are encoded with
+// `encoding/gob`; types other than the built-in
+// ones must be registered with `gob.Register`.
+type snapshotEntry struct {
+	Key     interface{}
+	Value   interface{}
+	Count   int
+	Expires int64
+	Version uint64
+}
+
+// - MARK: LRU section.
+
+// Export writes a snapshot of live enteries to `w`,
+// from least to most recently used, and returns the
+// number of exported enteries. Snapshots are written
+// in the latest format version; see `SnapshotInfo`.
+func (lru *LRU) Export(w io.Writer) (n int, err error) {
+	var (
+		enc     *gob.Encoder = gob.NewEncoder(w)
+		entries []snapshotEntry
+		header  snapshotHeader
+		now     int64
+		item    *LRUItem
+	)
+	lru.mu.Lock()
+	now = lru.now()
+	entries = make([]snapshotEntry, 0, lru.items.Len())
+	for elem := lru.items.Back(); elem != nil; elem = elem.Prev() {
+		if item = elem.Value.(*LRUItem); !item.expired(now) {
+			entries = append(entries, snapshotEntry{item.Key, item.Value, item.Count, item.expires, item.version})
+		}
+	}
+	header = snapshotHeader{Version: snapshotVERSION, Count: len(entries), Created: now, Capacity: lru.capacity + 1}
+	header.Name, header.Labels = lru.opts.name, copyLabels(lru.opts.labels)
+	lru.mu.Unlock()
+	if _, err = w.Write(snapshotMAGIC); err != nil {
+		return 0, err
+	}
+	if err = enc.Encode(&header); err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		if err = enc.Encode(&e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Import reads a snapshot written by `Export` from
+// `r` and writes its live enteries to the cache,
+// preserving their recency order, and returns the
+// number of imported enteries; see `LoadOrdered`.
+// Snapshots of older format versions are migrated
+// transparently; newer ones fail with `ELRUSNAPSHOT`.
+func (lru *LRU) Import(r io.Reader) (n int, err error) {
+	var (
+		dec     *gob.Decoder
+		info    SnapshotMeta
+		entries []snapshotEntry
+		loaded  []Entry
+	)
+	if dec, info, err = readSnapshotInfo(r); err != nil {
+		return 0, err
+	}
+	if entries, err = snapshotMigrations[info.Version](dec, info); err != nil {
+		return 0, err
+	}
+	loaded = make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		loaded = append(loaded, e.entry())
+	}
+	return lru.LoadOrdered(loaded)
+}
+
+// - MARK: Snapshot section.
+
+// SnapshotInfo reads the header of a snapshot
+// written by `Export` from `r` without decoding its
+// enteries, e.g. to inspect persisted caches before
+// restoring them.
+func SnapshotInfo(r io.Reader) (info SnapshotMeta, err error) {
+	_, info, err = readSnapshotInfo(r)
+	return info, err
+}
+
+// readSnapshotInfo detects the format version
+// of the snapshot in `r`, reads its header and
+// returns a decoder positioned at its first
+// entery.
+func readSnapshotInfo(r io.Reader) (dec *gob.Decoder, info SnapshotMeta, err error) {
+	var (
+		br     *bufio.Reader = bufio.NewReader(r)
+		magic  []byte
+		header snapshotHeader
+	)
+	magic, _ = br.Peek(len(snapshotMAGIC))
+	if !bytes.Equal(magic, snapshotMAGIC) {
+		// unversioned snapshots start with the count
+		dec = gob.NewDecoder(br)
+		if err = dec.Decode(&info.Entries); err != nil {
+			return nil, info, err
+		}
+		info.Version = SnapshotV1
+		return dec, info, nil
+	}
+	br.Discard(len(snapshotMAGIC))
+	dec = gob.NewDecoder(br)
+	if err = dec.Decode(&header); err != nil {
+		return nil, info, err
+	}
+	if _, ok := snapshotMigrations[header.Version]; !ok {
+		return nil, info, ELRUSNAPSHOT
+	}
+	info = SnapshotMeta{Version: header.Version, Name: header.Name, Labels: header.Labels, Entries: header.Count, Capacity: header.Capacity}
+	if header.Created != 0 {
+		info.Created = time.Unix(0, header.Created)
+	}
+	return dec, info, nil
+}
+
+// decodeSnapshotEntries decodes enteries of
+// `SnapshotV1` and `SnapshotV2` snapshots, which
+// share their entery encoding. Snapshots with
+// negative counts fail with `ELRUSNAPSHOT`.
+func decodeSnapshotEntries(dec *gob.Decoder, info SnapshotMeta) (entries []snapshotEntry, err error) {
+	if info.Entries < 0 {
+		return nil, ELRUSNAPSHOT
+	}
+	entries = make([]snapshotEntry, 0, min(info.Entries, snapshotPREALLOC))
+	for i := 0; i < info.Entries; i++ {
+		var (
+			e snapshotEntry
+		)
+		if err = dec.Decode(&e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// - MARK: snapshotEntry section.
+
+// entry converts `e` to an `Entry`.
+func (e snapshotEntry) entry() (entry Entry) {
+	entry = Entry{Key: e.Key, Value: e.Value, Count: e.Count, Version: e.Version}
+	if e.Expires != 0 {
+		entry.Expires = time.Unix(0, e.Expires)
+	}
+	return entry
+}
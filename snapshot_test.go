@@ -0,0 +1,89 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestSnapshotInfo(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8)
+		buf bytes.Buffer
+	)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.Export(&buf)
+	info, err := SnapshotInfo(bytes.NewReader(buf.Bytes()))
+	if err != nil || info.Version != SnapshotV2 || info.Entries != 2 || info.Capacity != 8 || info.Created.IsZero() {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", info, err)
+	}
+}
+
+func TestSnapshotMigrateV1(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8)
+		buf bytes.Buffer
+		enc *gob.Encoder = gob.NewEncoder(&buf)
+	)
+	// unversioned format of early releases
+	enc.Encode(2)
+	enc.Encode(&snapshotEntry{Key: "a", Value: 1, Count: 3, Version: 1})
+	enc.Encode(&snapshotEntry{Key: "b", Value: 2, Count: 5, Version: 4})
+	info, err := SnapshotInfo(bytes.NewReader(buf.Bytes()))
+	if err != nil || info.Version != SnapshotV1 || info.Entries != 2 || !info.Created.IsZero() {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", info, err)
+	}
+	if n, err := lru.Import(&buf); n != 2 || err != nil {
+		t.Fatal("assertion failed, expected migrated snapshot.", n, err)
+	}
+	if item := lru.read("b"); item.Value != 2 || item.Count != 5 || item.version != 4 {
+		t.Fatal("assertion failed, expected preserved enteries.", item)
+	}
+}
+
+func TestSnapshotUnsupportedVersion(t *testing.T) {
+	var (
+		buf bytes.Buffer
+	)
+	buf.Write(snapshotMAGIC)
+	gob.NewEncoder(&buf).Encode(&snapshotHeader{Version: snapshotVERSION + 1})
+	if _, err := NewLRU(8).Import(&buf); err != ELRUSNAPSHOT {
+		t.Fatal("assertion failed, expected unsupported version.", err)
+	}
+}
+
+func TestSnapshotCorruptCount(t *testing.T) {
+	for _, count := range []int{-1, 1 << 40} {
+		var (
+			buf bytes.Buffer
+		)
+		buf.Write(snapshotMAGIC)
+		gob.NewEncoder(&buf).Encode(&snapshotHeader{Version: snapshotVERSION, Count: count})
+		if _, err := NewLRU(8).Import(&buf); err == nil {
+			t.Fatal("assertion failed, expected corrupt snapshot to fail.", count)
+		}
+	}
+}
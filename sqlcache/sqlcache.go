@@ -0,0 +1,369 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+// Package sqlcache caches results of SQL queries
+// in a `cache.LRU`, keyed by normalized SQL and
+// arguments, and invalidates them by table.
+package sqlcache
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitghi/cache"
+)
+
+// Argument kinds
+const (
+	argNIL uint64 = iota
+	argINT
+	argFLOAT
+	argBOOL
+	argBYTES
+	argSTRING
+	argTIME
+	argNAMED
+	argOTHER
+)
+
+// Queryer is protocol definition for query
+// executors such as `*sql.DB`, `*sql.Conn`
+// and `*sql.Tx`.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Ensure interface (protocol) conformance
+var (
+	_ Queryer = (*sql.DB)(nil)
+	_ Queryer = (*sql.Tx)(nil)
+)
+
+// Rows is an immutable, fully materialized row
+// set. Its values are those produced by scanning
+// into `*interface{}` destinations; byte slices
+// are copied.
+type Rows struct {
+	Columns []string
+	Values  [][]interface{}
+}
+
+// Cache wraps query execution with a result
+// cache.
+type Cache struct {
+	mu     sync.Mutex
+	db     Queryer
+	lru    *cache.LRU
+	ttl    time.Duration
+	tags   map[string]map[string]struct{} // table -> keys
+	tables map[string][]string            // key -> tables
+	gens   map[string]uint64              // table -> invalidation generation
+	// evicted keys pending pruning, guarded by `emu`
+	// since evictions are reported with `lru` locked
+	emu     sync.Mutex
+	evicted []string
+}
+
+// - MARK: Alloc/Init section.
+
+// New allocates and initializes a new `Cache`
+// struct querying `db`, storing results in `lru`
+// for `ttl` ( forever when non-positive ) and
+// returns a pointer to it. Tags of results leaving
+// `lru` are pruned ( see `cache.LRU.OnEvict` ).
+func New(db Queryer, lru *cache.LRU, ttl time.Duration) (c *Cache) {
+	c = &Cache{
+		db:     db,
+		lru:    lru,
+		ttl:    ttl,
+		tags:   make(map[string]map[string]struct{}),
+		tables: make(map[string][]string),
+		gens:   make(map[string]uint64),
+	}
+	lru.OnEvict(c.onEvict)
+	return c
+}
+
+// - MARK: Cache section.
+
+// Query returns the cached result of `query` with
+// `args`, executing it on a miss. The result is
+// tagged with `tables` so that `Invalidate` drops
+// it once any of them changes.
+func (c *Cache) Query(ctx context.Context, tables []string, query string, args ...interface{}) (*Rows, error) {
+	return c.query(Key(query, args...), tables, func() (*sql.Rows, error) {
+		return c.db.QueryContext(ctx, query, args...)
+	})
+}
+
+// QueryStmt is like `Query` but executes the
+// prepared statement `stmt` that was prepared
+// from `query`.
+func (c *Cache) QueryStmt(ctx context.Context, stmt *sql.Stmt, tables []string, query string, args ...interface{}) (*Rows, error) {
+	return c.query(Key(query, args...), tables, func() (*sql.Rows, error) {
+		return stmt.QueryContext(ctx, args...)
+	})
+}
+
+// Invalidate drops cached results tagged with
+// any of `tables` and returns the number of
+// dropped results. It is meant to be called
+// after writes to those tables.
+func (c *Cache) Invalidate(tables ...string) (n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, table := range tables {
+		table = strings.ToLower(table)
+		for key, _ := range c.tags[table] {
+			if c.lru.Remove(key) {
+				n++
+			}
+		}
+		delete(c.tags, table)
+		c.gens[table]++
+	}
+	c.prune()
+	return n
+}
+
+// query serves `key` from the cache or materializes
+// the rows returned by `exec`. Results of queries
+// racing with an invalidation of their tables are
+// returned but not cached.
+func (c *Cache) query(key string, tables []string, exec func() (*sql.Rows, error)) (rows *Rows, err error) {
+	var (
+		gens   []uint64 = make([]uint64, len(tables))
+		cached interface{}
+		ok     bool
+	)
+	if cached, err = c.lru.Get(key); err == nil {
+		if rows, ok = cached.(*Rows); ok {
+			return rows, nil
+		}
+	}
+	c.mu.Lock()
+	for i, table := range tables {
+		gens[i] = c.gens[strings.ToLower(table)]
+	}
+	c.mu.Unlock()
+	if rows, err = materialize(exec); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, table := range tables {
+		if c.gens[strings.ToLower(table)] != gens[i] {
+			return rows, nil
+		}
+	}
+	if c.ttl > 0 {
+		_, err = c.lru.SetWithTTL(key, rows, c.ttl)
+	} else {
+		_, err = c.lru.Set(key, rows)
+	}
+	if err != nil {
+		return rows, nil
+	}
+	// earlier results of `key` may be pending
+	c.prune()
+	c.untag(key)
+	for _, table := range tables {
+		table = strings.ToLower(table)
+		if c.tags[table] == nil {
+			c.tags[table] = make(map[string]struct{})
+		}
+		c.tags[table][key] = struct{}{}
+		c.tables[key] = append(c.tables[key], table)
+	}
+	return rows, nil
+}
+
+// onEvict queues the key of a result leaving
+// the cache for pruning. Replaced results are
+// tagged again by their writer.
+func (c *Cache) onEvict(key, value interface{}, reason cache.EvictReason) {
+	if _, ok := value.(*Rows); !ok || reason == cache.EvictREPLACED {
+		return
+	}
+	if k, ok := key.(string); ok {
+		c.emu.Lock()
+		c.evicted = append(c.evicted, k)
+		c.emu.Unlock()
+	}
+}
+
+// prune drops tags of evicted results. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (c *Cache) prune() {
+	var (
+		evicted []string
+	)
+	c.emu.Lock()
+	evicted, c.evicted = c.evicted, nil
+	c.emu.Unlock()
+	for _, key := range evicted {
+		c.untag(key)
+	}
+}
+
+// untag drops tags of `key`. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (c *Cache) untag(key string) {
+	for _, table := range c.tables[key] {
+		if delete(c.tags[table], key); len(c.tags[table]) == 0 {
+			delete(c.tags, table)
+		}
+	}
+	delete(c.tables, key)
+}
+
+// materialize reads all rows returned by `exec`.
+func materialize(exec func() (*sql.Rows, error)) (rows *Rows, err error) {
+	var (
+		rs   *sql.Rows
+		dest []interface{}
+	)
+	if rs, err = exec(); err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+	rows = &Rows{}
+	if rows.Columns, err = rs.Columns(); err != nil {
+		return nil, err
+	}
+	for rs.Next() {
+		var (
+			values []interface{} = make([]interface{}, len(rows.Columns))
+		)
+		dest = dest[:0]
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err = rs.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = append([]byte(nil), b...)
+			}
+		}
+		rows.Values = append(rows.Values, values)
+	}
+	if err = rs.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Key returns the cache key of `query` with `args`.
+// Whitespace outside of quoted literals is collapsed
+// so that formatting differences map to the same key.
+// Arguments are encoded exactly, after the default
+// conversion of `database/sql` ( e.g. pointers are
+// dereferenced and `driver.Valuer`s are valued ), so
+// that only arguments sent as the same value share
+// a key.
+func Key(query string, args ...interface{}) string {
+	var (
+		kb *cache.KeyBuilder = cache.NewKeyBuilder()
+	)
+	kb.String(Normalize(query))
+	for _, arg := range args {
+		appendArg(kb, arg)
+	}
+	return kb.Key()
+}
+
+// appendArg appends `arg` tagged with its kind
+// to `kb`. Arguments the default conversion
+// rejects are left to the driver and hashed.
+func appendArg(kb *cache.KeyBuilder, arg interface{}) {
+	var (
+		v   driver.Value
+		err error
+	)
+	if named, ok := arg.(sql.NamedArg); ok {
+		kb.Uint(argNAMED).String(named.Name)
+		arg = named.Value
+	}
+	if v, err = driver.DefaultParameterConverter.ConvertValue(arg); err != nil {
+		kb.Uint(argOTHER).Hash(arg)
+		return
+	}
+	switch v := v.(type) {
+	case nil:
+		kb.Uint(argNIL)
+	case int64:
+		kb.Uint(argINT).Int(v)
+	case float64:
+		kb.Uint(argFLOAT).Uint(math.Float64bits(v))
+	case bool:
+		kb.Uint(argBOOL).Bool(v)
+	case []byte:
+		kb.Uint(argBYTES).Bytes(v)
+	case string:
+		kb.Uint(argSTRING).String(v)
+	case time.Time:
+		b, _ := v.MarshalBinary()
+		kb.Uint(argTIME).Bytes(b)
+	default:
+		kb.Uint(argOTHER).Hash(v)
+	}
+}
+
+// Normalize trims `query` and collapses runs of
+// whitespace outside of quoted literals and
+// identifiers into single spaces.
+func Normalize(query string) string {
+	var (
+		sb    strings.Builder
+		quote rune
+		space bool
+	)
+	sb.Grow(len(query))
+	for _, r := range strings.TrimSpace(query) {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			space = true
+			continue
+		}
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
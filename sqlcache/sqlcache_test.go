@@ -0,0 +1,147 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package sqlcache
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mitghi/cache"
+)
+
+// fakeDriver serves a fixed row set for every
+// query and counts executions. It's its own
+// connector so that tests don't register it.
+type fakeDriver struct {
+	queries int32
+}
+
+type fakeConn struct{ d *fakeDriver }
+type fakeStmt struct{ d *fakeDriver }
+type fakeRows struct{ n int }
+
+func (d *fakeDriver) Open(string) (driver.Conn, error)             { return fakeConn{d}, nil }
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return fakeConn{d}, nil }
+func (d *fakeDriver) Driver() driver.Driver                        { return d }
+func (c fakeConn) Prepare(string) (driver.Stmt, error)             { return fakeStmt{c.d}, nil }
+func (c fakeConn) Close() error                                    { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                       { return nil, errors.New("unsupported") }
+func (s fakeStmt) Close() error                                    { return nil }
+func (s fakeStmt) NumInput() int                                   { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error)      { return nil, errors.New("unsupported") }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	atomic.AddInt32(&s.d.queries, 1)
+	return &fakeRows{}, nil
+}
+func (r *fakeRows) Columns() []string { return []string{"id", "name"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.n == 2 {
+		return io.EOF
+	}
+	r.n++
+	dest[0], dest[1] = int64(r.n), []byte("user")
+	return nil
+}
+
+func TestNormalize(t *testing.T) {
+	if q := Normalize("  SELECT *\n\tFROM  users WHERE name = 'a  b'  "); q != "SELECT * FROM users WHERE name = 'a  b'" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", q)
+	}
+	if Key("SELECT 1 ", 1) != Key("SELECT\n1", 1) || Key("SELECT ?", 1) == Key("SELECT ?", 2) {
+		t.Fatal("assertion failed, inconsistent keys.")
+	}
+	var (
+		a, b int = 1, 1
+	)
+	if Key("SELECT ?", &a) != Key("SELECT ?", &b) || Key("SELECT ?", 1) != Key("SELECT ?", int64(1)) {
+		t.Fatal("assertion failed, expected equal keys of equal arguments.")
+	}
+	if Key("SELECT ?", "1") == Key("SELECT ?", 1) || Key("SELECT ?", "a") == Key("SELECT ?", []byte("a")) || Key("SELECT ?", nil) == Key("SELECT ?", 0) {
+		t.Fatal("assertion failed, expected distinct keys of distinct arguments.")
+	}
+	if Key("SELECT ?", sql.Named("a", 1)) == Key("SELECT ?", 1) {
+		t.Fatal("assertion failed, expected distinct keys of named arguments.")
+	}
+}
+
+func TestCacheQuery(t *testing.T) {
+	var (
+		fd  *fakeDriver = &fakeDriver{}
+		ctx             = context.Background()
+		db  *sql.DB
+		c   *Cache
+	)
+	db = sql.OpenDB(fd)
+	defer db.Close()
+	c = New(db, cache.NewLRU(8), 0)
+	for i := 0; i < 3; i++ {
+		rows, err := c.Query(ctx, []string{"users"}, "SELECT id, name FROM users WHERE id > ?", 0)
+		if err != nil || len(rows.Values) != 2 || rows.Columns[1] != "name" || string(rows.Values[1][1].([]byte)) != "user" {
+			t.Fatal("assertion failed, inconsistent state. expected equal.", rows, err)
+		}
+	}
+	if atomic.LoadInt32(&fd.queries) != 1 {
+		t.Fatal("assertion failed, expected cached result.", fd.queries)
+	}
+	if n := c.Invalidate("orders"); n != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n)
+	}
+	if n := c.Invalidate("USERS"); n != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n)
+	}
+	stmt, err := db.Prepare("SELECT id, name FROM users WHERE id > ?")
+	if err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	defer stmt.Close()
+	if _, err = c.QueryStmt(ctx, stmt, []string{"users"}, "SELECT id, name FROM users WHERE id > ?", 0); err != nil || atomic.LoadInt32(&fd.queries) != 2 {
+		t.Fatal("assertion failed, expected reload after invalidation.", err, fd.queries)
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	var (
+		fd  *fakeDriver = &fakeDriver{}
+		ctx             = context.Background()
+		db  *sql.DB     = sql.OpenDB(fd)
+		c   *Cache
+	)
+	defer db.Close()
+	c = New(db, cache.NewLRU(2), 0)
+	c.Query(ctx, []string{"users", "orders"}, "SELECT id, name FROM users")
+	c.Query(ctx, []string{"orders"}, "SELECT id, name FROM orders")
+	// evicts the first result
+	c.Query(ctx, []string{"orders"}, "SELECT id FROM orders")
+	if len(c.tags["users"]) != 0 || len(c.tags["orders"]) != 2 || len(c.tables) != 2 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", c.tags, c.tables)
+	}
+	if n := c.Invalidate("orders"); n != 2 || len(c.tags) != 0 || len(c.tables) != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", n, c.tags, c.tables)
+	}
+}
@@ -0,0 +1,245 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// Counters is the container for cache
+// statistics.
+type Counters struct {
+	Hits            uint64
+	Misses          uint64
+	Loads           uint64               // loader calls
+	Unadmitted      uint64               // loaded values not cached
+	Oversized       uint64               // loaded values too large to cache
+	StaleServed     uint64               // stale values served on load failures
+	Rejected        uint64               // loads rejected by load limits
+	GhostHits       uint64               // misses of recently evicted keys, see `WithGhost`
+	Vetoed          uint64               // evictions vetoed, see `WithOnBeforeEvict`
+	OverflowDropped uint64               // evicted enteries not handed off, see `WithOverflow`
+	Readmitted      uint64               // evicted enteries restored, see `WithReadmission`
+	Prefetches      uint64               // loads issued by `WithPrefetcher`
+	PrefetchDropped uint64               // predictions dropped while prefetchers were busy
+	Coalesced       uint64               // updates coalesced, see `WithWriteCoalescing`
+	Invalid         uint64               // values rejected, see `WithValidator`
+	Denied          uint64               // insertions denied, see `WithAdmitter`
+	Cleaned         uint64               // values released by cleanups, see `WithCleanup`
+	Evictions       [evictREASONS]uint64 // indexed by `EvictReason`
+}
+
+// Stats is a snapshot of cache statistics; the
+// embedded counters cover the whole cache while
+// `Namespaces` breaks them down per namespace of
+// composite keys ( see `Set2` ).
+type Stats struct {
+	Counters
+	Name       string            // see `WithName`
+	Labels     map[string]string // see `WithLabels`
+	Namespaces map[interface{}]Counters
+	Curve      []CurvePoint  // estimated hit ratios, see `WithMRC`
+	Shadows    []ShadowStats // see `WithShadow`
+}
+
+// - MARK: Counters section.
+
+// HitRatio returns ratio of hits to lookups or
+// zero when there were no lookups.
+func (c Counters) HitRatio() float64 {
+	if c.Hits+c.Misses == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Hits+c.Misses)
+}
+
+// Evicted returns number of enteries that left
+// the cache due to `reason`.
+func (c Counters) Evicted(reason EvictReason) uint64 {
+	if reason < 0 || reason >= evictREASONS {
+		return 0
+	}
+	return c.Evictions[reason]
+}
+
+// TotalEvictions returns number of enteries that
+// left the cache for any reason.
+func (c Counters) TotalEvictions() (n uint64) {
+	for _, v := range c.Evictions {
+		n += v
+	}
+	return n
+}
+
+// add sums `o` into `c`.
+func (c *Counters) add(o *Counters) {
+	c.Hits += o.Hits
+	c.Misses += o.Misses
+	c.Loads += o.Loads
+	c.Unadmitted += o.Unadmitted
+	c.Oversized += o.Oversized
+	c.StaleServed += o.StaleServed
+	c.Rejected += o.Rejected
+	c.GhostHits += o.GhostHits
+	c.Vetoed += o.Vetoed
+	c.OverflowDropped += o.OverflowDropped
+	c.Readmitted += o.Readmitted
+	c.Prefetches += o.Prefetches
+	c.PrefetchDropped += o.PrefetchDropped
+	c.Coalesced += o.Coalesced
+	c.Invalid += o.Invalid
+	c.Denied += o.Denied
+	c.Cleaned += o.Cleaned
+	for reason, n := range o.Evictions {
+		c.Evictions[reason] += n
+	}
+}
+
+// - MARK: LRU section.
+
+// Cost returns total cost of enteries as computed
+// by the function configured with `WithCost`.
+func (lru *LRU) Cost() (cost int64) {
+	lru.mu.Lock()
+	cost = lru.opts.totalCost
+	lru.mu.Unlock()
+	return cost
+}
+
+// Stats returns a snapshot of cache statistics.
+func (lru *LRU) Stats() (stats Stats) {
+	lru.mu.Lock()
+	stats = lru.stats()
+	lru.mu.Unlock()
+	return stats
+}
+
+// stats is the unprotected variant of `Stats`.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) stats() (stats Stats) {
+	stats.Counters = lru.opts.stats
+	if lru.opts.cleanup != nil {
+		stats.Cleaned = lru.opts.cleanup.cleaned.Load()
+	}
+	stats.Name, stats.Labels = lru.opts.name, copyLabels(lru.opts.labels)
+	stats.Namespaces = make(map[interface{}]Counters, len(lru.opts.namespaced))
+	for ns, c := range lru.opts.namespaced {
+		stats.Namespaces[ns] = *c
+	}
+	if lru.opts.mrc != nil {
+		stats.Curve = lru.opts.mrc.curve()
+	}
+	for _, s := range lru.opts.shadows {
+		stats.Shadows = append(stats.Shadows, s.stats)
+	}
+	return stats
+}
+
+// ResetStats zeroes all statistics.
+func (lru *LRU) ResetStats() {
+	lru.mu.Lock()
+	lru.opts.stats = Counters{}
+	if lru.opts.cleanup != nil {
+		lru.opts.cleanup.cleaned.Store(0)
+	}
+	lru.opts.namespaced = nil
+	if lru.opts.mrc != nil {
+		lru.opts.mrc.reset()
+	}
+	if lru.opts.keyStats != nil {
+		lru.opts.keyStats.reset()
+	}
+	for _, s := range lru.opts.shadows {
+		s.stats.Hits, s.stats.Accesses = 0, 0
+	}
+	if lru.opts.control != nil {
+		lru.opts.control.hits, lru.opts.control.misses, lru.opts.control.ghostHits = 0, 0, 0
+	}
+	lru.mu.Unlock()
+}
+
+// hit records a hit of `key`. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) hit(key interface{}) {
+	lru.opts.stats.Hits++
+	if lru.opts.keyStats != nil {
+		lru.opts.keyStats.record(hashKey(key), lru.now())
+	}
+	if lru.opts.admitter != nil {
+		lru.opts.admitter.Record(hashKey(key))
+	}
+	if lru.opts.mrc != nil {
+		lru.opts.mrc.access(key)
+	}
+	if lru.opts.shadows != nil {
+		lru.shadow(key)
+	}
+	if ck, ok := key.(compositeKey); ok {
+		lru.nsCounters(ck.ns).Hits++
+	}
+}
+
+// miss records a miss of `key`. Note, this routine
+// is not protected against concurrent accesses;
+// therefore not publicly exposed.
+func (lru *LRU) miss(key interface{}) {
+	lru.opts.stats.Misses++
+	if lru.opts.keyStats != nil {
+		lru.opts.keyStats.record(hashKey(key), lru.now())
+	}
+	if lru.opts.admitter != nil {
+		lru.opts.admitter.Record(hashKey(key))
+	}
+	if lru.opts.mrc != nil {
+		lru.opts.mrc.access(key)
+	}
+	if lru.opts.shadows != nil {
+		lru.shadow(key)
+	}
+	if lru.opts.ghost != nil && lru.opts.ghost.hit(key) {
+		lru.opts.stats.GhostHits++
+		if ck, ok := key.(compositeKey); ok {
+			lru.nsCounters(ck.ns).GhostHits++
+		}
+	}
+	if ck, ok := key.(compositeKey); ok {
+		lru.nsCounters(ck.ns).Misses++
+	}
+}
+
+// nsCounters returns counters of namespace `ns`.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) nsCounters(ns interface{}) (c *Counters) {
+	var (
+		ok bool
+	)
+	if lru.opts.namespaced == nil {
+		lru.opts.namespaced = make(map[interface{}]*Counters)
+	}
+	if c, ok = lru.opts.namespaced[ns]; !ok {
+		c = &Counters{}
+		lru.opts.namespaced[ns] = c
+	}
+	return c
+}
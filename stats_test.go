@@ -0,0 +1,105 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUStats(t *testing.T) {
+	var (
+		lru   *LRU = NewLRU(2)
+		stats Stats
+	)
+	lru.Set("a", 1)
+	lru.Get("a")
+	lru.Get("missing")
+	lru.Set2("users", 1, 1)
+	lru.Get2("users", 1)
+	lru.Get2("users", 2)
+	lru.Set2("users", 3, 3) // evicts "a"
+	lru.SetWithTTL("ttl", 1, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	lru.Get("ttl")
+	lru.PurgeNamespace("users")
+
+	stats = lru.Stats()
+	if stats.Hits != 2 || stats.Misses != 3 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Counters)
+	}
+	if stats.Evicted(EvictCAPACITY) != 2 || stats.Evicted(EvictEXPIRED) != 1 || stats.Evicted(EvictPURGED) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Evictions)
+	}
+	users := stats.Namespaces["users"]
+	if users.Hits != 1 || users.Misses != 1 || users.Evicted(EvictCAPACITY) != 1 || users.Evicted(EvictPURGED) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", users)
+	}
+	if r := users.HitRatio(); r != 0.5 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", r)
+	}
+	lru.ResetStats()
+	if stats = lru.Stats(); stats.Hits != 0 || stats.TotalEvictions() != 0 || len(stats.Namespaces) != 0 {
+		t.Fatal("assertion failed, expected zeroed statistics.")
+	}
+}
+
+func TestLRUGhostHits(t *testing.T) {
+	var (
+		lru   *LRU = NewLRU(2, WithGhost(2))
+		stats Stats
+	)
+	lru.Set(1, 1)
+	lru.Set(2, 2)
+	lru.Set(3, 3) // evicts 1
+	lru.Set(4, 4) // evicts 2
+	lru.Set(5, 5) // evicts 3, forgets 1
+	lru.Get(1)
+	lru.Get(3)
+	lru.Get(3)
+	lru.Remove(5)
+	lru.Get(5)
+	lru.Set2("ns", 1, 1)
+	lru.Set2("ns", 2, 2)
+	lru.Get2("ns", 1)
+	lru.Set2("ns", 3, 3) // evicts ("ns", 2)
+	lru.Get2("ns", 2)
+	stats = lru.Stats()
+	if stats.GhostHits != 2 || stats.Misses != 5 || stats.Namespaces["ns"].GhostHits != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.GhostHits, stats.Misses)
+	}
+}
+
+func TestLRUPurgeStats(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8)
+	)
+	lru.Set("a", 1)
+	lru.Set2("users", 1, 1)
+	// purged without callbacks
+	lru.Purge()
+	stats := lru.Stats()
+	if stats.Evicted(EvictPURGED) != 2 || stats.Namespaces["users"].Evicted(EvictPURGED) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Evictions)
+	}
+}
@@ -0,0 +1,204 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+)
+
+// streamCHUNK is size in bytes of chunks of
+// streamed values.
+const streamCHUNK = 64 << 10
+
+// Ensure interface (protocol) conformance
+var (
+	_ Releasable = (*ChunkedValue)(nil)
+)
+
+// ChunkedValue is an immutable value written by
+// `SetReader`, stored as fixed-size chunks so that
+// large payloads never need one contiguous buffer.
+// Chunks of pooled values are recycled on release;
+// readers of released values fail with
+// `ELRURELEASED` rather than reading recycled
+// memory.
+type ChunkedValue struct {
+	chunks   [][]byte
+	size     int64
+	pool     *BytePool
+	released int32
+}
+
+// chunkReader reads a `ChunkedValue`.
+type chunkReader struct {
+	cv      *ChunkedValue
+	chunk   int
+	offset  int
+	release func()
+}
+
+// - MARK: ChunkedValue section.
+
+// Size returns length of the value in bytes.
+func (cv *ChunkedValue) Size() int64 {
+	return cv.size
+}
+
+// NewReader returns a reader of the value. Readers
+// of pooled values must be obtained with `GetReader`,
+// which keeps the value referenced until the reader
+// is closed.
+func (cv *ChunkedValue) NewReader() io.ReadCloser {
+	return &chunkReader{cv: cv}
+}
+
+// Release conforms to `Releasable`. It recycles
+// chunks of pooled values.
+func (cv *ChunkedValue) Release() {
+	if cv.pool == nil || !atomic.CompareAndSwapInt32(&cv.released, 0, 1) {
+		return
+	}
+	for _, chunk := range cv.chunks {
+		cv.free(chunk)
+	}
+	cv.chunks = nil
+}
+
+// alloc returns a chunk of `n` bytes.
+func (cv *ChunkedValue) alloc(n int) []byte {
+	if cv.pool != nil {
+		return cv.pool.Get(n)
+	}
+	return make([]byte, n)
+}
+
+// free recycles `chunk` of a pooled value.
+func (cv *ChunkedValue) free(chunk []byte) {
+	if cv.pool != nil {
+		cv.pool.Put(chunk)
+	}
+}
+
+// Read conforms to `io.Reader`.
+func (cr *chunkReader) Read(p []byte) (n int, err error) {
+	if atomic.LoadInt32(&cr.cv.released) != 0 {
+		return 0, ELRURELEASED
+	}
+	for n < len(p) && cr.chunk < len(cr.cv.chunks) {
+		c := copy(p[n:], cr.cv.chunks[cr.chunk][cr.offset:])
+		n += c
+		if cr.offset += c; cr.offset == len(cr.cv.chunks[cr.chunk]) {
+			cr.chunk++
+			cr.offset = 0
+		}
+	}
+	if n == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// Close conforms to `io.Closer`; it drops the
+// reference held by the reader.
+func (cr *chunkReader) Close() error {
+	if cr.release != nil {
+		cr.release()
+	}
+	return nil
+}
+
+// - MARK: LRU section.
+
+// SetReader writes the value of `key` streamed from
+// `r`, reading exactly `size` bytes, or until EOF
+// when `size` is negative. The value is stored as a
+// `*ChunkedValue`. Nothing is written when reading
+// fails; `ELRUSHORT` is returned when `r` ends
+// before `size` bytes.
+func (lru *LRU) SetReader(key interface{}, r io.Reader, size int64) (isNew bool, err error) {
+	var (
+		cv *ChunkedValue = &ChunkedValue{pool: lru.opts.pool}
+		n  int
+	)
+	if size >= 0 {
+		r = io.LimitReader(r, size)
+	}
+	for {
+		var (
+			chunk []byte = cv.alloc(streamCHUNK)
+		)
+		n, err = io.ReadFull(r, chunk)
+		switch {
+		case n == 0:
+			cv.free(chunk)
+		case n < streamCHUNK:
+			// shrink the tail to its size
+			tail := cv.alloc(n)
+			copy(tail, chunk)
+			cv.free(chunk)
+			cv.chunks = append(cv.chunks, tail)
+		default:
+			cv.chunks = append(cv.chunks, chunk)
+		}
+		cv.size += int64(n)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			cv.Release()
+			return false, err
+		}
+	}
+	if size >= 0 && cv.size != size {
+		cv.Release()
+		return false, ELRUSHORT
+	}
+	return lru.Set(key, cv)
+}
+
+// GetReader returns a reader of the value of `key`,
+// which must be a `*ChunkedValue` or a `[]byte`. It
+// returns `ELRUMISS` when `key` is not cached. The
+// reader stays valid after the entery is evicted
+// until it is closed.
+func (lru *LRU) GetReader(key interface{}) (rc io.ReadCloser, err error) {
+	var (
+		value   interface{}
+		release func()
+	)
+	if value, release, err = lru.Acquire(key); err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil, ELRUMISS
+	case *ChunkedValue:
+		return &chunkReader{cv: v, release: release}, nil
+	case []byte:
+		return io.NopCloser(bytes.NewReader(v)), nil
+	}
+	release()
+	return nil, ELRUINVALTYPE
+}
@@ -0,0 +1,70 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestLRUSetGetReader(t *testing.T) {
+	var (
+		lru     *LRU   = NewLRU(8)
+		payload []byte = make([]byte, 3*streamCHUNK+17)
+	)
+	rand.New(rand.NewSource(1)).Read(payload)
+	if _, err := lru.SetReader("big", bytes.NewReader(payload), int64(len(payload))); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	cv := lru.Read("big").(*ChunkedValue)
+	if cv.Size() != int64(len(payload)) || len(cv.chunks) != 4 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", cv.Size(), len(cv.chunks))
+	}
+	rc, err := lru.GetReader("big")
+	if err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	lru.Remove("big")
+	got, err := io.ReadAll(rc)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatal("assertion failed, expected equal payload.", len(got), err)
+	}
+	if _, err = lru.SetReader("short", bytes.NewReader(payload[:10]), 11); err != ELRUSHORT || lru.Read("short") != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+	if _, err = lru.SetReader("eof", bytes.NewReader(payload[:10]), -1); err != nil || lru.Read("eof").(*ChunkedValue).Size() != 10 {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	if _, err = lru.GetReader("missing"); err != ELRUMISS {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", err)
+	}
+	lru.Set("bytes", []byte("abc"))
+	if rc, err = lru.GetReader("bytes"); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	if got, _ = io.ReadAll(rc); string(got) != "abc" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", string(got))
+	}
+}
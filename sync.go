@@ -0,0 +1,232 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// SyncDirection selects which side of an
+// anti-entropy sync gets repaired.
+type SyncDirection int
+
+// Sync directions
+const (
+	SyncBOTH SyncDirection = iota // repair both sides
+	SyncPULL                      // only repair the receiver
+	SyncPUSH                      // only repair the other cache
+)
+
+// SyncOptions configures `SyncWith`.
+type SyncOptions struct {
+	Direction SyncDirection
+}
+
+// SyncResult reports the outcome of `SyncWith`.
+type SyncResult struct {
+	Pulled int // enteries repaired in the receiver
+	Pushed int // enteries repaired in the other cache
+}
+
+// keyDigest summarizes an entery ( or a tombstone )
+// for exchange during anti-entropy sync.
+type keyDigest struct {
+	version uint64
+	hash    uint64
+	deleted bool
+}
+
+// syncEntry is a detached copy of an entery
+// transferred during anti-entropy sync.
+type syncEntry struct {
+	key     interface{}
+	value   interface{}
+	expires int64
+	version uint64
+}
+
+// - MARK: LRU section.
+
+// SyncWith reconciles the receiver and `other` by
+// exchanging key digests ( versions and value hashes )
+// and copying whichever side of each differing entery
+// is newer, tombstones included. Only keys in digest
+// buckets that differ are exchanged. When versions tie
+// but values differ, the value with the larger hash
+// wins so that both sides converge. It's meant to be
+// run periodically to repair drift between mirrored
+// caches; neither cache is locked for the whole sync.
+func (lru *LRU) SyncWith(other *LRU, opts SyncOptions) (res SyncResult, err error) {
+	var (
+		ld, rd  Digest = lru.Digest(), other.Digest()
+		buckets []int  = ld.Diff(&rd)
+		local   map[interface{}]keyDigest
+		remote  map[interface{}]keyDigest
+		pull    []interface{}
+		push    []interface{}
+	)
+	if len(buckets) == 0 {
+		return res, nil
+	}
+	local, remote = lru.digests(buckets), other.digests(buckets)
+	for key, ld := range local {
+		rd, ok := remote[key]
+		if !ok || newerDigest(ld, rd) {
+			push = append(push, key)
+		} else if newerDigest(rd, ld) {
+			pull = append(pull, key)
+		}
+	}
+	for key, _ := range remote {
+		if _, ok := local[key]; !ok {
+			pull = append(pull, key)
+		}
+	}
+	if opts.Direction != SyncPUSH {
+		if res.Pulled, err = lru.repair(other, pull); err != nil {
+			return res, err
+		}
+	}
+	if opts.Direction != SyncPULL {
+		if res.Pushed, err = other.repair(lru, push); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// repair copies enteries and tombstones of `keys`
+// from `src` into the receiver.
+func (lru *LRU) repair(src *LRU, keys []interface{}) (n int, err error) {
+	var (
+		entries []syncEntry
+		tombs   map[interface{}]uint64
+		applied bool
+		now     int64
+	)
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	entries, tombs = src.export(keys)
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	now = lru.now()
+	for _, e := range entries {
+		if e.expires != 0 && e.expires <= now {
+			// already expired on this clock
+			continue
+		}
+		if item := lru.read(e.key); item != nil && item.version == e.version {
+			// equal versions with diverging values; the
+			// larger hash was elected by the digests
+			lru.unlink(lru.lookup[e.key], EvictREPLACED)
+		}
+		if applied, err = lru.setVersion(e.key, e.value, e.expires, e.version); err != nil {
+			return n, err
+		}
+		if applied {
+			n++
+		}
+	}
+	for key, version := range tombs {
+		if item := lru.read(key); item != nil && item.version <= version {
+			lru.unlink(lru.lookup[key], EvictREMOVED)
+			n++
+		}
+		if lru.opts.tombstoneTTL > 0 {
+			lru.bury(key, version)
+		}
+	}
+	return n, nil
+}
+
+// export returns detached copies of enteries and
+// tombstones associated to `keys`.
+func (lru *LRU) export(keys []interface{}) (entries []syncEntry, tombs map[interface{}]uint64) {
+	var (
+		now int64 = lru.now()
+	)
+	tombs = make(map[interface{}]uint64)
+	lru.mu.Lock()
+	for _, key := range keys {
+		if item := lru.read(key); item != nil {
+			entries = append(entries, syncEntry{key, item.Value, item.expires, item.version})
+		} else if tomb, ok := lru.opts.tombstones[key]; ok && tomb.expires > now {
+			tombs[key] = tomb.version
+		}
+	}
+	lru.mu.Unlock()
+	return entries, tombs
+}
+
+// digests returns digests of live enteries and
+// tombstones whose keys fall into `buckets`.
+func (lru *LRU) digests(buckets []int) (digests map[interface{}]keyDigest) {
+	var (
+		now    int64 = lru.now()
+		item   *LRUItem
+		wanted [digestBUCKETS]bool
+	)
+	for _, b := range buckets {
+		wanted[b] = true
+	}
+	lru.mu.Lock()
+	digests = make(map[interface{}]keyDigest)
+	for key, tomb := range lru.opts.tombstones {
+		if tomb.expires > now && wanted[digestBucket(hashValue(key))] {
+			digests[key] = keyDigest{version: tomb.version, deleted: true}
+		}
+	}
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		item = elem.Value.(*LRUItem)
+		if item.expired(now) || !wanted[digestBucket(hashValue(item.Key))] {
+			continue
+		}
+		digests[item.Key] = keyDigest{version: item.version, hash: hashValue(item.Value)}
+	}
+	lru.mu.Unlock()
+	return digests
+}
+
+// newerDigest returns whether `a` supersedes `b`.
+func newerDigest(a, b keyDigest) bool {
+	switch {
+	case a.version != b.version:
+		return a.version > b.version
+	case a.deleted != b.deleted:
+		// deletions win ties
+		return a.deleted
+	}
+	return a.hash > b.hash
+}
+
+// hashValue returns 64 bit FNV-1a hash of the
+// Go-syntax representation of `value`.
+func hashValue(value interface{}) uint64 {
+	var (
+		h = fnv.New64a()
+	)
+	fmt.Fprintf(h, "%#v", value)
+	return h.Sum64()
+}
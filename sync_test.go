@@ -0,0 +1,91 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUSyncWith(t *testing.T) {
+	var (
+		a   *LRU = NewLRU(16, WithTombstones(time.Hour))
+		b   *LRU = NewLRU(16, WithTombstones(time.Hour))
+		res SyncResult
+		err error
+	)
+	a.SetVersion("only-a", "a", 1)
+	b.SetVersion("only-b", "b", 1)
+	a.SetVersion("newer-a", "a2", 2)
+	b.SetVersion("newer-a", "a1", 1)
+	a.SetVersion("deleted", "d", 1)
+	b.SetVersion("deleted", "d", 1)
+	b.Remove("deleted")
+	a.SetVersion("tie", "x", 3)
+	b.SetVersion("tie", "y", 3)
+
+	if res, err = a.SyncWith(b, SyncOptions{}); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	if res.Pulled == 0 || res.Pushed == 0 {
+		t.Fatal("assertion failed, expected repairs on both sides.", res)
+	}
+	for _, key := range []string{"only-a", "only-b", "newer-a", "tie"} {
+		if a.Read(key) == nil || a.Read(key) != b.Read(key) {
+			t.Fatal("assertion failed, expected converged enteries.", key, a.Read(key), b.Read(key))
+		}
+	}
+	if a.Read("newer-a") != "a2" || a.Read("deleted") != nil || b.Read("deleted") != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	if res, _ = a.SyncWith(b, SyncOptions{}); res.Pulled != 0 || res.Pushed != 0 {
+		t.Fatal("assertion failed, expected no repairs once converged.", res)
+	}
+}
+
+func TestLRUSyncDirection(t *testing.T) {
+	var (
+		a *LRU = NewLRU(16)
+		b *LRU = NewLRU(16)
+	)
+	a.Set("a", 1)
+	b.Set("b", 2)
+	if res, _ := a.SyncWith(b, SyncOptions{Direction: SyncPULL}); res.Pulled != 1 || res.Pushed != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", res)
+	}
+	if a.Read("b") != 2 || b.Read("a") != nil {
+		t.Fatal("assertion failed, expected one sided repair.")
+	}
+}
+
+func TestLRUSyncExpired(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Now().Add(time.Hour)}
+		a     *LRU         = NewLRU(16)
+		b     *LRU         = NewLRU(16, WithClock(clock))
+	)
+	a.SetWithTTL("k", "v", time.Minute)
+	if res, err := b.SyncWith(a, SyncOptions{}); err != nil || res.Pulled != 0 || b.Read("k") != nil {
+		t.Fatal("assertion failed, expected expired enteries to be skipped.", res, err)
+	}
+}
@@ -0,0 +1,207 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+// Defaults
+const (
+	defaultTIERSCAN = 8
+)
+
+// Tier names of the usual three tier setup
+// ( see `WithTiers` ).
+const (
+	TierCOLD = "cold"
+	TierWARM = "warm"
+	TierHOT  = "hot"
+)
+
+// Tier describes one ttl tier of `WithTiers`.
+// Enteries of a tier expire when not accessed
+// for `TTL`, are promoted into it once accessed
+// `Hits` times within one ttl window of the tier
+// below, and are demoted from it when accessed
+// less than `Hits` times within one of its own
+// ttl windows. Note, when `TTL <= 0` holds true,
+// enteries of the tier never expire nor demote.
+type Tier struct {
+	Name string
+	TTL  time.Duration
+	Hits int
+}
+
+// tierState is the per entery state of `WithTiers`.
+type tierState struct {
+	tier  int   // index into tiers
+	hits  int   // accesses within the current window
+	since int64 // start of the current window
+}
+
+// WithTiers classifies enteries written by `Set`,
+// and loaded with zero ttl, into `tiers` ordered
+// from the coldest to the hottest, e.g.
+//
+//	WithTiers(
+//		Tier{TierCOLD, time.Minute, 0},
+//		Tier{TierWARM, 10 * time.Minute, 4},
+//		Tier{TierHOT, time.Hour, 16},
+//	)
+//
+// New enteries start in the coldest tier and move
+// between tiers on access based on their access
+// frequency ( see `Tier` ), so that rarely used
+// enteries age out faster than hot ones. When the
+// cache is full, enteries of colder tiers are
+// evicted first among the least recently used
+// ones. Enteries written with an explicit ttl are
+// not tiered. Note, tiered victim selection doesn't
+// consult `WithOnBeforeEvict`.
+func WithTiers(tiers ...Tier) Option {
+	return func(lru *LRU) {
+		if len(tiers) == 0 {
+			return
+		}
+		lru.opts.tiers = append([]Tier(nil), tiers...)
+		lru.opts.tiered = make(map[interface{}]*tierState)
+	}
+}
+
+// - MARK: LRU section.
+
+// Tier returns name of the tier of the entery
+// associated to `key` and `true` when the entery
+// exists and is tiered.
+func (lru *LRU) Tier(key interface{}) (name string, ok bool) {
+	var (
+		st *tierState
+	)
+	lru.mu.Lock()
+	if lru.read(key) != nil {
+		if st, ok = lru.opts.tiered[lru.canonical(key)]; ok {
+			name = lru.opts.tiers[st.tier].Name
+		}
+	}
+	lru.mu.Unlock()
+	return name, ok
+}
+
+// tierDeadline returns the expiry of an entery
+// associated to `key` written without explicit
+// ttl, registering it in the coldest tier when
+// new. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) tierDeadline(key interface{}) int64 {
+	var (
+		st *tierState
+		ok bool
+	)
+	if lru.opts.tiers == nil {
+		return lru.idleDeadline(key)
+	}
+	key = lru.canonical(key)
+	if st, ok = lru.opts.tiered[key]; !ok {
+		st = &tierState{since: lru.now()}
+		lru.opts.tiered[key] = st
+	}
+	return lru.deadline(lru.opts.tiers[st.tier].TTL)
+}
+
+// untier removes `key` from tiers. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) untier(key interface{}) {
+	if lru.opts.tiered != nil {
+		delete(lru.opts.tiered, lru.canonical(key))
+	}
+}
+
+// touchTier accounts an access of `item`, moves
+// it between tiers as its access frequency
+// mandates and extends its expiry by the ttl of
+// its tier. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) touchTier(item *LRUItem) {
+	var (
+		st  *tierState
+		now int64
+		ok  bool
+	)
+	if st, ok = lru.opts.tiered[item.Key]; !ok {
+		return
+	}
+	now = lru.now()
+	if ttl := lru.opts.tiers[st.tier].TTL; ttl > 0 && now-st.since >= int64(ttl) {
+		if st.tier > 0 && st.hits < lru.opts.tiers[st.tier].Hits {
+			st.tier--
+		}
+		st.hits, st.since = 0, now
+	}
+	st.hits++
+	if st.tier+1 < len(lru.opts.tiers) && st.hits >= lru.opts.tiers[st.tier+1].Hits {
+		st.tier++
+		st.hits, st.since = 0, now
+	}
+	item.expires = lru.deadline(lru.opts.tiers[st.tier].TTL)
+	lru.scheduleExpiry(item)
+}
+
+// tieredVictim returns the entery of the coldest
+// tier among the `defaultTIERSCAN` least recently
+// used unpinned ones, preferring the least recently
+// used one on ties. Untiered enteries count as the
+// coldest ones. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) tieredVictim() *list.Element {
+	var (
+		victim  *list.Element
+		item    *LRUItem
+		coldest int = len(lru.opts.tiers)
+		tier    int
+		scanned int
+	)
+	for elem := lru.items.Back(); elem != nil && scanned < defaultTIERSCAN; elem = elem.Prev() {
+		item = elem.Value.(*LRUItem)
+		if _, pinned := lru.opts.pins[item.Key]; pinned {
+			continue
+		}
+		scanned++
+		tier = 0
+		if st, ok := lru.opts.tiered[item.Key]; ok {
+			tier = st.tier
+		}
+		if tier < coldest {
+			victim, coldest = elem, tier
+		}
+	}
+	if victim == nil {
+		return lru.items.Back()
+	}
+	return victim
+}
@@ -0,0 +1,109 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func newTieredLRU(clock Clock, capacity int) *LRU {
+	return NewLRU(capacity, WithClock(clock), WithTiers(
+		Tier{TierCOLD, time.Second, 0},
+		Tier{TierWARM, 10 * time.Second, 2},
+		Tier{TierHOT, time.Minute, 3},
+	))
+}
+
+func TestLRUTiersPromoteDemote(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = newTieredLRU(clock, 8)
+	)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.SetWithTTL("c", 3, time.Hour)
+	if tier, _ := lru.Tier("a"); tier != TierCOLD {
+		t.Fatalf("assertion failed, expected equal with value(%s) - got value(%s).", TierCOLD, tier)
+	}
+	if _, ok := lru.Tier("c"); ok {
+		t.Fatal("assertion failed, expected enteries with explicit ttl to be untiered.")
+	}
+	lru.Get("a")
+	lru.Get("a")
+	if tier, _ := lru.Tier("a"); tier != TierWARM {
+		t.Fatalf("assertion failed, expected equal with value(%s) - got value(%s).", TierWARM, tier)
+	}
+	// cold enteries age out faster than warm ones
+	clock.now = clock.now.Add(2 * time.Second)
+	if v, _ := lru.Get("b"); v != nil {
+		t.Fatal("assertion failed, expected cold entery to expire.")
+	}
+	if v, _ := lru.Get("a"); v != 1 {
+		t.Fatal("assertion failed, expected warm entery to survive.")
+	}
+	lru.Get("a")
+	lru.Get("a")
+	if tier, _ := lru.Tier("a"); tier != TierHOT {
+		t.Fatalf("assertion failed, expected equal with value(%s) - got value(%s).", TierHOT, tier)
+	}
+	// a single access per window demotes
+	clock.now = clock.now.Add(30 * time.Second)
+	lru.Get("a")
+	clock.now = clock.now.Add(45 * time.Second)
+	lru.Get("a")
+	if tier, _ := lru.Tier("a"); tier != TierWARM {
+		t.Fatalf("assertion failed, expected equal with value(%s) - got value(%s).", TierWARM, tier)
+	}
+	clock.now = clock.now.Add(11 * time.Second)
+	if v, _ := lru.Get("a"); v != nil {
+		t.Fatal("assertion failed, expected warm entery to expire.")
+	}
+}
+
+func TestLRUTiersEviction(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = newTieredLRU(clock, 3)
+	)
+	lru.Set("a", 1)
+	lru.Get("a")
+	lru.Get("a")
+	lru.Set("b", 2)
+	lru.Set("c", 3)
+	lru.Get("b")
+	lru.Get("c")
+	// "a" is the least recently used entery yet warm
+	lru.Set("d", 4)
+	lru.Set("e", 5)
+	if lru.Read("a") != 1 || lru.Read("b") != nil || lru.Read("c") != nil {
+		t.Fatal("assertion failed, expected colder enteries to be evicted first.")
+	}
+	if lru.Remove("a"); len(lru.opts.tiered) != lru.Len() {
+		t.Fatalf("assertion failed, expected equal with value(%d) - got value(%d).", lru.Len(), len(lru.opts.tiered))
+	}
+	lru.Purge()
+	if len(lru.opts.tiered) != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+}
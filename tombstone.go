@@ -0,0 +1,139 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// tombstone records a removed entery's version
+// until it expires.
+type tombstone struct {
+	version uint64
+	expires int64
+}
+
+// - MARK: LRU section.
+
+// SetVersion writes k/v pair at `version` as done
+// by replication layers. The write is rejected
+// ( i.e. `applied` is false ) when the cache holds
+// the same or a newer version of `key`, or a live
+// tombstone not older than `version`.
+func (lru *LRU) SetVersion(key interface{}, value interface{}, version uint64) (applied bool, err error) {
+	if err = lru.checkType(value); err != nil {
+		return false, err
+	}
+	lru.mu.Lock()
+	applied, err = lru.setVersion(key, value, 0, version)
+	lru.mu.Unlock()
+	return applied, err
+}
+
+// RemoveVersion removes `key` on behalf of a
+// replicated deletion at `version`. The removal
+// is rejected when the cache holds a newer version
+// of `key`. When tombstones are enabled, a tombstone
+// at `version` is recorded even if `key` is absent.
+func (lru *LRU) RemoveVersion(key interface{}, version uint64) (ok bool) {
+	var (
+		item *LRUItem
+	)
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	if item = lru.read(key); item != nil && item.version > version {
+		return false
+	}
+	if lru.opts.tombstoneTTL > 0 {
+		lru.bury(key, version)
+	}
+	if item == nil {
+		return false
+	}
+	lru.unlink(lru.lookup[key], EvictREMOVED)
+	return true
+}
+
+// Version returns version of the entery associated
+// to `key` and whether such entery exists.
+func (lru *LRU) Version(key interface{}) (version uint64, ok bool) {
+	var (
+		item *LRUItem
+	)
+	lru.mu.Lock()
+	if item = lru.read(key); item != nil {
+		version, ok = item.version, true
+	}
+	lru.mu.Unlock()
+	return version, ok
+}
+
+// setVersion writes k/v pair at `version` unless
+// it's superseded by the stored entery or a live
+// tombstone. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) setVersion(key interface{}, value interface{}, expires int64, version uint64) (applied bool, err error) {
+	var (
+		item *LRUItem
+		tomb tombstone
+		ok   bool
+	)
+	if tomb, ok = lru.opts.tombstones[key]; ok {
+		if tomb.expires > lru.now() && version <= tomb.version {
+			return false, nil
+		}
+		delete(lru.opts.tombstones, key)
+	}
+	if item = lru.read(key); item != nil && version <= item.version {
+		return false, nil
+	}
+	if _, err = lru.set(key, value, expires); err != nil {
+		return false, err
+	}
+	if item = lru.read(key); item == nil {
+		// written already expired
+		return false, nil
+	}
+	item.version = version
+	return true, nil
+}
+
+// bury records a tombstone for `key` at `version`
+// and sweeps expired tombstones once they outgrow
+// the cache capacity. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) bury(key interface{}, version uint64) {
+	var (
+		now int64 = lru.now()
+	)
+	if tomb, ok := lru.opts.tombstones[key]; ok && tomb.version > version {
+		version = tomb.version
+	}
+	lru.opts.tombstones[key] = tombstone{version: version, expires: now + int64(lru.opts.tombstoneTTL)}
+	if len(lru.opts.tombstones) <= lru.capacity+1 {
+		return
+	}
+	for k, tomb := range lru.opts.tombstones {
+		if tomb.expires <= now {
+			delete(lru.opts.tombstones, k)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUTombstones(t *testing.T) {
+	var (
+		lru     *LRU = NewLRU(8, WithTombstones(time.Hour))
+		applied bool
+		version uint64
+		ok      bool
+	)
+	if applied, _ = lru.SetVersion("key", "v5", 5); !applied {
+		t.Fatal("assertion failed, expected applied write.")
+	}
+	// stale replicated write
+	if applied, _ = lru.SetVersion("key", "v4", 4); applied || lru.Read("key") != "v5" {
+		t.Fatal("assertion failed, expected rejected write.")
+	}
+	if !lru.Remove("key") {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	// late write of the removed version must not resurrect it
+	if applied, _ = lru.SetVersion("key", "v5", 5); applied || lru.Read("key") != nil {
+		t.Fatal("assertion failed, expected tombstone to reject write.")
+	}
+	if applied, _ = lru.SetVersion("key", "v6", 6); !applied || lru.Read("key") != "v6" {
+		t.Fatal("assertion failed, expected newer write to be applied.")
+	}
+	// replicated delete at an older version is rejected
+	if lru.RemoveVersion("key", 5) || lru.Read("key") != "v6" {
+		t.Fatal("assertion failed, expected rejected removal.")
+	}
+	// local writes always win and bump the version
+	lru.RemoveVersion("key", 6)
+	lru.Set("key", "local")
+	if version, ok = lru.Version("key"); !ok || version != 7 {
+		t.Fatalf("assertion failed, expected equal with value(7) - got value(%d).", version)
+	}
+}
+
+func TestLRUTombstoneExpiry(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithTombstones(time.Millisecond))
+	)
+	lru.SetVersion("key", "v1", 1)
+	lru.Remove("key")
+	time.Sleep(2 * time.Millisecond)
+	if applied, _ := lru.SetVersion("key", "v1", 1); !applied {
+		t.Fatal("assertion failed, expected expired tombstone to be ignored.")
+	}
+}
+
+func TestLRUSetVersionExpired(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8)
+	)
+	lru.mu.Lock()
+	applied, err := lru.setVersion("k", "v", lru.now()-1, 1)
+	lru.mu.Unlock()
+	if applied || err != nil || lru.Read("k") != nil {
+		t.Fatal("assertion failed, expected expired write not to be applied.", applied, err)
+	}
+}
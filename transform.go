@@ -0,0 +1,338 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Error messages
+var (
+	ELRUCIPHERTEXT error = errors.New("cache(transform): ciphertext too short.")
+)
+
+// Ensure interface (protocol) conformance
+var (
+	_ CacheInterface = (*TransformCache)(nil)
+	_ Remover        = (*TransformCache)(nil)
+	_ KeyTransformer = aeadTransformer{}
+)
+
+// Transformer is protocol definition for value
+// transformations such as serialization,
+// compression and encryption.
+type Transformer interface {
+	Encode(value interface{}) (interface{}, error)
+	Decode(value interface{}) (interface{}, error)
+}
+
+// KeyTransformer is protocol definition for
+// transformers whose encoding is bound to the key
+// of the value, e.g. authenticating it as
+// additional data of an AEAD cipher. `TransformCache`
+// prefers these methods over the key-less ones.
+type KeyTransformer interface {
+	Transformer
+	EncodeKey(key interface{}, value interface{}) (interface{}, error)
+	DecodeKey(key interface{}, value interface{}) (interface{}, error)
+}
+
+// TransformCache applies a pipeline of transformers
+// to values of another cache: in order on writes and
+// in reverse order on reads.
+type TransformCache struct {
+	inner CacheInterface
+	ts    []Transformer
+}
+
+// gobTransformer serializes values with `encoding/gob`.
+type gobTransformer struct{}
+
+// flateTransformer compresses values with a
+// `FlateCodec`.
+type flateTransformer struct {
+	codec *FlateCodec
+}
+
+// aeadTransformer seals values with an AEAD cipher.
+type aeadTransformer struct {
+	aead cipher.AEAD
+}
+
+// - MARK: Alloc/Init section.
+
+// Transform allocates and initializes a new
+// `TransformCache` struct applying `ts` to values of
+// `inner` and returns a pointer to it. A typical
+// pipeline serializes, then compresses, then
+// encrypts:
+//
+//	Transform(lru, GobTransformer(), FlateTransformer(codec), AEADTransformer(aead))
+func Transform(inner CacheInterface, ts ...Transformer) *TransformCache {
+	return &TransformCache{inner: inner, ts: ts}
+}
+
+// GobTransformer returns a transformer serializing
+// values to `[]byte` with `encoding/gob`. Types other
+// than the built-in ones must be registered with
+// `gob.Register`.
+func GobTransformer() Transformer {
+	return gobTransformer{}
+}
+
+// FlateTransformer returns a transformer compressing
+// `[]byte` values with `codec`.
+func FlateTransformer(codec *FlateCodec) Transformer {
+	return flateTransformer{codec}
+}
+
+// AEADTransformer returns a transformer encrypting
+// `[]byte` values with `aead` ( e.g. AES-GCM ) under
+// a random nonce prepended to the ciphertext. Within
+// a `TransformCache`, ciphertexts are bound to their
+// key ( see `KeyTransformer` ) so that they don't
+// decrypt once moved to another key.
+func AEADTransformer(aead cipher.AEAD) Transformer {
+	return aeadTransformer{aead}
+}
+
+// - MARK: TransformCache section.
+
+// Set encodes `value` and writes it to the inner
+// cache.
+func (tc *TransformCache) Set(key interface{}, value interface{}) (isNew bool, err error) {
+	if value, err = tc.encode(key, value); err != nil {
+		return false, err
+	}
+	return tc.inner.Set(key, value)
+}
+
+// SetWithTTL is same as `Set` except that the entery
+// expires after `ttl` when the inner cache supports
+// expiry; otherwise `ttl` is ignored.
+func (tc *TransformCache) SetWithTTL(key interface{}, value interface{}, ttl time.Duration) (isNew bool, err error) {
+	if value, err = tc.encode(key, value); err != nil {
+		return false, err
+	}
+	if ts, ok := tc.inner.(ttlSetter); ok {
+		return ts.SetWithTTL(key, value, ttl)
+	}
+	return tc.inner.Set(key, value)
+}
+
+// Get fetches `key` from the inner cache and decodes
+// its value.
+func (tc *TransformCache) Get(key interface{}) (value interface{}, err error) {
+	if value, err = tc.inner.Get(key); err != nil || value == nil {
+		return value, err
+	}
+	return tc.decode(key, value)
+}
+
+// Read is same as `Get` except that it doesn't touch
+// cache counters and reports decoding failures as
+// `nil` values.
+func (tc *TransformCache) Read(key interface{}) interface{} {
+	var (
+		value interface{} = tc.inner.Read(key)
+		err   error
+	)
+	if value == nil {
+		return nil
+	}
+	if value, err = tc.decode(key, value); err != nil {
+		return nil
+	}
+	return value
+}
+
+// Remove removes `key` when the inner cache conforms
+// to `Remover`.
+func (tc *TransformCache) Remove(key interface{}) bool {
+	if r, ok := tc.inner.(Remover); ok {
+		return r.Remove(key)
+	}
+	return false
+}
+
+// Purge purges the inner cache.
+func (tc *TransformCache) Purge() {
+	tc.inner.Purge()
+}
+
+// Len returns number of items in the inner cache.
+func (tc *TransformCache) Len() int {
+	return tc.inner.Len()
+}
+
+// encode runs the pipeline forwards on `value`
+// of `key`.
+func (tc *TransformCache) encode(key interface{}, value interface{}) (interface{}, error) {
+	var (
+		err error
+	)
+	for _, t := range tc.ts {
+		if kt, ok := t.(KeyTransformer); ok {
+			value, err = kt.EncodeKey(key, value)
+		} else {
+			value, err = t.Encode(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// decode runs the pipeline backwards on `value`
+// of `key`.
+func (tc *TransformCache) decode(key interface{}, value interface{}) (interface{}, error) {
+	var (
+		err error
+	)
+	for i := len(tc.ts) - 1; i >= 0; i-- {
+		if kt, ok := tc.ts[i].(KeyTransformer); ok {
+			value, err = kt.DecodeKey(key, value)
+		} else {
+			value, err = tc.ts[i].Decode(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// - MARK: gobTransformer section.
+
+// Encode conforms to `Transformer`.
+func (gobTransformer) Encode(value interface{}) (interface{}, error) {
+	var (
+		buf bytes.Buffer
+	)
+	if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode conforms to `Transformer`.
+func (gobTransformer) Decode(value interface{}) (interface{}, error) {
+	var (
+		data []byte
+		out  interface{}
+		ok   bool
+	)
+	if data, ok = value.([]byte); !ok {
+		return nil, ELRUINVALTYPE
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// - MARK: aeadTransformer section.
+
+// Encode conforms to `Transformer`.
+func (at aeadTransformer) Encode(value interface{}) (interface{}, error) {
+	return at.seal(value, nil)
+}
+
+// Decode conforms to `Transformer`.
+func (at aeadTransformer) Decode(value interface{}) (interface{}, error) {
+	return at.open(value, nil)
+}
+
+// EncodeKey conforms to `KeyTransformer`.
+func (at aeadTransformer) EncodeKey(key interface{}, value interface{}) (interface{}, error) {
+	return at.seal(value, keyData(key))
+}
+
+// DecodeKey conforms to `KeyTransformer`.
+func (at aeadTransformer) DecodeKey(key interface{}, value interface{}) (interface{}, error) {
+	return at.open(value, keyData(key))
+}
+
+// seal encrypts `value` authenticating `ad`.
+func (at aeadTransformer) seal(value interface{}, ad []byte) (interface{}, error) {
+	var (
+		data  []byte
+		nonce []byte
+		ok    bool
+	)
+	if data, ok = value.([]byte); !ok {
+		return nil, ELRUINVALTYPE
+	}
+	nonce = make([]byte, at.aead.NonceSize(), at.aead.NonceSize()+len(data)+at.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return at.aead.Seal(nonce, nonce, data, ad), nil
+}
+
+// open decrypts `value` authenticating `ad`.
+func (at aeadTransformer) open(value interface{}, ad []byte) (interface{}, error) {
+	var (
+		data []byte
+		ok   bool
+		n    int = at.aead.NonceSize()
+	)
+	if data, ok = value.([]byte); !ok {
+		return nil, ELRUINVALTYPE
+	}
+	if len(data) < n {
+		return nil, ELRUCIPHERTEXT
+	}
+	return at.aead.Open(nil, data[:n], data[n:], ad)
+}
+
+// keyData returns additional data identifying `key`
+// by type and Go-syntax representation.
+func keyData(key interface{}) []byte {
+	return fmt.Appendf(nil, "%T:%#v", key, key)
+}
+
+// - MARK: flateTransformer section.
+
+// Encode conforms to `Transformer`.
+func (ft flateTransformer) Encode(value interface{}) (interface{}, error) {
+	if data, ok := value.([]byte); ok {
+		return ft.codec.Encode(data)
+	}
+	return nil, ELRUINVALTYPE
+}
+
+// Decode conforms to `Transformer`.
+func (ft flateTransformer) Decode(value interface{}) (interface{}, error) {
+	if data, ok := value.([]byte); ok {
+		return ft.codec.Decode(data)
+	}
+	return nil, ELRUINVALTYPE
+}
@@ -0,0 +1,73 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+	"time"
+)
+
+func TestTransformPipeline(t *testing.T) {
+	var (
+		block, _      = aes.NewCipher(bytes.Repeat([]byte{7}, 32))
+		aead, _       = cipher.NewGCM(block)
+		lru      *LRU = NewLRU(8)
+		tc       *TransformCache
+		doc      string = "a fairly repetitive document, a fairly repetitive document"
+	)
+	tc = Transform(lru, GobTransformer(), FlateTransformer(NewFlateCodec(flate.BestSpeed, nil)), AEADTransformer(aead))
+	if _, err := tc.SetWithTTL("doc", doc, time.Minute); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	stored, ok := lru.Read("doc").([]byte)
+	if !ok || bytes.Contains(stored, []byte("repetitive")) || lru.read("doc").expires == 0 {
+		t.Fatal("assertion failed, expected encrypted value with ttl.", stored)
+	}
+	if v, err := tc.Get("doc"); v != doc || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+	}
+	if v := tc.Read("doc"); v != doc {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v)
+	}
+	// moved ciphertexts fail authentication
+	lru.Set("other", stored)
+	if _, err := tc.Get("other"); err == nil {
+		t.Fatal("assertion failed, expected ciphertext bound to its key.")
+	}
+	lru.Remove("other")
+	// tampered ciphertexts fail authentication
+	stored[len(stored)-1] ^= 1
+	if _, err := tc.Get("doc"); err == nil || tc.Read("doc") != nil {
+		t.Fatal("assertion failed, expected authentication failure.")
+	}
+	if v, err := tc.Get("missing"); v != nil || err != nil {
+		t.Fatal("assertion failed, expected miss.", v, err)
+	}
+	if !tc.Remove("doc") || tc.Len() != 0 {
+		t.Fatal("assertion failed, expected removed entery.")
+	}
+}
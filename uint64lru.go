@@ -0,0 +1,203 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Uint64LRU implements Least Recently Used
+// caching policy specialized for `uint64`
+// keys. Keys are indexed without boxing,
+// which suits callers that already hash
+// their keys ( see `KeyBuilder.Sum64` ).
+type Uint64LRU struct {
+	// size: 64 bytes
+	mu              *sync.RWMutex            // 8 bytes
+	items           *list.List               // 8 bytes
+	lookup          map[uint64]*list.Element // 8 bytes
+	capacity, count int                      // 16 bytes
+	_               [3]uint64                // 24 bytes
+}
+
+// Uint64LRUItem is the container for
+// individual `Uint64LRU` cache enteries.
+type Uint64LRUItem struct {
+	// size: 64 bytes
+	Key    uint64      // 8 bytes
+	Value  interface{} // 16 bytes
+	Count  int         // 8 bytes
+	digest uint64      // 8 bytes, see `HashedLRU`
+	_      [3]uint64   // 24 bytes
+}
+
+// - MARK: Alloc/Init section.
+
+// NewUint64LRU allocates and initializes a new
+// `Uint64LRU` struct and returns a pointer to it.
+// Note, when `capacity <= 0` holds true, capacity
+// is set to `defaultCAPACITY`.
+func NewUint64LRU(capacity int) (lru *Uint64LRU) {
+	lru = &Uint64LRU{
+		mu:       &sync.RWMutex{},
+		items:    list.New(),
+		lookup:   make(map[uint64]*list.Element),
+		capacity: capacity - 1,
+		count:    0,
+	}
+	// ensure validity of capacity
+	if lru.capacity <= 0 {
+		lru.capacity = defaultCAPACITY
+	}
+	return lru
+}
+
+// - MARK: Uint64LRU section.
+
+// Set writes k/v pair in the cache and evicts
+// old enteries when needed. It sets `isNew` to
+// `true` when the given k/v pair are allocated.
+func (lru *Uint64LRU) Set(key uint64, value interface{}) (isNew bool) {
+	lru.mu.Lock()
+	isNew = lru.set(key, value)
+	lru.mu.Unlock()
+	return isNew
+}
+
+// Get fetches `key` from cache and returns its
+// value along with a boolean indicating whether
+// it was found.
+func (lru *Uint64LRU) Get(key uint64) (value interface{}, ok bool) {
+	var (
+		elem *list.Element
+		item *Uint64LRUItem
+	)
+	lru.mu.Lock()
+	lru.count++
+	if elem, ok = lru.lookup[key]; ok {
+		item = elem.Value.(*Uint64LRUItem)
+		item.Count++
+		value = item.Value
+		lru.items.MoveToFront(elem)
+	}
+	lru.mu.Unlock()
+	return value, ok
+}
+
+// Read only reads the given item with `key`
+// without incrementing cache counter or
+// triggering eviction policies.
+func (lru *Uint64LRU) Read(key uint64) (value interface{}, ok bool) {
+	var (
+		elem *list.Element
+	)
+	lru.mu.Lock()
+	if elem, ok = lru.lookup[key]; ok {
+		value = elem.Value.(*Uint64LRUItem).Value
+	}
+	lru.mu.Unlock()
+	return value, ok
+}
+
+// Remove removes the given item with `key` from
+// cache and returns `true` when succesfull.
+func (lru *Uint64LRU) Remove(key uint64) (ok bool) {
+	var (
+		elem *list.Element
+	)
+	lru.mu.Lock()
+	if elem, ok = lru.lookup[key]; ok {
+		lru.unlink(elem)
+	}
+	lru.mu.Unlock()
+	return ok
+}
+
+// SetKey is same as `Set` except that the key
+// is derived from the hash of `kb`.
+func (lru *Uint64LRU) SetKey(kb *KeyBuilder, value interface{}) (isNew bool) {
+	return lru.Set(kb.Sum64(), value)
+}
+
+// GetKey is same as `Get` except that the key
+// is derived from the hash of `kb`.
+func (lru *Uint64LRU) GetKey(kb *KeyBuilder) (value interface{}, ok bool) {
+	return lru.Get(kb.Sum64())
+}
+
+// Purge removes all enteries and restarts the cache.
+func (lru *Uint64LRU) Purge() {
+	lru.mu.Lock()
+	lru.items = lru.items.Init()
+	lru.lookup = make(map[uint64]*list.Element)
+	lru.count = 0
+	lru.mu.Unlock()
+}
+
+// Len returns number of items in cache.
+func (lru *Uint64LRU) Len() (l int) {
+	lru.mu.Lock()
+	l = lru.items.Len()
+	lru.mu.Unlock()
+	return l
+}
+
+// set writes k/v pair in the cache and triggers
+// eviction policy when neccessary. Note, this
+// routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *Uint64LRU) set(key uint64, value interface{}) (isNew bool) {
+	lru.count++
+	var (
+		elem *list.Element
+		item *Uint64LRUItem
+		ok   bool
+	)
+	if elem, ok = lru.lookup[key]; ok {
+		item = elem.Value.(*Uint64LRUItem)
+		item.Count++
+		item.Value = value
+		lru.items.MoveToFront(elem)
+		return false
+	}
+	if lru.items.Len() > lru.capacity {
+		lru.unlink(lru.items.Back())
+	}
+	item = &Uint64LRUItem{Key: key, Value: value, Count: lru.count}
+	lru.lookup[key] = lru.items.PushFront(item)
+	return true
+}
+
+// unlink removes `elem` from the list along with
+// its lookup reference. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *Uint64LRU) unlink(elem *list.Element) {
+	var (
+		item *Uint64LRUItem = lru.items.Remove(elem).(*Uint64LRUItem)
+	)
+	delete(lru.lookup, item.Key)
+	// remove references to help GC
+	item.Value = nil
+}
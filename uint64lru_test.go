@@ -0,0 +1,62 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestUint64LRU(t *testing.T) {
+	const (
+		defCAPACITY int = 8
+		iters       int = 10
+	)
+	var (
+		lru   *Uint64LRU = NewUint64LRU(defCAPACITY)
+		value interface{}
+		ok    bool
+	)
+	for i := 0; i < iters; i++ {
+		if !lru.Set(uint64(i), i) {
+			t.Fatal("inconsistent state, expected equal.")
+		}
+	}
+	if l := lru.Len(); l != defCAPACITY {
+		t.Fatalf("assertion failed; inconsistent state, expected equal with value(%d) - got value(%d).", defCAPACITY, l)
+	}
+	if _, ok = lru.Read(0); ok {
+		t.Fatal("assertion failed, expected evicted entery.")
+	}
+	if value, ok = lru.Get(9); !ok || value != 9 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	if !lru.Remove(9) || lru.Len() != defCAPACITY-1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	lru.SetKey(NewKeyBuilder().String("user").Int(1), "user_1")
+	if value, ok = lru.GetKey(NewKeyBuilder().String("user").Int(1)); !ok || value != "user_1" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	lru.Purge()
+	if lru.Len() != 0 || len(lru.lookup) != 0 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+}
@@ -0,0 +1,126 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// - MARK: LRU section.
+
+// Validate checks internal consistency of the
+// cache: every lookup entery points to a live
+// list element, aliases resolve to registered
+// canonical enteries, list length equals number
+// of canonical lookup enteries, costs sum up to
+// the tracked total and secondary indexes agree
+// with the list. It returns an
+// error wrapping `ELRUCORRUPT` describing the
+// first violation found.
+func (lru *LRU) Validate() (err error) {
+	lru.mu.Lock()
+	err = lru.validate()
+	lru.mu.Unlock()
+	return err
+}
+
+// validate is the unprotected variant of `Validate`.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) validate() error {
+	var (
+		live      map[*list.Element]struct{} = make(map[*list.Element]struct{}, lru.items.Len())
+		canonical int
+		cost      int64
+		item      *LRUItem
+		ok        bool
+	)
+	for elem := lru.items.Front(); elem != nil; elem = elem.Next() {
+		if item, ok = elem.Value.(*LRUItem); !ok || item == nil {
+			return fmt.Errorf("%w: list element holds %T", ELRUCORRUPT, elem.Value)
+		}
+		if lru.lookup[item.Key] != elem {
+			return fmt.Errorf("%w: key(%v) is not indexed by its element", ELRUCORRUPT, item.Key)
+		}
+		live[elem] = struct{}{}
+		cost += item.cost
+	}
+	if cost != lru.opts.totalCost {
+		return fmt.Errorf("%w: cost sum(%d) differs from total cost(%d)", ELRUCORRUPT, cost, lru.opts.totalCost)
+	}
+	if l := lru.items.Len(); l > lru.capacity+1 {
+		return fmt.Errorf("%w: length(%d) exceeds capacity(%d)", ELRUCORRUPT, l, lru.capacity+1)
+	}
+	for key, elem := range lru.lookup {
+		if _, ok = live[elem]; !ok {
+			return fmt.Errorf("%w: key(%v) points to a dead element", ELRUCORRUPT, key)
+		}
+		item = elem.Value.(*LRUItem)
+		if item.Key == key {
+			canonical++
+			continue
+		}
+		if _, ok = lru.opts.aliases[item.Key][key]; !ok {
+			return fmt.Errorf("%w: alias(%v) of key(%v) is not registered", ELRUCORRUPT, key, item.Key)
+		}
+	}
+	if canonical != lru.items.Len() {
+		return fmt.Errorf("%w: list length(%d) differs from lookup length(%d)", ELRUCORRUPT, lru.items.Len(), canonical)
+	}
+	for ns, ids := range lru.opts.namespaces {
+		for id, elem := range ids {
+			if lru.lookup[compositeKey{ns, id}] != elem {
+				return fmt.Errorf("%w: namespace(%v) indexes dead id(%v)", ELRUCORRUPT, ns, id)
+			}
+		}
+	}
+	for id, keys := range lru.opts.reverse {
+		for key, _ := range keys {
+			elem, ok := lru.lookup[key]
+			if !ok || lru.opts.identity(elem.Value.(*LRUItem).Value) != id {
+				return fmt.Errorf("%w: reverse index maps identity(%v) to stale key(%v)", ELRUCORRUPT, id, key)
+			}
+		}
+	}
+	return nil
+}
+
+// debugValidate runs `validate` every configured
+// number of writes when debug validation is
+// enabled. Note, this routine is not protected
+// against concurrent accesses; therefore not
+// publicly exposed.
+func (lru *LRU) debugValidate() {
+	if lru.opts.validateEvery <= 0 {
+		return
+	}
+	if lru.opts.validateN++; lru.opts.validateN < lru.opts.validateEvery {
+		return
+	}
+	lru.opts.validateN = 0
+	if err := lru.validate(); err != nil && lru.opts.onInvalid != nil {
+		lru.opts.onInvalid(err)
+	}
+}
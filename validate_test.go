@@ -0,0 +1,56 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLRUValidate(t *testing.T) {
+	var (
+		lru     *LRU = NewLRU(8, WithIdentity(func(v interface{}) interface{} { return v }))
+		invalid int
+	)
+	for i := 0; i < 12; i++ {
+		lru.Set(i, i%3)
+		lru.Set2("ns", i, i)
+	}
+	lru.Alias("alias", 11)
+	lru.Remove(10)
+	if err := lru.Validate(); err != nil {
+		t.Fatal("assertion failed, unexpected error.", err)
+	}
+	// corrupt the lookup
+	lru.lookup["ghost"] = lru.items.Front()
+	if err := lru.Validate(); !errors.Is(err, ELRUCORRUPT) {
+		t.Fatal("assertion failed, expected error.", err)
+	}
+	lru = NewLRU(8, WithValidation(1, func(error) { invalid++ }))
+	lru.Set("key", "value")
+	delete(lru.lookup, "key")
+	lru.Set("other", "value")
+	if invalid != 1 {
+		t.Fatal("assertion failed, expected reported violation.", invalid)
+	}
+}
@@ -0,0 +1,71 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+// Validator checks values of `key` before they are
+// cached ( see `WithValidator` ) and returns a non
+// nil error to reject malformed ones.
+type Validator func(key, value interface{}) error
+
+// RejectNil is a `Validator` rejecting nil values
+// with `ELRUNILVALUE`.
+var RejectNil Validator = func(key, value interface{}) error {
+	if value == nil {
+		return ELRUNILVALUE
+	}
+	return nil
+}
+
+// - MARK: Alloc/Init section.
+
+// WithValidator makes the cache check values loaded
+// in read-through mode with `v` before caching them,
+// so that a misbehaving backend can't poison the
+// cache; rejected values are treated as load
+// failures with the error of `v`. When `onGet` is
+// true, cached values are checked on every hit as
+// well and rejected ones are removed and treated as
+// misses. Rejections are counted as `Invalid`. Note,
+// `v` runs with the cache locked on hits; hence it
+// must not call back into the cache.
+func WithValidator(v Validator, onGet bool) Option {
+	return func(lru *LRU) {
+		lru.opts.validator, lru.opts.validateGets = v, onGet
+	}
+}
+
+// - MARK: LRU section.
+
+// valid returns whether the cached `item` passes
+// the configured validator, removing it otherwise.
+// Note, this routine is not protected against
+// concurrent accesses; therefore not publicly
+// exposed.
+func (lru *LRU) valid(item *LRUItem) bool {
+	if lru.opts.validator(item.Key, item.Value) == nil {
+		return true
+	}
+	lru.opts.stats.Invalid++
+	lru.unlink(lru.lookup[item.Key], EvictREMOVED)
+	return false
+}
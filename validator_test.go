@@ -0,0 +1,63 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLRUValidator(t *testing.T) {
+	var (
+		values      = map[interface{}]interface{}{"a": 1, "b": nil, "c": "bad"}
+		lru    *LRU = NewLRU(8, WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			return values[key], nil
+		})), WithValidator(func(key, value interface{}) error {
+			if err := RejectNil(key, value); err != nil {
+				return err
+			}
+			if _, ok := value.(int); !ok {
+				return errors.New("not an int")
+			}
+			return nil
+		}, true))
+	)
+	if v, err := lru.Get("a"); v != 1 || err != nil {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", v, err)
+	}
+	if _, err := lru.Get("b"); err != ELRUNILVALUE || lru.Read("b") != nil {
+		t.Fatal("assertion failed, expected nil value to be rejected.", err)
+	}
+	if _, err := lru.Get("c"); err == nil || lru.Read("c") != nil {
+		t.Fatal("assertion failed, expected malformed value to be rejected.", err)
+	}
+	// cached values are checked on hits; the
+	// reload is rejected as well
+	lru.Set("d", "bad")
+	if v, _ := lru.Get("d"); v != nil || lru.Read("d") != nil {
+		t.Fatal("assertion failed, expected malformed entery to be removed.", v)
+	}
+	if n := lru.Stats().Invalid; n != 4 {
+		t.Fatalf("assertion failed, expected equal with value(4) - got value(%d).", n)
+	}
+}
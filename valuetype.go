@@ -0,0 +1,69 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// - MARK: Alloc/Init section.
+
+// WithValueType restricts values of the cache to
+// those assignable to `t`, e.g. `reflect.TypeFor[*User]()`.
+// Writes of other values fail with an error matching
+// `ErrInvalidValueType` before touching the cache,
+// and loaded values of other types are treated as
+// load failures and counted as `Invalid`. Nil values
+// are accepted when `t` is nilable ( e.g. a pointer
+// or an interface type ).
+func WithValueType(t reflect.Type) Option {
+	return func(lru *LRU) {
+		lru.opts.valueType = t
+	}
+}
+
+// - MARK: LRU section.
+
+// checkType returns an error matching
+// `ErrInvalidValueType` unless `value` conforms to
+// the type configured by `WithValueType`.
+func (lru *LRU) checkType(value interface{}) error {
+	var (
+		t reflect.Type = lru.opts.valueType
+	)
+	if t == nil {
+		return nil
+	}
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+			return nil
+		}
+		return fmt.Errorf("%w: nil is not %v", ErrInvalidValueType, t)
+	}
+	if !reflect.TypeOf(value).AssignableTo(t) {
+		return fmt.Errorf("%w: %T is not %v", ErrInvalidValueType, value, t)
+	}
+	return nil
+}
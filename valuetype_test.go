@@ -0,0 +1,69 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLRUValueType(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithValueType(reflect.TypeFor[int]()))
+	)
+	if _, err := lru.Set("a", 1); err != nil {
+		t.Fatal("assertion failed, expected nil error.", err)
+	}
+	for _, value := range []interface{}{"1", nil, int64(1)} {
+		if _, err := lru.Set("b", value); !errors.Is(err, ErrInvalidValueType) {
+			t.Fatal("assertion failed, expected invalid value type.", value, err)
+		}
+	}
+	if _, err := lru.SetVersion("b", "1", 2); !errors.Is(err, ErrInvalidValueType) || lru.Len() != 1 {
+		t.Fatal("assertion failed, expected invalid value type.", err)
+	}
+	// interface types accept implementations and nil
+	lru = NewLRU(8, WithValueType(reflect.TypeFor[fmt.Stringer]()))
+	if _, err := lru.Set("a", reflect.TypeFor[int]()); err != nil {
+		t.Fatal("assertion failed, expected nil error.", err)
+	}
+	if _, err := lru.Set("b", nil); err != nil {
+		t.Fatal("assertion failed, expected nil error.", err)
+	}
+}
+
+func TestLRUValueTypeLoader(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(8, WithValueType(reflect.TypeFor[int]()), WithLoader(LoaderFunc(func(key interface{}) (interface{}, error) {
+			return "1", nil
+		})))
+	)
+	if _, err := lru.Get("a"); !errors.Is(err, ErrInvalidValueType) || lru.Read("a") != nil {
+		t.Fatal("assertion failed, expected loaded value to be rejected.", err)
+	}
+	if n := lru.Stats().Invalid; n != 1 {
+		t.Fatalf("assertion failed, expected equal with value(1) - got value(%d).", n)
+	}
+}
@@ -0,0 +1,77 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "container/list"
+
+// Defaults
+const (
+	defaultMAXVETOES = 8
+)
+
+// - MARK: LRU section.
+
+// victim returns the least recently used entery
+// that is neither pinned ( see `Pin` ) nor vetoed by
+// the configured `WithOnBeforeEvict` hook, falling
+// back to the least recently used unpinned one when
+// all candidates are vetoed or the veto cap is
+// reached, and to the least recently used one when
+// all enteries are pinned. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) victim() *list.Element {
+	var (
+		back     *list.Element = lru.items.Back()
+		fallback *list.Element
+		item     *LRUItem
+		vetoes   int
+	)
+	for elem := back; elem != nil; elem = elem.Prev() {
+		if elem == lru.items.Front() && elem != back {
+			break
+		}
+		item = elem.Value.(*LRUItem)
+		if _, pinned := lru.opts.pins[item.Key]; pinned {
+			continue
+		}
+		if fallback == nil {
+			fallback = elem
+		}
+		if lru.opts.beforeEvict == nil {
+			return elem
+		}
+		if vetoes >= lru.opts.maxVetoes {
+			break
+		}
+		if lru.opts.beforeEvict(item.Key, item.Value) {
+			return elem
+		}
+		lru.opts.stats.Vetoed++
+		vetoes++
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return back
+}
@@ -0,0 +1,61 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "testing"
+
+func TestLRUOnBeforeEvict(t *testing.T) {
+	var (
+		busy map[interface{}]bool = map[interface{}]bool{0: true, 1: true}
+		lru  *LRU                 = NewLRU(4, WithOnBeforeEvict(func(key, value interface{}) bool {
+			return !busy[key]
+		}, 0))
+	)
+	for i := 0; i < 4; i++ {
+		lru.Set(i, i)
+	}
+	lru.Set(4, 4)
+	if lru.Read(0) != 0 || lru.Read(1) != 1 || lru.Read(2) != nil {
+		t.Fatal("assertion failed, expected vetoed enteries to be spared.")
+	}
+	if stats := lru.Stats(); stats.Vetoed != 2 || stats.Evicted(EvictCAPACITY) != 1 {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", stats.Vetoed)
+	}
+}
+
+func TestLRUOnBeforeEvictCap(t *testing.T) {
+	var (
+		lru *LRU = NewLRU(4, WithOnBeforeEvict(func(key, value interface{}) bool {
+			return false
+		}, 2))
+	)
+	for i := 0; i < 5; i++ {
+		lru.Set(i, i)
+	}
+	if lru.Len() != 4 || lru.Read(0) != nil {
+		t.Fatal("assertion failed, expected oldest entery evicted after veto cap.")
+	}
+	if stats := lru.Stats(); stats.Vetoed != 2 {
+		t.Fatalf("assertion failed, expected equal with value(2) - got value(%d).", stats.Vetoed)
+	}
+}
@@ -0,0 +1,117 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import "time"
+
+// CacheState is the lifecycle state of a cache.
+type CacheState int
+
+// Cache states
+const (
+	StateREADY   CacheState = iota // serving normally
+	StateWARMING                   // filling after start
+)
+
+// WarmupMode selects how read-through misses are
+// handled while warming.
+type WarmupMode int
+
+// Warmup modes
+const (
+	WarmPASSTHROUGH WarmupMode = iota // load ignoring load limits
+	WarmFAILFAST                      // fail with `ELRUWARMING`
+	WarmDEFAULT                       // serve `Warmup.Default` without loading
+)
+
+// Warmup configures `WithWarmup`. The cache becomes
+// ready once any configured condition holds or
+// `MarkReady` is called.
+type Warmup struct {
+	Mode     WarmupMode
+	Default  func(key interface{}) interface{} // value served in `WarmDEFAULT` mode
+	Fill     float64                           // ready once filled to this share of capacity
+	Duration time.Duration                     // ready once elapsed since construction
+}
+
+// warmupState tracks progress of warm-up.
+type warmupState struct {
+	Warmup
+	since int64
+	ready bool
+}
+
+// - MARK: CacheState section.
+
+// String returns the name of `state`.
+func (state CacheState) String() string {
+	switch state {
+	case StateREADY:
+		return "ready"
+	case StateWARMING:
+		return "warming"
+	}
+	return "unknown"
+}
+
+// - MARK: LRU section.
+
+// State returns the lifecycle state of the cache.
+func (lru *LRU) State() (state CacheState) {
+	lru.mu.Lock()
+	if lru.opts.warmup != nil && lru.warming() {
+		state = StateWARMING
+	}
+	lru.mu.Unlock()
+	return state
+}
+
+// MarkReady ends warm-up.
+func (lru *LRU) MarkReady() {
+	lru.mu.Lock()
+	if lru.opts.warmup != nil {
+		lru.opts.warmup.ready = true
+	}
+	lru.mu.Unlock()
+}
+
+// warming evaluates warm-up conditions and returns
+// whether the cache is still warming. Once ready,
+// it never warms again. Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) warming() bool {
+	var (
+		w *warmupState = lru.opts.warmup
+	)
+	if w.ready {
+		return false
+	}
+	if w.Fill > 0 && float64(lru.items.Len()) >= w.Fill*float64(lru.capacity+1) {
+		w.ready = true
+	}
+	if w.Duration > 0 && lru.now()-w.since >= int64(w.Duration) {
+		w.ready = true
+	}
+	return !w.ready
+}
@@ -0,0 +1,76 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUWarmup(t *testing.T) {
+	var (
+		clock  *manualClock = &manualClock{time.Unix(0, 0)}
+		loader Loader       = LoaderFunc(func(key interface{}) (interface{}, error) {
+			return "loaded", nil
+		})
+		lru   *LRU
+		value interface{}
+		err   error
+	)
+	lru = NewLRU(4, WithClock(clock), WithLoader(loader), WithWarmup(Warmup{Mode: WarmFAILFAST, Duration: time.Minute}))
+	if lru.State() != StateWARMING {
+		t.Fatal("assertion failed, expected warming state.")
+	}
+	if _, err = lru.Get("key"); err != ELRUWARMING {
+		t.Fatal("assertion failed, expected error.", err)
+	}
+	lru.Set("warm", "value")
+	if value, _ = lru.Get("warm"); value != "value" {
+		t.Fatal("assertion failed, expected hits while warming.", value)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	if lru.State() != StateREADY {
+		t.Fatal("assertion failed, expected ready state.")
+	}
+	if value, _ = lru.Get("key"); value != "loaded" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+
+	lru = NewLRU(4, WithLoader(loader), WithWarmup(Warmup{Mode: WarmDEFAULT, Fill: 0.5, Default: func(interface{}) interface{} {
+		return "default"
+	}}))
+	if value, _ = lru.Get("key"); value != "default" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	if value, _ = lru.Get("key"); value != "loaded" || lru.State() != StateREADY {
+		t.Fatal("assertion failed, expected ready once filled.", value)
+	}
+
+	lru = NewLRU(4, WithLoader(loader), WithWarmup(Warmup{Mode: WarmFAILFAST}))
+	lru.MarkReady()
+	if value, _ = lru.Get("key"); value != "loaded" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", value)
+	}
+}
@@ -0,0 +1,327 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+// Defaults
+const (
+	wheelBITS   = 6
+	wheelSLOTS  = 1 << wheelBITS
+	wheelLEVELS = 4
+	wheelSPAN   = 1 << (wheelBITS * wheelLEVELS) // ticks covered by all levels
+)
+
+// TimingWheel is a hierarchical timing wheel
+// scheduling expirations of keys in O(1). Each of
+// its levels is a ring of 64 slots, where a slot of
+// level `l` spans 64^l ticks; timers cascade to
+// lower levels as their deadline approaches. Timers
+// fire within one tick after their deadline.
+// Note, `TimingWheel` is not safe for concurrent
+// use.
+type TimingWheel struct {
+	tick    int64 // tick length in nanoseconds
+	cursor  int64 // current tick
+	started bool
+	slots   [wheelLEVELS][wheelSLOTS]*list.List
+	counts  [wheelLEVELS]int // timers per level
+	timers  map[interface{}]*wheelTimer
+}
+
+// wheelTimer is a scheduled expiration.
+type wheelTimer struct {
+	key   interface{}
+	at    int64 // deadline in unix nanoseconds
+	level int
+	slot  *list.List
+	elem  *list.Element
+}
+
+// - MARK: Alloc/Init section.
+
+// NewTimingWheel allocates and initializes a new
+// `TimingWheel` with ticks of `tick` and returns a
+// pointer to it. Note, when `tick <= 0` holds true,
+// it's set to one millisecond.
+func NewTimingWheel(tick time.Duration) *TimingWheel {
+	if tick <= 0 {
+		tick = time.Millisecond
+	}
+	return &TimingWheel{tick: int64(tick), timers: make(map[interface{}]*wheelTimer)}
+}
+
+// WithTimingWheel schedules expirations of enteries
+// in a `TimingWheel` with ticks of `tick`, so that
+// `ReapExpired` ( and the janitor, see `WithJanitor` )
+// only visits enteries whose deadline passed rather
+// than scanning the whole cache.
+func WithTimingWheel(tick time.Duration) Option {
+	return func(lru *LRU) {
+		lru.opts.wheel = NewTimingWheel(tick)
+	}
+}
+
+// - MARK: TimingWheel section.
+
+// Schedule (re)schedules expiration of `key` at `at`.
+// Timers scheduled before the first `Advance` are
+// placed once it tells the current time.
+func (tw *TimingWheel) Schedule(key interface{}, at time.Time) {
+	tw.schedule(key, at.UnixNano(), false)
+}
+
+// Cancel cancels expiration of `key` and returns
+// `true` when it was scheduled.
+func (tw *TimingWheel) Cancel(key interface{}) bool {
+	return tw.cancel(key)
+}
+
+// Advance moves the wheel forward to `now` and
+// returns keys whose expiration is due, which are
+// no longer scheduled afterwards.
+func (tw *TimingWheel) Advance(now time.Time) (due []interface{}) {
+	return tw.advance(now.UnixNano())
+}
+
+// Len returns number of scheduled expirations.
+func (tw *TimingWheel) Len() int {
+	return len(tw.timers)
+}
+
+// schedule schedules expiration of `key` at `at`,
+// replacing its current timer unless `earlier` is
+// true and the current one is due no later.
+func (tw *TimingWheel) schedule(key interface{}, at int64, earlier bool) {
+	var (
+		t  *wheelTimer
+		ok bool
+	)
+	if t, ok = tw.timers[key]; ok {
+		if earlier && t.at <= at {
+			return
+		}
+		tw.unplace(t)
+	} else {
+		t = &wheelTimer{key: key}
+		tw.timers[key] = t
+	}
+	if t.at = at; tw.started {
+		tw.place(t)
+	}
+}
+
+// cancel cancels expiration of `key` and returns
+// `true` when it was scheduled.
+func (tw *TimingWheel) cancel(key interface{}) bool {
+	var (
+		t  *wheelTimer
+		ok bool
+	)
+	if t, ok = tw.timers[key]; !ok {
+		return false
+	}
+	tw.unplace(t)
+	delete(tw.timers, key)
+	return true
+}
+
+// advance moves the wheel forward to `now` ( in
+// unix nanoseconds ) and returns due keys.
+func (tw *TimingWheel) advance(now int64) (due []interface{}) {
+	var (
+		target int64
+	)
+	tw.start(now)
+	for target = now / tw.tick; tw.cursor < target; {
+		if len(tw.timers) == 0 {
+			tw.cursor = target
+			break
+		}
+		if tw.counts[0] == 0 && !tw.skip(target) {
+			break
+		}
+		tw.cursor++
+		// cascade higher levels first so that timers
+		// may cascade again within the same tick
+		for l := wheelLEVELS - 1; l > 0; l-- {
+			if tw.cursor&(1<<(wheelBITS*l)-1) == 0 {
+				tw.cascade(tw.slots[l][(tw.cursor>>(wheelBITS*l))&(wheelSLOTS-1)])
+			}
+		}
+		if slot := tw.slots[0][tw.cursor&(wheelSLOTS-1)]; slot != nil {
+			for elem := slot.Front(); elem != nil; elem = slot.Front() {
+				t := elem.Value.(*wheelTimer)
+				tw.unplace(t)
+				delete(tw.timers, t.key)
+				due = append(due, t.key)
+			}
+		}
+	}
+	return due
+}
+
+// skip moves the cursor right before the next tick
+// cascading the lowest non-empty level, as nothing
+// fires until then, and returns `false` when that
+// tick is beyond `target`.
+func (tw *TimingWheel) skip(target int64) bool {
+	var (
+		level int = 1
+		span  int64
+		next  int64
+	)
+	for tw.counts[level] == 0 {
+		level++
+	}
+	span = 1 << (wheelBITS * level)
+	if next = (tw.cursor/span + 1) * span; next > target {
+		tw.cursor = target
+		return false
+	}
+	tw.cursor = next - 1
+	return true
+}
+
+// reset cancels all expirations.
+func (tw *TimingWheel) reset() {
+	for key, t := range tw.timers {
+		tw.unplace(t)
+		delete(tw.timers, key)
+	}
+}
+
+// start sets the cursor to `now` on first use and
+// places timers scheduled before.
+func (tw *TimingWheel) start(now int64) {
+	if tw.started {
+		return
+	}
+	tw.cursor, tw.started = now/tw.tick, true
+	for _, t := range tw.timers {
+		tw.place(t)
+	}
+}
+
+// cascade places timers of `slot` again.
+func (tw *TimingWheel) cascade(slot *list.List) {
+	if slot == nil {
+		return
+	}
+	for elem := slot.Front(); elem != nil; elem = slot.Front() {
+		t := elem.Value.(*wheelTimer)
+		tw.unplace(t)
+		tw.place(t)
+	}
+}
+
+// place links `t` into the slot of its deadline.
+// Overdue timers fire on the next tick and those
+// beyond the span of the wheel are parked in the
+// farthest slot until they cascade.
+func (tw *TimingWheel) place(t *wheelTimer) {
+	var (
+		expiry int64 = (t.at + tw.tick - 1) / tw.tick
+		delta  int64 = expiry - tw.cursor
+		level  int
+	)
+	switch {
+	case delta <= 0:
+		expiry, delta = tw.cursor+1, 1
+	case delta >= wheelSPAN:
+		expiry, delta = tw.cursor+wheelSPAN-1, wheelSPAN-1
+	}
+	for delta >= 1<<(wheelBITS*(level+1)) {
+		level++
+	}
+	slot := &tw.slots[level][(expiry>>(wheelBITS*level))&(wheelSLOTS-1)]
+	if *slot == nil {
+		*slot = list.New()
+	}
+	t.level, t.slot, t.elem = level, *slot, (*slot).PushBack(t)
+	tw.counts[level]++
+}
+
+// unplace unlinks `t` from its slot, if placed.
+func (tw *TimingWheel) unplace(t *wheelTimer) {
+	if t.slot == nil {
+		return
+	}
+	t.slot.Remove(t.elem)
+	tw.counts[t.level]--
+	t.slot, t.elem = nil, nil
+}
+
+// - MARK: LRU section.
+
+// scheduleExpiry schedules expiration of `item` in
+// the configured timing wheel, unless it's scheduled
+// no later already; timers of enteries whose expiry
+// was extended meanwhile are rescheduled when they
+// fire. The expiry queue, if any, is kept exact (
+// see `WithExpiryQueue` ). Note, this routine is not
+// protected against concurrent accesses; therefore
+// not publicly exposed.
+func (lru *LRU) scheduleExpiry(item *LRUItem) {
+	if lru.opts.wheel != nil && item.expires != 0 {
+		if !lru.opts.wheel.started {
+			// the cursor starts at the current time
+			lru.opts.wheel.start(lru.now())
+		}
+		lru.opts.wheel.schedule(item.Key, item.expires, true)
+	}
+	if lru.opts.expiries != nil {
+		lru.queueExpiry(item)
+	}
+}
+
+// reapWheel removes enteries whose expiration is due
+// in the timing wheel and returns their number. Note,
+// this routine is not protected against concurrent
+// accesses; therefore not publicly exposed.
+func (lru *LRU) reapWheel(now int64) (n int) {
+	var (
+		elem *list.Element
+		item *LRUItem
+		ok   bool
+	)
+	for _, key := range lru.opts.wheel.advance(now) {
+		if elem, ok = lru.lookup[key]; !ok {
+			continue
+		}
+		switch item = elem.Value.(*LRUItem); {
+		case item.expires == 0:
+		case !item.expired(now):
+			lru.opts.wheel.schedule(key, item.expires, false)
+		case lru.opts.loader != nil && lru.inGrace(item, now):
+			lru.opts.wheel.schedule(key, item.expires+int64(lru.opts.grace), false)
+		default:
+			lru.unlink(elem, lru.expiredReason(item))
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+/* MIT License
+* 
+* Copyright (c) 2018 Mike Taghavi <mitghi[at]gmail.com>
+* 
+* Permission is hereby granted, free of charge, to any person obtaining a copy
+* of this software and associated documentation files (the "Software"), to deal
+* in the Software without restriction, including without limitation the rights
+* to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+* copies of the Software, and to permit persons to whom the Software is
+* furnished to do so, subject to the following conditions:
+* The above copyright notice and this permission notice shall be included in all
+* copies or substantial portions of the Software.
+* 
+* THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+* IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+* FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+* AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+* LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+* OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+* SOFTWARE.
+*/
+
+package cache
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestTimingWheel(t *testing.T) {
+	var (
+		tw    *TimingWheel              = NewTimingWheel(time.Millisecond)
+		rnd   *rand.Rand                = rand.New(rand.NewSource(1))
+		start time.Time                 = time.Unix(1000, 0)
+		want  map[interface{}]time.Time = make(map[interface{}]time.Time)
+	)
+	tw.Advance(start)
+	for i := 0; i < 2000; i++ {
+		at := start.Add(time.Duration(rnd.Int63n(int64(10 * time.Minute))))
+		tw.Schedule(i, at)
+		want[i] = at
+	}
+	// beyond the span of all levels
+	tw.Schedule("far", start.Add(10*24*time.Hour))
+	want["far"] = start.Add(10 * 24 * time.Hour)
+	tw.Schedule(0, start.Add(time.Hour))
+	want[0] = start.Add(time.Hour)
+	if !tw.Cancel(1) || tw.Cancel(1) {
+		t.Fatal("assertion failed, inconsistent state. expected equal.")
+	}
+	delete(want, 1)
+	for now := start; len(want) > 0; now = now.Add(time.Duration(rnd.Int63n(int64(time.Minute)))) {
+		for _, key := range tw.Advance(now) {
+			at, ok := want[key]
+			if !ok || at.After(now) {
+				t.Fatalf("assertion failed, expected key(%v) due at (%v) not to fire at (%v).", key, at, now)
+			}
+			delete(want, key)
+		}
+		for key, at := range want {
+			if now.Sub(at) >= time.Millisecond {
+				t.Fatalf("assertion failed, expected key(%v) due at (%v) to fire by (%v).", key, at, now)
+			}
+		}
+	}
+	if tw.Len() != 0 {
+		t.Fatalf("assertion failed, expected equal with value(0) - got value(%d).", tw.Len())
+	}
+}
+
+func TestLRUTimingWheel(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(0, 0)}
+		lru   *LRU         = NewLRU(8, WithClock(clock), WithTimingWheel(time.Millisecond), WithExpireAfterAccess(2*time.Second))
+	)
+	lru.SetWithTTL("a", 1, time.Second)
+	lru.SetWithTTL("b", 2, 3*time.Second)
+	lru.Set("c", 3)
+	lru.SetWithTTL("d", 4, 0)
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	lru.Get("c")
+	if n := lru.ReapExpired(); n != 1 || lru.Read("a") != nil {
+		t.Fatalf("assertion failed, expected equal with value(1) - got value(%d).", n)
+	}
+	// "c" slid past its first deadline
+	clock.now = clock.now.Add(time.Second)
+	if n := lru.ReapExpired(); n != 0 || lru.opts.wheel.Len() != 2 {
+		t.Fatalf("assertion failed, expected equal with value(0) - got value(%d).", n)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if n := lru.ReapExpired(); n != 2 || lru.Len() != 1 || lru.opts.wheel.Len() != 0 {
+		t.Fatalf("assertion failed, expected equal with value(2) - got value(%d).", n)
+	}
+	lru.SetWithTTL("e", 5, time.Second)
+	lru.Remove("e")
+	if lru.opts.wheel.Len() != 0 {
+		t.Fatal("assertion failed, expected removed entery to be unscheduled.")
+	}
+}
+
+func TestTimingWheelMixedTTLs(t *testing.T) {
+	var (
+		clock *manualClock = &manualClock{time.Unix(1000, 0)}
+		lru   *LRU         = NewLRU(16, WithClock(clock), WithTimingWheel(time.Millisecond))
+		tw    *TimingWheel = NewTimingWheel(time.Millisecond)
+		start time.Time    = time.Unix(1000, 0)
+	)
+	// the longer ttl is scheduled first
+	lru.SetWithTTL("far", 1, time.Hour)
+	lru.SetWithTTL("near", 2, time.Second)
+	clock.now = clock.now.Add(2 * time.Second)
+	if n := lru.ReapExpired(); n != 1 || lru.Len() != 1 {
+		t.Fatalf("assertion failed, expected equal with value(1) - got value(%d).", n)
+	}
+	tw.Schedule("far", start.Add(time.Hour))
+	tw.Schedule("near", start.Add(time.Second))
+	tw.Advance(start)
+	if due := tw.Advance(start.Add(2 * time.Second)); len(due) != 1 || due[0] != "near" {
+		t.Fatal("assertion failed, inconsistent state. expected equal.", due)
+	}
+}